@@ -3,6 +3,12 @@
 
 package audio
 
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
 // AudioStreamContainerFormat defines supported audio stream container format.
 type AudioStreamContainerFormat int //nolint:revive
 
@@ -32,6 +38,38 @@ const (
 	ANY AudioStreamContainerFormat = 0x108
 )
 
+var containerFormatsByName = map[string]AudioStreamContainerFormat{
+	"ogg":   OGGOPUS,
+	"opus":  OGGOPUS,
+	"mp3":   MP3,
+	"flac":  FLAC,
+	"alaw":  ALAW,
+	"mulaw": MULAW,
+	"amr":   AMRNB,
+	"any":   ANY,
+}
+
+// ParseContainerFormat returns the container format of a name: ogg or opus, mp3, flac, alaw, mulaw, amr or any,
+// ignoring case.
+func ParseContainerFormat(name string) (AudioStreamContainerFormat, error) {
+	format, ok := containerFormatsByName[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown audio container format %q", name)
+	}
+	return format, nil
+}
+
+// ContainerFormatFromFileName returns the container format matching the extension of a compressed audio file name,
+// e.g. MP3 for song.mp3. It returns false for other extensions, including .wav.
+func ContainerFormatFromFileName(name string) (AudioStreamContainerFormat, bool) {
+	ext := strings.TrimPrefix(filepath.Ext(name), ".")
+	if strings.EqualFold(ext, "any") {
+		return 0, false
+	}
+	format, err := ParseContainerFormat(ext)
+	return format, err == nil
+}
+
 // AudioStreamWaveFormat represents the format specified inside WAV container which are sent directly as encoded to the speech service.
 type AudioStreamWaveFormat int //nolint:revive
 
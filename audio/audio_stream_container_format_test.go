@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package audio
+
+import (
+	"testing"
+)
+
+func TestContainerFormatFromFileName(t *testing.T) {
+	tests := []struct {
+		name   string
+		format AudioStreamContainerFormat
+		ok     bool
+	}{
+		{"song.mp3", MP3, true},
+		{"dir.v2/call.OPUS", OGGOPUS, true},
+		{"call.ogg", OGGOPUS, true},
+		{"call.flac", FLAC, true},
+		{"call.mulaw", MULAW, true},
+		{"voicemail.amr", AMRNB, true},
+		{"call.wav", 0, false},
+		{"call.any", 0, false},
+		{"call", 0, false},
+	}
+	for _, test := range tests {
+		format, ok := ContainerFormatFromFileName(test.name)
+		if format != test.format || ok != test.ok {
+			t.Error("Unexpected container format of ", test.name, ": ", format, ok)
+		}
+	}
+	if format, err := ParseContainerFormat("Any"); format != ANY || err != nil {
+		t.Error("Unexpected container format: ", format, err)
+	}
+	if _, err := ParseContainerFormat("wav"); err == nil {
+		t.Error("Expected an error parsing wav")
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package batch
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/limit"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// Default options.
+const (
+	DefaultConcurrency = 4
+	DefaultMaxAttempts = 3
+	DefaultRetryDelay  = time.Second
+)
+
+// Job is an audio file to transcribe and its recognition settings.
+type Job struct {
+	// Path is the path of the file. Files with the .wav extension are read as WAV files, others are streamed with the
+	// compressed format of their extension (see audio.ContainerFormatFromFileName), or any format otherwise.
+	Path string
+
+	// Language is the recognition language of the file, instead of the language of the SpeechConfig.
+	Language string
+
+	// AutoDetectLanguages, if not empty, are the candidate languages of the file, one of which is identified by the
+	// service. Set the language identification mode of the SpeechConfig to ContinuousLanguageID for files switching
+	// languages.
+	AutoDetectLanguages []string
+}
+
+// Files creates jobs for the files at paths, recognized in the language of the SpeechConfig.
+func Files(paths ...string) []Job {
+	jobs := make([]Job, len(paths))
+	for i, path := range paths {
+		jobs[i].Path = path
+	}
+	return jobs
+}
+
+// Phrase is a recognized phrase of a file.
+type Phrase struct {
+	Text     string
+	Offset   time.Duration
+	Duration time.Duration
+
+	// Language is the identified language of the phrase, when the job has AutoDetectLanguages.
+	Language string
+}
+
+// Result is the transcript of a file.
+type Result struct {
+	Job Job
+
+	// Phrases are the recognized phrases, in order.
+	Phrases []Phrase
+
+	// Text is the text of the phrases, separated by spaces.
+	Text string
+
+	// Attempts is the number of recognitions of the file, more than one when transient errors were retried.
+	Attempts int
+
+	// Elapsed is the time spent transcribing the file, including the retries.
+	Elapsed time.Duration
+
+	// Err is the error of the last attempt, e.g. a *common.CancellationError. Phrases are then empty.
+	Err error
+}
+
+// Stage is the stage of a job reported to the progress callback.
+type Stage int
+
+const (
+	// Started is reported when the first attempt of a job starts.
+	Started Stage = iota
+
+	// Retrying is reported when an attempt failed with a transient error and the job is retried after a delay.
+	Retrying
+
+	// Finished is reported when a job succeeded or failed for good.
+	Finished
+)
+
+func (stage Stage) String() string {
+	switch stage {
+	case Started:
+		return "Started"
+	case Retrying:
+		return "Retrying"
+	case Finished:
+		return "Finished"
+	}
+	return "Unknown"
+}
+
+// Progress reports the progress of a job.
+type Progress struct {
+	Stage Stage
+
+	// Index is the index of the job in the jobs passed to Transcribe.
+	Index int
+	Job   Job
+
+	// Attempt is the number of the attempt, starting at 1. When retrying, it is the number of the failed attempt.
+	Attempt int
+
+	// Err is the error of the failed attempt when retrying, and the final error when finished.
+	Err error
+
+	// Completed is the number of jobs finished so far, out of Total.
+	Completed int
+	Total     int
+}
+
+// Options configure a Transcriber. Zero values select the defaults.
+type Options struct {
+	// Concurrency is the maximum number of files recognized at the same time, DefaultConcurrency by default. Keep it
+	// within the concurrent request quota of the resource.
+	Concurrency int
+
+	// MaxAttempts is the maximum number of recognitions of a file, DefaultMaxAttempts by default. Set it to 1 to
+	// disable retries.
+	MaxAttempts int
+
+	// RetryDelay is the delay before the first retry of a file, DefaultRetryDelay by default. It doubles for each
+	// following retry.
+	RetryDelay time.Duration
+
+	// Timeout, if not zero, limits the duration of each attempt. Attempts timing out are retried.
+	Timeout time.Duration
+
+	// Progress, if not nil, is called as jobs start, are retried and finish. Calls are serialized.
+	Progress func(progress Progress)
+
+	// Limiter, if not nil, limits the attempts with the limits of LimitKey, limit.KeyOf(config) by default, shared with
+	// the other users of the limiter. Attempts throttled by the service back off its following sessions.
+	Limiter  *limit.Limiter
+	LimitKey string
+}
+
+type recognizeFunc func(ctx context.Context, job Job) ([]Phrase, error)
+
+// Transcriber transcribes files concurrently with a shared SpeechConfig.
+type Transcriber struct {
+	options   Options
+	recognize recognizeFunc
+}
+
+// New creates a transcriber recognizing files with config, which must stay open while the transcriber is used. The
+// config can also be the config of an EmbeddedSpeechConfig, see EmbeddedSpeechConfig.GetSpeechConfig.
+func New(config *speech.SpeechConfig, options Options) *Transcriber {
+	if options.Limiter != nil && options.LimitKey == "" {
+		options.LimitKey = limit.KeyOf(config)
+	}
+	return newTranscriber(func(ctx context.Context, job Job) ([]Phrase, error) {
+		return recognizeFile(ctx, config, job)
+	}, options)
+}
+
+func newTranscriber(recognize recognizeFunc, options Options) *Transcriber {
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultConcurrency
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+	if options.RetryDelay <= 0 {
+		options.RetryDelay = DefaultRetryDelay
+	}
+	return &Transcriber{options: options, recognize: recognize}
+}
+
+// Transcribe transcribes the files of the jobs and returns their results in the same order. It returns when all the
+// jobs are finished; when the context is done, the remaining jobs fail with the error of the context.
+func (transcriber *Transcriber) Transcribe(ctx context.Context, jobs []Job) []Result {
+	results := make([]Result, len(jobs))
+	var mu sync.Mutex
+	completed := 0
+	report := func(progress Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		if progress.Stage == Finished {
+			completed++
+		}
+		progress.Completed, progress.Total = completed, len(jobs)
+		if transcriber.options.Progress != nil {
+			transcriber.options.Progress(progress)
+		}
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	workers := transcriber.options.Concurrency
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = transcriber.transcribe(ctx, index, jobs[index], report)
+			}
+		}()
+	}
+	for index := range jobs {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+func (transcriber *Transcriber) transcribe(ctx context.Context, index int, job Job, report func(Progress)) Result {
+	start := time.Now()
+	result := Result{Job: job}
+	report(Progress{Stage: Started, Index: index, Job: job, Attempt: 1})
+	delay := transcriber.options.RetryDelay
+	for {
+		if err := ctx.Err(); err != nil {
+			result.Err = err
+			break
+		}
+		result.Attempts++
+		result.Phrases, result.Err = transcriber.attempt(ctx, job)
+		if result.Err == nil || result.Attempts >= transcriber.options.MaxAttempts || !common.IsRetryable(result.Err) || ctx.Err() != nil {
+			break
+		}
+		report(Progress{Stage: Retrying, Index: index, Job: job, Attempt: result.Attempts, Err: result.Err})
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+		delay *= 2
+	}
+	if result.Err != nil {
+		result.Phrases = nil
+	}
+	texts := make([]string, len(result.Phrases))
+	for i, phrase := range result.Phrases {
+		texts[i] = phrase.Text
+	}
+	result.Text = strings.Join(texts, " ")
+	result.Elapsed = time.Since(start)
+	report(Progress{Stage: Finished, Index: index, Job: job, Attempt: result.Attempts, Err: result.Err})
+	return result
+}
+
+func (transcriber *Transcriber) attempt(ctx context.Context, job Job) (phrases []Phrase, err error) {
+	if limiter := transcriber.options.Limiter; limiter != nil {
+		// The wait for the limiter does not count in the attempt timeout.
+		permit, acquireErr := limiter.Acquire(ctx, transcriber.options.LimitKey, limit.Request{})
+		if acquireErr != nil {
+			return nil, acquireErr
+		}
+		defer func() { permit.Release(err) }()
+	}
+	if transcriber.options.Timeout <= 0 {
+		return transcriber.recognize(ctx, job)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, transcriber.options.Timeout)
+	defer cancel()
+	phrases, err = transcriber.recognize(attemptCtx, job)
+	if err != nil && ctx.Err() == nil && attemptCtx.Err() == context.DeadlineExceeded {
+		// Attempt timeouts are transient, unlike the cancellation of the batch.
+		err = common.ErrTimeout
+	}
+	return phrases, err
+}
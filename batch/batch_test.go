@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/limit"
+)
+
+func TestTranscribeBoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	recognize := func(ctx context.Context, job Job) ([]Phrase, error) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return []Phrase{{Text: "hello", Offset: time.Second}, {Text: job.Path}}, nil
+	}
+	var progress []Progress
+	transcriber := newTranscriber(recognize, Options{Concurrency: 3, Progress: func(p Progress) {
+		progress = append(progress, p)
+	}})
+	results := transcriber.Transcribe(context.Background(), Files("a", "b", "c", "d", "e", "f", "g"))
+	if peak != 3 {
+		t.Errorf("peak concurrency = %d, want 3", peak)
+	}
+	for i, result := range results {
+		if result.Err != nil || result.Attempts != 1 || result.Job.Path != string(rune('a'+i)) || result.Text != "hello "+result.Job.Path {
+			t.Errorf("results[%d] = %+v", i, result)
+		}
+	}
+	if len(progress) != 14 {
+		t.Fatalf("progress calls = %d, want 14", len(progress))
+	}
+	last := progress[len(progress)-1]
+	if last.Stage != Finished || last.Completed != 7 || last.Total != 7 {
+		t.Errorf("last progress = %+v", last)
+	}
+}
+
+func TestTranscribeRetriesTransientErrors(t *testing.T) {
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	recognize := func(ctx context.Context, job Job) ([]Phrase, error) {
+		mu.Lock()
+		attempts[job.Path]++
+		attempt := attempts[job.Path]
+		mu.Unlock()
+		switch {
+		case job.Path == "throttled" && attempt < 3:
+			return nil, &common.CancellationError{Reason: common.Error, ErrorCode: common.TooManyRequests}
+		case job.Path == "forbidden":
+			return nil, &common.CancellationError{Reason: common.Error, ErrorCode: common.Forbidden}
+		case job.Path == "slow" && attempt == 1:
+			<-ctx.Done()
+			return nil, ctx.Err()
+		case job.Path == "down":
+			return nil, &common.CancellationError{Reason: common.Error, ErrorCode: common.ServiceUnavailable}
+		}
+		return []Phrase{{Text: job.Path, Language: job.Language}}, nil
+	}
+	var retries []Progress
+	transcriber := newTranscriber(recognize, Options{
+		RetryDelay: time.Millisecond,
+		Timeout:    20 * time.Millisecond,
+		Progress: func(p Progress) {
+			if p.Stage == Retrying {
+				retries = append(retries, p)
+			}
+		},
+	})
+	jobs := []Job{{Path: "throttled"}, {Path: "forbidden"}, {Path: "slow"}, {Path: "down"}, {Path: "ok", Language: "de-DE"}}
+	results := transcriber.Transcribe(context.Background(), jobs)
+	if results[0].Err != nil || results[0].Attempts != 3 || results[0].Text != "throttled" {
+		t.Errorf("throttled = %+v", results[0])
+	}
+	var canceled *common.CancellationError
+	if !errors.As(results[1].Err, &canceled) || canceled.ErrorCode != common.Forbidden || results[1].Attempts != 1 {
+		t.Errorf("forbidden = %+v, want no retry", results[1])
+	}
+	if results[2].Err != nil || results[2].Attempts != 2 {
+		t.Errorf("slow = %+v, want a retry after the timeout", results[2])
+	}
+	if !common.IsRetryable(results[3].Err) || results[3].Attempts != DefaultMaxAttempts || results[3].Phrases != nil {
+		t.Errorf("down = %+v, want %d attempts", results[3], DefaultMaxAttempts)
+	}
+	if results[4].Phrases[0].Language != "de-DE" {
+		t.Errorf("ok = %+v", results[4])
+	}
+	if len(retries) != 5 {
+		t.Errorf("retries = %d, want 5", len(retries))
+	}
+}
+
+func TestTranscribeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	recognize := func(ctx context.Context, job Job) ([]Phrase, error) {
+		cancel()
+		return nil, &common.CancellationError{Reason: common.Error, ErrorCode: common.ConnectionFailure}
+	}
+	results := newTranscriber(recognize, Options{Concurrency: 1}).Transcribe(ctx, Files("a", "b"))
+	if results[0].Attempts != 1 || results[1].Attempts != 0 {
+		t.Errorf("attempts = %d, %d, want 1, 0", results[0].Attempts, results[1].Attempts)
+	}
+	if !errors.Is(results[1].Err, context.Canceled) {
+		t.Errorf("results[1].Err = %v, want context.Canceled", results[1].Err)
+	}
+}
+
+func TestTranscribeLimiter(t *testing.T) {
+	var mu sync.Mutex
+	running, peak, throttled := 0, 0, false
+	recognize := func(ctx context.Context, job Job) ([]Phrase, error) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		first := !throttled
+		throttled = true
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if first {
+			return nil, &common.CancellationError{Reason: common.Error, ErrorCode: common.TooManyRequests}
+		}
+		return []Phrase{{Text: job.Path}}, nil
+	}
+	limiter := limit.New(limit.Options{MaxSessions: 2, MinBackoff: 10 * time.Millisecond})
+	transcriber := newTranscriber(recognize, Options{
+		Concurrency: 4,
+		RetryDelay:  time.Millisecond,
+		Limiter:     limiter,
+		LimitKey:    "westus",
+	})
+	results := transcriber.Transcribe(context.Background(), Files("a", "b", "c", "d", "e"))
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("results[%d].Err = %v", i, result.Err)
+		}
+	}
+	if peak == 0 || peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most the 2 sessions of the limiter", peak)
+	}
+	stats := limiter.Stats("westus")
+	if stats.Acquired != 6 || stats.Throttled != 1 || stats.Active != 0 {
+		t.Errorf("limiter stats = %+v, want 6 sessions with 1 throttled", stats)
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package batch transcribes many audio files with a bounded number of concurrent recognizers sharing a SpeechConfig.
+//
+// Each file is recognized with a continuous recognition until the end of the audio, collecting its phrases with their
+// timings. WAV files are read directly; other files are streamed with the compressed format of their extension (see
+// audio.GetCompressedFormat). The language can be set per file, or identified among candidate languages. Attempts
+// canceled by transient errors, such as throttling or connection failures, are retried with an exponential backoff.
+// A limit.Limiter shared with other recognizers and synthesizers of the resource can further limit the attempts.
+//
+//	transcriber := batch.New(config, batch.Options{Concurrency: 8})
+//	for _, result := range transcriber.Transcribe(ctx, batch.Files(paths...)) {
+//		if result.Err != nil {
+//			log.Println(result.Job.Path, result.Err)
+//			continue
+//		}
+//		fmt.Println(result.Job.Path, result.Text)
+//	}
+package batch
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package batch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recognition"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+func newRecognizer(config *speech.SpeechConfig, job Job, audioConfig *audio.AudioConfig) (*speech.SpeechRecognizer, error) {
+	switch {
+	case len(job.AutoDetectLanguages) > 0:
+		languageConfig, err := speech.NewAutoDetectSourceLanguageConfigFromLanguages(job.AutoDetectLanguages)
+		if err != nil {
+			return nil, err
+		}
+		defer languageConfig.Close()
+		return speech.NewSpeechRecognizerFromAutoDetectSourceLangConfig(config, languageConfig, audioConfig)
+	case job.Language != "":
+		return speech.NewSpeechRecognizerFromSourceLanguage(config, job.Language, audioConfig)
+	}
+	return speech.NewSpeechRecognizerFromConfig(config, audioConfig)
+}
+
+// recognizeFile runs a continuous recognition of the file of the job until the end of its audio.
+func recognizeFile(ctx context.Context, config *speech.SpeechConfig, job Job) ([]Phrase, error) {
+	input, err := recognition.OpenFile(job.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	recognizer, err := newRecognizer(config, job, input.Config)
+	if err != nil {
+		return nil, err
+	}
+	defer recognizer.Close()
+
+	var mu sync.Mutex
+	var phrases []Phrase
+	err = recognition.Recognize(ctx, recognizer, input, func(result *speech.SpeechRecognitionResult) error {
+		phrase := Phrase{Text: result.Text, Offset: result.Offset, Duration: result.Duration}
+		if len(job.AutoDetectLanguages) > 0 && result.Properties != nil {
+			phrase.Language = result.Properties.GetProperty(common.SpeechServiceConnectionAutoDetectSourceLanguageResult, "")
+		}
+		mu.Lock()
+		phrases = append(phrases, phrase)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return phrases, nil
+}
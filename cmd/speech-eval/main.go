@@ -0,0 +1,170 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Command speech-eval recognizes a directory of WAV files and scores the results against the reference .txt files
+// next to them, reporting the word and character error rates.
+//
+// Usage:
+//
+//	speech-eval [flags] <directory>
+//
+// The service and the credentials are read from the configuration file given with -config or the
+// SPEECH_CONFIG_FILE environment variable, and from the SPEECH_SUBSCRIPTION_KEY, SPEECH_SUBSCRIPTION_REGION and
+// related environment variables (see speech.LoadConfig). With -embedded, the embedded models of the configuration or
+// of the EMBEDDED_MODELS_DIR directory are used instead.
+//
+// The exit code is 0 when the thresholds are met, 1 when they are exceeded or files failed, and 2 for invalid
+// arguments or configurations.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/eval"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+const (
+	exitThresholds = 1
+	exitUsage      = 2
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	flags := flag.NewFlagSet("speech-eval", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: speech-eval [flags] <directory>")
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config", "", "JSON or YAML configuration `file`")
+	language := flags.String("language", "", "recognition language, e.g. en-US")
+	embedded := flags.Bool("embedded", false, "recognize with embedded models")
+	model := flags.String("model", "", "embedded recognition model `name`")
+	license := flags.String("license", os.Getenv("EMBEDDED_SPEECH_MODEL_LICENSE"), "license of the embedded recognition model")
+	timeout := flags.Duration("timeout", 5*time.Minute, "maximum recognition time per file")
+	jsonFile := flags.String("json", "", "write the report as JSON to `file`, - for standard output")
+	diffs := flags.Bool("diff", false, "print the word diff of each file with errors")
+	var thresholds eval.Thresholds
+	flags.Float64Var(&thresholds.MaxWER, "max-wer", 0, "maximum total word error rate, e.g. 0.15; 0 to disable")
+	flags.Float64Var(&thresholds.MaxCER, "max-cer", 0, "maximum total character error rate; 0 to disable")
+	flags.Float64Var(&thresholds.MaxFileWER, "max-file-wer", 0, "maximum word error rate of each file; 0 to disable")
+	flags.BoolVar(&thresholds.AllowFailures, "allow-failures", false, "do not fail when files cannot be recognized")
+	var normalizer eval.Normalizer
+	flags.BoolVar(&normalizer.KeepCase, "keep-case", false, "do not lowercase the texts")
+	flags.BoolVar(&normalizer.KeepPunctuation, "keep-punctuation", false, "do not remove punctuation")
+	flags.BoolVar(&normalizer.KeepNumbers, "keep-numbers", false, "do not spell out numbers, e.g. for other languages than English")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	samples, err := eval.LoadSamples(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "speech-eval:", err)
+		return exitUsage
+	}
+	settings, err := speech.LoadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "speech-eval:", err)
+		return exitUsage
+	}
+	if *language != "" {
+		settings.Language = *language
+	}
+
+	var recognize eval.Recognizer
+	if *embedded {
+		if *model != "" {
+			if settings.Embedded == nil {
+				settings.Embedded = new(speech.EmbeddedSettings)
+			}
+			settings.Embedded.RecognitionModel = &speech.ModelSettings{Name: *model, License: *license}
+		}
+		config, err := settings.EmbeddedConfig()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "speech-eval:", err)
+			return exitUsage
+		}
+		defer config.Close()
+		recognize = eval.NewEmbeddedRecognizer(config)
+	} else {
+		config, err := settings.SpeechConfig()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "speech-eval:", err)
+			return exitUsage
+		}
+		defer config.Close()
+		recognize = eval.NewRecognizer(config)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	report, err := eval.Run(ctx, samples, func(ctx context.Context, path string) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, *timeout)
+		defer cancel()
+		fmt.Fprintln(os.Stderr, "Recognizing", path)
+		return recognize(ctx, path)
+	}, normalizer)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "speech-eval:", err)
+		return exitThresholds
+	}
+
+	// Keep standard output for the JSON report when requested there.
+	text := os.Stdout
+	if *jsonFile == "-" {
+		text = os.Stderr
+	}
+	if err = report.WriteText(text, *diffs); err != nil {
+		fmt.Fprintln(os.Stderr, "speech-eval:", err)
+		return exitUsage
+	}
+	if *jsonFile != "" {
+		if err = writeJSON(report, *jsonFile); err != nil {
+			fmt.Fprintln(os.Stderr, "speech-eval:", err)
+			return exitUsage
+		}
+	}
+	if err = report.Check(thresholds); err != nil {
+		fmt.Fprintln(os.Stderr, "speech-eval:", err)
+		return exitThresholds
+	}
+	return 0
+}
+
+func writeJSON(report *eval.Report, path string) error {
+	if path == "-" {
+		return report.WriteJSON(os.Stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = report.WriteJSON(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Command speech-server serves speech recognition, translation and synthesis over HTTP (see the server package).
+//
+// Usage:
+//
+//	speech-server [flags]
+//
+// The service and the credentials are read from the configuration file given with -config or the
+// SPEECH_CONFIG_FILE environment variable, and from the SPEECH_SUBSCRIPTION_KEY, SPEECH_SUBSCRIPTION_REGION and
+// related environment variables (see speech.LoadConfig). The server stops gracefully when interrupted.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/limit"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/server"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+const exitUsage = 2
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	flags := flag.NewFlagSet("speech-server", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: speech-server [flags]")
+		flags.PrintDefaults()
+	}
+	addr := flags.String("addr", ":8080", "listen `address`")
+	configFile := flags.String("config", "", "JSON or YAML configuration `file`")
+	language := flags.String("language", "", "default recognition language, e.g. en-US")
+	voice := flags.String("voice", "", "synthesis voice `name`")
+	format := flags.String("format", "", "synthesis output `format`, e.g. audio-24khz-96kbitrate-mono-mp3")
+	var options server.Options
+	flags.IntVar(&options.MaxSessions, "max-sessions", server.DefaultMaxSessions, "maximum number of concurrent requests")
+	flags.IntVar(&options.PreparedRecognizers, "prepared", server.DefaultPreparedRecognizers, "number of recognizers prepared for the next requests; -1 for none")
+	flags.DurationVar(&options.Timeout, "timeout", server.DefaultTimeout, "maximum duration of a request")
+	flags.Int64Var(&options.MaxBodySize, "max-body", server.DefaultMaxBodySize, "maximum size of a request body, in bytes")
+	var limits limit.Options
+	flags.Float64Var(&limits.ConnectionRate, "connection-rate", 0, "maximum number of new sessions per second; 0 for no limit")
+	flags.Float64Var(&limits.CharacterRate, "character-rate", 0, "maximum number of synthesized characters per second; 0 for no limit")
+	origins := flags.String("origins", "", "comma-separated `origins` of the pages allowed to stream, e.g. https://example.com; * for any")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	settings, err := speech.LoadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "speech-server:", err)
+		return exitUsage
+	}
+	if *language != "" {
+		settings.Language = *language
+	}
+	if *voice != "" {
+		settings.Voice = *voice
+	}
+	if *format != "" {
+		settings.SynthesisOutputFormat = *format
+	}
+	if *origins != "" {
+		options.CheckOrigin = allowOrigins(strings.Split(*origins, ","))
+	}
+	if limits.ConnectionRate > 0 || limits.CharacterRate > 0 {
+		options.Limiter = limit.New(limits)
+	}
+	handler, err := server.New(settings, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "speech-server:", err)
+		return exitUsage
+	}
+	defer handler.Close()
+
+	httpServer := &http.Server{Addr: *addr, Handler: handler}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		<-interrupts
+		signal.Stop(interrupts)
+		log.Println("speech-server: shutting down")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			log.Println("speech-server:", err)
+		}
+	}()
+	log.Println("speech-server: listening on", *addr)
+	if err = httpServer.ListenAndServe(); err != http.ErrServerClosed {
+		log.Println("speech-server:", err)
+		return 1
+	}
+	<-stopped
+	return 0
+}
+
+// allowOrigins allows streams from the given origins, from any with "*", and from clients other than browsers, which
+// send no Origin header.
+func allowOrigins(origins []string) func(*http.Request) bool {
+	allowed := make(map[string]bool)
+	for _, origin := range origins {
+		allowed[strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))] = true
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || allowed["*"] || allowed[strings.ToLower(origin)]
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// serviceFlags are the flags selecting the service, the credentials and the embedded models.
+type serviceFlags struct {
+	configFile string
+	key        string
+	region     string
+	endpoint   string
+	host       string
+	token      string
+	embedded   string
+	language   string
+}
+
+func addServiceFlags(flags *flag.FlagSet) *serviceFlags {
+	service := new(serviceFlags)
+	flags.StringVar(&service.configFile, "config", "", "JSON or YAML configuration `file`")
+	flags.StringVar(&service.key, "key", "", "subscription key of the Speech resource")
+	flags.StringVar(&service.region, "region", "", "region of the Speech resource, e.g. westus")
+	flags.StringVar(&service.endpoint, "endpoint", "", "service endpoint `URL`, instead of a region")
+	flags.StringVar(&service.host, "host", "", "service host `URL`, e.g. of a container")
+	flags.StringVar(&service.token, "token", "", "authorization token, instead of a key")
+	flags.StringVar(&service.embedded, "embedded", "", "use the embedded models of `directory`")
+	flags.StringVar(&service.language, "language", "", "language, e.g. en-US")
+	return service
+}
+
+// settings loads the configuration settings and overrides them with the flags.
+func (service *serviceFlags) settings() (*speech.ConfigSettings, error) {
+	settings, err := speech.LoadConfig(service.configFile)
+	if err != nil {
+		return nil, err
+	}
+	// The service is overridden as a whole, as with the environment variables.
+	if service.region != "" || service.endpoint != "" || service.host != "" {
+		settings.Region, settings.Endpoint, settings.Host = service.region, service.endpoint, service.host
+	}
+	if service.key != "" {
+		settings.Key = service.key
+	}
+	if service.token != "" {
+		settings.Token = service.token
+	}
+	if service.embedded != "" {
+		if settings.Embedded == nil {
+			settings.Embedded = new(speech.EmbeddedSettings)
+		}
+		settings.Embedded.ModelPaths = []string{service.embedded}
+	}
+	if service.language != "" {
+		settings.Language = service.language
+	}
+	return settings, nil
+}
+
+// isEmbedded checks whether the embedded models are used: when requested with -embedded, or when model paths are
+// configured and no service is.
+func (service *serviceFlags) isEmbedded(settings *speech.ConfigSettings) bool {
+	if settings.Embedded == nil || len(settings.Embedded.ModelPaths) == 0 {
+		return false
+	}
+	return service.embedded != "" || settings.Region == "" && settings.Endpoint == "" && settings.Host == ""
+}
+
+// embeddedSettings returns the embedded settings, creating them if needed.
+func embeddedSettings(settings *speech.ConfigSettings) *speech.EmbeddedSettings {
+	if settings.Embedded == nil {
+		settings.Embedded = new(speech.EmbeddedSettings)
+	}
+	return settings.Embedded
+}
+
+// speechConfig creates the speech config of the settings, or of their embedded models. The returned function closes
+// it.
+func (service *serviceFlags) speechConfig(settings *speech.ConfigSettings) (*speech.SpeechConfig, func(), error) {
+	if service.isEmbedded(settings) {
+		config, err := settings.EmbeddedConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		return config.GetSpeechConfig(), config.Close, nil
+	}
+	config, err := settings.SpeechConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, config.Close, nil
+}
+
+// modelFlags are the flags selecting an embedded model or voice.
+type modelFlags struct {
+	name    string
+	license string
+}
+
+func addModelFlags(flags *flag.FlagSet, kind string) *modelFlags {
+	model := new(modelFlags)
+	flags.StringVar(&model.name, "model", "", "embedded "+kind+" `name`")
+	flags.StringVar(&model.license, "license", os.Getenv("EMBEDDED_SPEECH_MODEL_LICENSE"), "license of the embedded "+kind)
+	return model
+}
+
+// apply sets the model, if any, keeping the license of the configured one when none is given.
+func (model *modelFlags) apply(settings **speech.ModelSettings) {
+	if model.name == "" {
+		return
+	}
+	license := model.license
+	if license == "" && *settings != nil {
+		license = (*settings).License
+	}
+	*settings = &speech.ModelSettings{Name: model.name, License: license}
+}
+
+// listFlag is a flag accepting comma-separated values, which can be repeated.
+type listFlag []string
+
+func (list *listFlag) String() string {
+	return strings.Join(*list, ",")
+}
+
+func (list *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*list = append(*list, item)
+		}
+	}
+	return nil
+}
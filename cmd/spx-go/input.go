@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recognition"
+)
+
+// audioFlags are the flags selecting the audio input.
+type audioFlags struct {
+	mic        bool
+	device     string
+	format     string
+	sampleRate uint
+}
+
+func addAudioFlags(flags *flag.FlagSet) *audioFlags {
+	input := new(audioFlags)
+	flags.BoolVar(&input.mic, "mic", false, "recognize from the microphone, until interrupted")
+	flags.StringVar(&input.device, "device", "", "microphone device `name`, instead of the default one")
+	flags.StringVar(&input.format, "format", "", "audio format of standard input or of files without a known extension: pcm, mp3, ogg, flac, alaw, mulaw, amr or any")
+	flags.UintVar(&input.sampleRate, "rate", 16000, "sample rate of 16-bit mono PCM input, in Hz")
+	return input
+}
+
+// continuousFlag is the -continuous flag of the commands recognizing either the first phrase or all of the input.
+type continuousFlag struct {
+	flags *flag.FlagSet
+	value *bool
+}
+
+func addContinuousFlag(flags *flag.FlagSet, usage string) continuousFlag {
+	return continuousFlag{flags: flags, value: flags.Bool("continuous", false, usage+" (default true with -mic or -device)")}
+}
+
+// enabled checks whether the input is recognized continuously: as set with -continuous, or else only for the
+// microphone, which is then recognized until interrupted.
+func (continuous continuousFlag) enabled(input *recognition.Input) bool {
+	set := false
+	continuous.flags.Visit(func(f *flag.Flag) {
+		set = set || f.Name == "continuous"
+	})
+	if set {
+		return *continuous.value
+	}
+	return input.Live
+}
+
+// open opens the microphone, standard input when path is -, or the file at path. WAV files are read directly, other
+// files and standard input are streamed.
+func (flags *audioFlags) open(path string) (*recognition.Input, error) {
+	if flags.mic || flags.device != "" {
+		if path != "" {
+			return nil, errors.New("a file cannot be recognized with -mic or -device")
+		}
+		return recognition.NewMicrophoneInput(flags.device)
+	}
+	if path == "" {
+		return nil, errors.New("an audio file, - for standard input, or -mic is required")
+	}
+	format := strings.ToLower(flags.format)
+	if format == "" && path != "-" && strings.EqualFold(filepath.Ext(path), ".wav") {
+		return recognition.OpenFile(path)
+	}
+
+	var streamFormat *audio.AudioStreamFormat
+	var err error
+	switch container, known := audio.ContainerFormatFromFileName(path); {
+	case format == "pcm" || format == "" && path == "-":
+		streamFormat, err = audio.GetWaveFormatPCM(uint32(flags.sampleRate), 16, 1)
+	case format != "":
+		if container, err = audio.ParseContainerFormat(format); err == nil {
+			streamFormat, err = audio.GetCompressedFormat(container)
+		}
+	case known:
+		streamFormat, err = audio.GetCompressedFormat(container)
+	default:
+		streamFormat, err = audio.GetCompressedFormat(audio.ANY)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var source io.ReadCloser = os.Stdin
+	if path != "-" {
+		if source, err = os.Open(path); err != nil {
+			streamFormat.Close()
+			return nil, err
+		}
+	}
+	return recognition.NewStreamInput(streamFormat, source)
+}
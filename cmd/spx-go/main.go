@@ -0,0 +1,161 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Command spx-go recognizes, synthesizes, translates and transcribes speech with the Speech Service or embedded
+// models.
+//
+// Usage:
+//
+//	spx-go <command> [flags] [file]
+//
+// The commands are:
+//
+//	recognize   recognize speech from a file, standard input or the microphone
+//	synthesize  synthesize text or SSML to an audio file
+//	translate   translate speech into one or more languages
+//	transcribe  transcribe a conversation with speaker diarization
+//	voices      list the synthesis voices
+//	models      list the embedded recognition and translation models
+//
+// Run spx-go <command> -h for the flags of a command. The service and the credentials are set with the -key,
+// -region, -endpoint, -host and -token flags, or read from the -config file and the environment variables of
+// speech.LoadConfig, such as SPEECH_SUBSCRIPTION_KEY and SPEECH_SUBSCRIPTION_REGION. Embedded models are used with
+// the -embedded flag, or when EMBEDDED_MODELS_DIR is set and no service is configured.
+//
+// The exit code is 0 on success, 1 when the operation failed or was canceled by the service, 2 for invalid arguments,
+// 3 when no speech was recognized and 130 when interrupted.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+// Exit codes.
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNoMatch     = 3
+	exitInterrupted = 130
+)
+
+var (
+	// errUsage is returned for invalid arguments, once they are reported.
+	errUsage = errors.New("invalid arguments")
+
+	// errNoMatch is returned when no speech was recognized.
+	errNoMatch = errors.New("no speech could be recognized")
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"recognize", "recognize speech from a file, standard input or the microphone", runRecognize},
+	{"synthesize", "synthesize text or SSML to an audio file", runSynthesize},
+	{"translate", "translate speech into one or more languages", runTranslate},
+	{"transcribe", "transcribe a conversation with speaker diarization", runTranscribe},
+	{"voices", "list the synthesis voices", runVoices},
+	{"models", "list the embedded recognition and translation models", runModels},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return exitUsage
+	}
+	if name := args[0]; name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		usage(os.Stdout)
+		return exitOK
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			interrupts := make(chan os.Signal, 1)
+			signal.Notify(interrupts, os.Interrupt)
+			defer signal.Stop(interrupts)
+			go func() {
+				select {
+				case <-interrupts:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+			return exitCode(ctx, cmd.name, cmd.run(ctx, args[1:]))
+		}
+	}
+	fmt.Fprintf(os.Stderr, "spx-go: unknown command %q\n", args[0])
+	usage(os.Stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: spx-go <command> [flags] [file]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run spx-go <command> -h for the flags of a command.")
+}
+
+// exitCode reports the error of a command, if any, and returns the exit code matching it.
+func exitCode(ctx context.Context, name string, err error) int {
+	switch {
+	case err == nil, err == flag.ErrHelp:
+		return exitOK
+	case err == errUsage:
+		return exitUsage
+	case ctx.Err() != nil:
+		fmt.Fprintf(os.Stderr, "spx-go %s: interrupted\n", name)
+		return exitInterrupted
+	}
+	fmt.Fprintf(os.Stderr, "spx-go %s: %v\n", name, err)
+	if errors.Is(err, errNoMatch) {
+		return exitNoMatch
+	}
+	return exitError
+}
+
+func newFlagSet(name string, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: spx-go %s [flags] %s\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses the arguments of a command, returning errUsage or flag.ErrHelp if they are invalid or help was
+// requested, once reported.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return errUsage
+	}
+	return nil
+}
+
+// usagef reports invalid arguments and returns errUsage.
+func usagef(flags *flag.FlagSet, format string, args ...interface{}) error {
+	fmt.Fprintf(flags.Output(), "spx-go %s: %s\n", flags.Name(), fmt.Sprintf(format, args...))
+	flags.Usage()
+	return errUsage
+}
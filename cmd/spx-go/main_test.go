@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recognition"
+)
+
+func TestPhraseWriter(t *testing.T) {
+	phrases := []phrase{
+		{Text: "Hello.", Offset: 500 * time.Millisecond, Duration: 1200 * time.Millisecond, SpeakerID: "Guest-1"},
+		{Text: "Bye.", Offset: time.Hour + 2*time.Minute + 3*time.Second, Duration: time.Second, Translations: map[string]string{"fr": "Au revoir.", "de": "Tschüss."}},
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{outputText, "Guest-1: Hello.\nBye.\n[de] Tschüss.\n[fr] Au revoir.\n"},
+		{outputSRT, "1\n00:00:00,500 --> 00:00:01,700\nGuest-1: Hello.\n\n" +
+			"2\n01:02:03,000 --> 01:02:04,000\nBye.\nTschüss.\nAu revoir.\n\n"},
+		{outputJSON, `{"offsetMs":500,"durationMs":1200,"speakerId":"Guest-1","text":"Hello."}` + "\n" +
+			`{"offsetMs":3723000,"durationMs":1000,"text":"Bye.","translations":{"de":"Tschüss.","fr":"Au revoir."}}` + "\n"},
+	}
+	for _, test := range tests {
+		var buffer bytes.Buffer
+		writer, err := newPhraseWriter(&buffer, test.format, outputText, outputJSON, outputSRT)
+		if err != nil {
+			t.Fatalf("newPhraseWriter(%q) error = %v", test.format, err)
+		}
+		for _, p := range phrases {
+			if err := writer.write(p); err != nil {
+				t.Fatalf("write() error = %v", err)
+			}
+		}
+		if got := buffer.String(); got != test.want {
+			t.Errorf("%s output = %q, want %q", test.format, got, test.want)
+		}
+		if writer.written() != 2 {
+			t.Errorf("written() = %d, want 2", writer.written())
+		}
+	}
+	if _, err := newPhraseWriter(&bytes.Buffer{}, outputSRT, outputText, outputJSON); err == nil {
+		t.Error("newPhraseWriter(srt) error = nil, want an error when srt is not allowed")
+	}
+}
+
+func TestListFlag(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	var list listFlag
+	flags.Var(&list, "to", "")
+	if err := flags.Parse([]string{"-to", "de, fr", "-to", "ja,"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := (listFlag{"de", "fr", "ja"}); !reflect.DeepEqual(list, want) {
+		t.Errorf("list = %v, want %v", list, want)
+	}
+}
+
+func TestContinuousFlag(t *testing.T) {
+	file, mic := &recognition.Input{}, &recognition.Input{Live: true}
+	tests := []struct {
+		args  []string
+		input *recognition.Input
+		want  bool
+	}{
+		{nil, file, false},
+		{nil, mic, true},
+		{[]string{"-continuous"}, file, true},
+		{[]string{"-continuous=false"}, mic, false},
+	}
+	for _, test := range tests {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		continuous := addContinuousFlag(flags, "")
+		if err := flags.Parse(test.args); err != nil {
+			t.Fatal(err)
+		}
+		if got := continuous.enabled(test.input); got != test.want {
+			t.Errorf("enabled(%v, live=%v) = %v, want %v", test.args, test.input.Live, got, test.want)
+		}
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	if code := run(nil); code != exitUsage {
+		t.Errorf("run() = %d, want %d", code, exitUsage)
+	}
+	if code := run([]string{"unknown"}); code != exitUsage {
+		t.Errorf("run(unknown) = %d, want %d", code, exitUsage)
+	}
+	// The arguments are rejected before any config is created.
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"recognize", "-output", "xml", "a.wav"}, exitUsage},
+		{[]string{"recognize", "-h"}, exitOK},
+		{[]string{"synthesize", "-out", "a.wav"}, exitUsage},
+		{[]string{"translate", "a.wav"}, exitUsage},
+		{[]string{"voices", "-gender", "other"}, exitUsage},
+		{[]string{"models", "-kind", "keyword"}, exitUsage},
+	}
+	for _, test := range tests {
+		if code := run(test.args); code != test.want {
+			t.Errorf("run(%q) = %d, want %d", test.args, code, test.want)
+		}
+	}
+}
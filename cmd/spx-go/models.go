@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// model describes an embedded recognition or translation model.
+type model struct {
+	Kind            string   `json:"kind"`
+	Name            string   `json:"name"`
+	Locales         []string `json:"locales,omitempty"`
+	TargetLanguages []string `json:"targetLanguages,omitempty"`
+	Version         string   `json:"version"`
+	Path            string   `json:"path"`
+}
+
+func runModels(ctx context.Context, args []string) error {
+	flags := newFlagSet("models", "")
+	service := addServiceFlags(flags)
+	kind := flags.String("kind", "all", "`kind` of the models: recognition, translation or all")
+	output := flags.String("output", "table", "output `format`: table or json")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usagef(flags, "too many arguments")
+	}
+	if *kind != "all" && *kind != "recognition" && *kind != "translation" {
+		return usagef(flags, "unknown model kind %q", *kind)
+	}
+	if *output != "table" && *output != outputJSON {
+		return usagef(flags, "unknown output format %q", *output)
+	}
+	settings, err := service.settings()
+	if err != nil {
+		return err
+	}
+	if settings.Embedded == nil || len(settings.Embedded.ModelPaths) == 0 {
+		return usagef(flags, "-embedded or %s is required", speech.EmbeddedModelsDirEnvironmentVariable)
+	}
+	config, err := settings.EmbeddedConfig()
+	if err != nil {
+		return err
+	}
+	defer config.Close()
+
+	var models []model
+	if *kind != "translation" {
+		infos, err := config.GetSpeechRecognitionModels()
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			models = append(models, model{
+				Kind:    "recognition",
+				Name:    info.Name(),
+				Locales: info.Locales(),
+				Version: info.Version(),
+				Path:    info.Path(),
+			})
+			info.Close()
+		}
+	}
+	if *kind != "recognition" {
+		infos, err := config.GetSpeechTranslationModels()
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			models = append(models, model{
+				Kind:            "translation",
+				Name:            info.Name(),
+				Locales:         info.SourceLanguages(),
+				TargetLanguages: info.TargetLanguages(),
+				Version:         info.Version(),
+				Path:            info.Path(),
+			})
+			info.Close()
+		}
+	}
+
+	if *output == outputJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if models == nil {
+			models = []model{}
+		}
+		return encoder.Encode(models)
+	}
+	table := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(table, "KIND\tNAME\tLOCALES\tTARGETS\tVERSION\tPATH")
+	for _, m := range models {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", m.Kind, m.Name, strings.Join(m.Locales, ","), strings.Join(m.TargetLanguages, ","), m.Version, m.Path)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// phrase is a recognized, translated or transcribed phrase.
+type phrase struct {
+	Text         string
+	Offset       time.Duration
+	Duration     time.Duration
+	SpeakerID    string
+	Translations map[string]string
+}
+
+// Output formats of the phrases.
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputSRT  = "srt"
+)
+
+// phraseWriter writes phrases as they are recognized: one line per phrase in the text format, one JSON object per line
+// in the JSON format, or SubRip subtitles.
+type phraseWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format string
+	count  int
+}
+
+func newPhraseWriter(w io.Writer, format string, allowed ...string) (*phraseWriter, error) {
+	for _, name := range allowed {
+		if name == format {
+			return &phraseWriter{w: w, format: format}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown output format %q, expected one of %v", format, allowed)
+}
+
+func (writer *phraseWriter) write(p phrase) error {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	writer.count++
+	text := p.Text
+	if p.SpeakerID != "" {
+		text = p.SpeakerID + ": " + text
+	}
+	languages := make([]string, 0, len(p.Translations))
+	for language := range p.Translations {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	var err error
+	switch writer.format {
+	case outputJSON:
+		type jsonPhrase struct {
+			OffsetMs     float64           `json:"offsetMs"`
+			DurationMs   float64           `json:"durationMs"`
+			SpeakerID    string            `json:"speakerId,omitempty"`
+			Text         string            `json:"text"`
+			Translations map[string]string `json:"translations,omitempty"`
+		}
+		err = json.NewEncoder(writer.w).Encode(jsonPhrase{
+			OffsetMs:     durationToMs(p.Offset),
+			DurationMs:   durationToMs(p.Duration),
+			SpeakerID:    p.SpeakerID,
+			Text:         p.Text,
+			Translations: p.Translations,
+		})
+	case outputSRT:
+		_, err = fmt.Fprintf(writer.w, "%d\n%s --> %s\n%s\n", writer.count, formatSRTTime(p.Offset), formatSRTTime(p.Offset+p.Duration), text)
+		for i := 0; err == nil && i < len(languages); i++ {
+			_, err = fmt.Fprintln(writer.w, p.Translations[languages[i]])
+		}
+		if err == nil {
+			_, err = fmt.Fprintln(writer.w)
+		}
+	default:
+		_, err = fmt.Fprintln(writer.w, text)
+		for i := 0; err == nil && i < len(languages); i++ {
+			_, err = fmt.Fprintf(writer.w, "[%s] %s\n", languages[i], p.Translations[languages[i]])
+		}
+	}
+	return err
+}
+
+// written returns the number of phrases written.
+func (writer *phraseWriter) written() int {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	return writer.count
+}
+
+func durationToMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// formatSRTTime formats an offset as a SubRip timestamp, e.g. 00:01:02,500.
+func formatSRTTime(d time.Duration) string {
+	ms := int64(d / time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recognition"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+func runRecognize(ctx context.Context, args []string) error {
+	flags := newFlagSet("recognize", "[file | -]")
+	service := addServiceFlags(flags)
+	model := addModelFlags(flags, "recognition model")
+	audioFlags := addAudioFlags(flags)
+	continuous := addContinuousFlag(flags, "recognize until the end of the audio rather than the first phrase")
+	output := flags.String("output", outputText, "output `format`: text, json or srt")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return usagef(flags, "too many arguments")
+	}
+	writer, err := newPhraseWriter(os.Stdout, *output, outputText, outputJSON, outputSRT)
+	if err != nil {
+		return usagef(flags, "%v", err)
+	}
+	settings, err := service.settings()
+	if err != nil {
+		return err
+	}
+	if service.isEmbedded(settings) {
+		model.apply(&embeddedSettings(settings).RecognitionModel)
+	}
+	input, err := audioFlags.open(flags.Arg(0))
+	if err != nil {
+		return usagef(flags, "%v", err)
+	}
+	defer input.Close()
+	config, closeConfig, err := service.speechConfig(settings)
+	if err != nil {
+		return err
+	}
+	defer closeConfig()
+	recognizer, err := speech.NewSpeechRecognizerFromConfig(config, input.Config)
+	if err != nil {
+		return err
+	}
+	defer recognizer.Close()
+
+	if !continuous.enabled(input) {
+		return recognizeOnce(ctx, recognizer, input, writer)
+	}
+	err = recognition.Recognize(ctx, recognizer, input, func(result *speech.SpeechRecognitionResult) error {
+		return writer.write(phrase{Text: result.Text, Offset: result.Offset, Duration: result.Duration})
+	})
+	if err == nil && writer.written() == 0 {
+		return errNoMatch
+	}
+	return err
+}
+
+// recognizeOnce recognizes the first phrase of the input.
+func recognizeOnce(ctx context.Context, recognizer *speech.SpeechRecognizer, input *recognition.Input, writer *phraseWriter) error {
+	outcomes := recognizer.RecognizeOnceWithContextAsync(ctx)
+	input.Start(ctx)
+	select {
+	case outcome := <-outcomes:
+		defer outcome.Close()
+		if outcome.Error != nil {
+			return outcome.Error
+		}
+		result := outcome.Result
+		if result.Reason == common.NoMatch || result.Text == "" {
+			return errNoMatch
+		}
+		return writer.write(phrase{Text: result.Text, Offset: result.Offset, Duration: result.Duration})
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// outputFormatsByExtension are the synthesis output formats used by default for the extension of the output file.
+var outputFormatsByExtension = map[string]common.SpeechSynthesisOutputFormat{
+	".wav":  common.Riff24Khz16BitMonoPcm,
+	".mp3":  common.Audio24Khz96KBitRateMonoMp3,
+	".ogg":  common.Ogg24Khz16BitMonoOpus,
+	".opus": common.Ogg24Khz16BitMonoOpus,
+}
+
+func runSynthesize(ctx context.Context, args []string) error {
+	flags := newFlagSet("synthesize", "[text]")
+	service := addServiceFlags(flags)
+	textFile := flags.String("input", "", "text `file` to synthesize, or - for standard input")
+	ssmlFile := flags.String("ssml", "", "SSML `file` to synthesize, or - for standard input")
+	voice := flags.String("voice", "", "voice `name`, e.g. en-US-AvaMultilingualNeural, or embedded voice name")
+	license := flags.String("license", os.Getenv("EMBEDDED_SPEECH_MODEL_LICENSE"), "license of the embedded voice")
+	formatName := flags.String("format", "", "output `format`, e.g. riff-24khz-16bit-mono-pcm; by default, that of the extension of -out")
+	out := flags.String("out", "", "output audio `file`, or - for standard output")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	sources := 0
+	for _, source := range []bool{*textFile != "", *ssmlFile != "", flags.NArg() > 0} {
+		if source {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return usagef(flags, "exactly one of text, -input or -ssml is required")
+	}
+	if *out == "" {
+		return usagef(flags, "-out is required")
+	}
+	text := strings.Join(flags.Args(), " ")
+	var err error
+	switch {
+	case *textFile != "":
+		text, err = readInput(*textFile)
+	case *ssmlFile != "":
+		text, err = readInput(*ssmlFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	settings, err := service.settings()
+	if err != nil {
+		return err
+	}
+	switch {
+	case *formatName != "":
+		format, err := common.ParseSpeechSynthesisOutputFormat(*formatName)
+		if err != nil {
+			return usagef(flags, "%v", err)
+		}
+		settings.SynthesisOutputFormat = format.Name()
+	case settings.SynthesisOutputFormat == "":
+		if format, ok := outputFormatsByExtension[strings.ToLower(filepath.Ext(*out))]; ok {
+			settings.SynthesisOutputFormat = format.Name()
+		}
+	}
+	if service.isEmbedded(settings) {
+		voiceModel := modelFlags{name: *voice, license: *license}
+		voiceModel.apply(&embeddedSettings(settings).SynthesisVoice)
+	} else if *voice != "" {
+		settings.Voice = *voice
+	}
+	config, closeConfig, err := service.speechConfig(settings)
+	if err != nil {
+		return err
+	}
+	defer closeConfig()
+	// Without audio config, the audio is kept in the result rather than played.
+	synthesizer, err := speech.NewSpeechSynthesizerFromConfig(config, nil)
+	if err != nil {
+		return err
+	}
+	defer synthesizer.Close()
+
+	var outcomes chan speech.SpeechSynthesisOutcome
+	if *ssmlFile != "" {
+		outcomes = synthesizer.SpeakSsmlWithContextAsync(ctx, text)
+	} else {
+		outcomes = synthesizer.SpeakTextWithContextAsync(ctx, text)
+	}
+	var outcome speech.SpeechSynthesisOutcome
+	select {
+	case outcome = <-outcomes:
+	case <-ctx.Done():
+		<-synthesizer.StopSpeakingAsync()
+		return ctx.Err()
+	}
+	defer outcome.Close()
+	if outcome.Error != nil {
+		return outcome.Error
+	}
+	if len(outcome.Result.AudioData) == 0 {
+		return errors.New("no audio was synthesized")
+	}
+	if *out == "-" {
+		_, err = os.Stdout.Write(outcome.Result.AudioData)
+		return err
+	}
+	return ioutil.WriteFile(*out, outcome.Result.AudioData, 0644)
+}
+
+// readInput reads the file at path, or standard input when path is -.
+func readInput(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	return string(data), err
+}
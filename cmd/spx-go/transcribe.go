@@ -0,0 +1,84 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recognition"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+func runTranscribe(ctx context.Context, args []string) error {
+	flags := newFlagSet("transcribe", "[file | -]")
+	service := addServiceFlags(flags)
+	audioFlags := addAudioFlags(flags)
+	output := flags.String("output", outputText, "output `format`: text, json or srt")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return usagef(flags, "too many arguments")
+	}
+	writer, err := newPhraseWriter(os.Stdout, *output, outputText, outputJSON, outputSRT)
+	if err != nil {
+		return usagef(flags, "%v", err)
+	}
+	settings, err := service.settings()
+	if err != nil {
+		return err
+	}
+	input, err := audioFlags.open(flags.Arg(0))
+	if err != nil {
+		return usagef(flags, "%v", err)
+	}
+	defer input.Close()
+	config, closeConfig, err := service.speechConfig(settings)
+	if err != nil {
+		return err
+	}
+	defer closeConfig()
+	transcriber, err := speech.NewConversationTranscriberFromConfig(config, input.Config)
+	if err != nil {
+		return err
+	}
+	defer transcriber.Close()
+
+	session := recognition.NewSession()
+	transcriber.Transcribed(func(event speech.ConversationTranscriptionEventArgs) {
+		defer event.Close()
+		result := event.Result
+		if result.Reason != common.RecognizedSpeech || result.Text == "" {
+			return
+		}
+		err := writer.write(phrase{
+			Text:      result.Text,
+			Offset:    result.Offset,
+			Duration:  result.Duration,
+			SpeakerID: result.SpeakerID,
+		})
+		if err != nil {
+			session.Finish(err)
+		}
+	})
+	transcriber.Canceled(func(event speech.ConversationTranscriptionCanceledEventArgs) {
+		defer event.Close()
+		if event.Reason == common.Error {
+			session.Finish(event.Err())
+		}
+	})
+	transcriber.SessionStopped(func(event speech.SessionEventArgs) {
+		defer event.Close()
+		session.Finish(nil)
+	})
+	err = session.Run(ctx, input, func() chan error {
+		return transcriber.StartTranscribingWithContextAsync(ctx)
+	}, transcriber.StopTranscribingAsync)
+	if err == nil && writer.written() == 0 {
+		return errNoMatch
+	}
+	return err
+}
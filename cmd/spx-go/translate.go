@@ -0,0 +1,138 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recognition"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+func runTranslate(ctx context.Context, args []string) error {
+	flags := newFlagSet("translate", "[file | -]")
+	service := addServiceFlags(flags)
+	model := addModelFlags(flags, "translation model")
+	audioFlags := addAudioFlags(flags)
+	var targets listFlag
+	flags.Var(&targets, "to", "target `languages`, comma-separated or repeated, e.g. de,fr")
+	continuous := addContinuousFlag(flags, "translate until the end of the audio rather than the first phrase")
+	output := flags.String("output", outputText, "output `format`: text, json or srt")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return usagef(flags, "too many arguments")
+	}
+	if len(targets) == 0 {
+		return usagef(flags, "-to is required")
+	}
+	writer, err := newPhraseWriter(os.Stdout, *output, outputText, outputJSON, outputSRT)
+	if err != nil {
+		return usagef(flags, "%v", err)
+	}
+	settings, err := service.settings()
+	if err != nil {
+		return err
+	}
+	input, err := audioFlags.open(flags.Arg(0))
+	if err != nil {
+		return usagef(flags, "%v", err)
+	}
+	defer input.Close()
+	recognizer, err := newTranslationRecognizer(service, settings, model, targets, input)
+	if err != nil {
+		return err
+	}
+	defer recognizer.Close()
+
+	translated := func(result *speech.TranslationRecognitionResult) (bool, error) {
+		if result.Reason != common.TranslatedSpeech || result.Text == "" {
+			return false, nil
+		}
+		return true, writer.write(phrase{
+			Text:         result.Text,
+			Offset:       result.Offset,
+			Duration:     result.Duration,
+			Translations: result.GetTranslations(),
+		})
+	}
+	if !continuous.enabled(input) {
+		outcomes := recognizer.RecognizeOnceWithContextAsync(ctx)
+		input.Start(ctx)
+		select {
+		case outcome := <-outcomes:
+			if outcome.Result != nil {
+				defer outcome.Result.Close()
+			}
+			if outcome.Error != nil {
+				return outcome.Error
+			}
+			ok, err := translated(outcome.Result)
+			if err == nil && !ok {
+				err = errNoMatch
+			}
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	session := recognition.NewSession()
+	recognizer.Recognized(func(event speech.TranslationRecognitionEventArgs) {
+		defer event.Close()
+		if _, err := translated(event.Result); err != nil {
+			session.Finish(err)
+		}
+	})
+	recognizer.Canceled(func(event speech.TranslationRecognitionCanceledEventArgs) {
+		defer event.Close()
+		if event.Reason == common.Error {
+			session.Finish(event.Err())
+		}
+	})
+	recognizer.SessionStopped(func(event speech.SessionEventArgs) {
+		defer event.Close()
+		session.Finish(nil)
+	})
+	err = session.Run(ctx, input, func() chan error {
+		return recognizer.StartContinuousRecognitionWithContextAsync(ctx)
+	}, recognizer.StopContinuousRecognitionAsync)
+	if err == nil && writer.written() == 0 {
+		return errNoMatch
+	}
+	return err
+}
+
+// newTranslationRecognizer creates a translation recognizer from the service or the embedded translation model, with
+// the source language of the settings and the target languages.
+func newTranslationRecognizer(service *serviceFlags, settings *speech.ConfigSettings, model *modelFlags, targets []string, input *recognition.Input) (*speech.TranslationRecognizer, error) {
+	if !service.isEmbedded(settings) {
+		settings.TargetLanguages = targets
+		config, err := settings.TranslationConfig()
+		if err != nil {
+			return nil, err
+		}
+		defer config.Close()
+		return speech.NewTranslationRecognizerFromConfig(config, input.Config)
+	}
+	model.apply(&embeddedSettings(settings).TranslationModel)
+	config, err := settings.EmbeddedConfig()
+	if err != nil {
+		return nil, err
+	}
+	defer config.Close()
+	recognizer, err := speech.NewTranslationRecognizerFromEmbeddedConfig(config, input.Config)
+	if err != nil {
+		return nil, err
+	}
+	for _, language := range targets {
+		if err = recognizer.AddTargetLanguage(language); err != nil {
+			recognizer.Close()
+			return nil, err
+		}
+	}
+	return recognizer, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+var voiceGenders = map[string]common.SynthesisVoiceGender{
+	"female": common.Female,
+	"male":   common.Male,
+}
+
+var voiceTypes = map[string][]common.SynthesisVoiceType{
+	"online":   speech.OnlineVoiceTypes,
+	"offline":  speech.OfflineVoiceTypes,
+	"neural":   {common.OnlineNeural, common.OnlineNeuralHD, common.OfflineNeural},
+	"neuralhd": {common.OnlineNeuralHD},
+	"standard": {common.OnlineStandard, common.OfflineStandard},
+}
+
+func runVoices(ctx context.Context, args []string) error {
+	flags := newFlagSet("voices", "")
+	service := addServiceFlags(flags)
+	gender := flags.String("gender", "", "list the voices of `gender`: female or male")
+	voiceType := flags.String("type", "", "list the voices of `type`: online, offline, neural, neuralhd or standard")
+	style := flags.String("style", "", "list the voices supporting `style`, e.g. cheerful")
+	output := flags.String("output", "table", "output `format`: table or json")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usagef(flags, "too many arguments")
+	}
+	filter := speech.VoiceFilter{Style: *style}
+	if *gender != "" {
+		var ok bool
+		if filter.Gender, ok = voiceGenders[strings.ToLower(*gender)]; !ok {
+			return usagef(flags, "unknown gender %q", *gender)
+		}
+	}
+	if *voiceType != "" {
+		var ok bool
+		if filter.VoiceTypes, ok = voiceTypes[strings.ToLower(*voiceType)]; !ok {
+			return usagef(flags, "unknown voice type %q", *voiceType)
+		}
+	}
+	if *output != "table" && *output != outputJSON {
+		return usagef(flags, "unknown output format %q", *output)
+	}
+	settings, err := service.settings()
+	if err != nil {
+		return err
+	}
+	// The -language flag selects the locale of the voices, rather than that of the config.
+	filter.Locale, settings.Language = settings.Language, ""
+	config, closeConfig, err := service.speechConfig(settings)
+	if err != nil {
+		return err
+	}
+	defer closeConfig()
+	synthesizer, err := speech.NewSpeechSynthesizerFromConfig(config, nil)
+	if err != nil {
+		return err
+	}
+	defer synthesizer.Close()
+
+	var outcome speech.SpeechSynthesisVoicesOutcome
+	select {
+	case outcome = <-synthesizer.GetVoicesAsync(filter.Locale):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer outcome.Close()
+	if outcome.Error != nil {
+		return outcome.Error
+	}
+	if outcome.Result.Reason != common.VoicesListRetrieved {
+		return errors.New("voices could not be retrieved: " + outcome.Result.ErrorDetails)
+	}
+	voices := outcome.Result.FilterVoices(filter)
+	if *output == outputJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(voices)
+	}
+	table := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tLOCALE\tGENDER\tTYPE\tSTYLES")
+	for _, voice := range voices {
+		fmt.Fprintf(table, "%s\t%s\t%v\t%v\t%s\n", voice.ShortName, voice.Locale, voice.Gender, voice.VoiceType, strings.Join(voice.StyleList, ","))
+	}
+	return table.Flush()
+}
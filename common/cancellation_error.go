@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package common
+
+import (
+	"errors"
+)
+
+// CancellationError is the error of a recognition or synthesis that was canceled. It matches ErrCanceled in
+// errors.Is, and can be retrieved with errors.As:
+//
+//	var canceled *common.CancellationError
+//	if errors.As(outcome.Error, &canceled) && canceled.Retryable() {
+//		// retry
+//	}
+type CancellationError struct {
+	// Reason is the reason of the cancellation.
+	Reason CancellationReason
+
+	// ErrorCode is the error code when Reason is Error.
+	ErrorCode CancellationErrorCode
+
+	// ErrorDetails is the error message when Reason is Error.
+	ErrorDetails string
+
+	// SessionID is the ID of the canceled session, when known.
+	SessionID string
+}
+
+func (e *CancellationError) Error() string {
+	message := "canceled: " + e.Reason.String()
+	if e.Reason == Error {
+		message += ": " + e.ErrorCode.String()
+	}
+	if e.ErrorDetails != "" {
+		message += ": " + e.ErrorDetails
+	}
+	if e.SessionID != "" {
+		message += " (session " + e.SessionID + ")"
+	}
+	return message
+}
+
+// Is reports whether target is ErrCanceled, for errors.Is.
+func (e *CancellationError) Is(target error) bool {
+	return ErrCanceled.Is(target)
+}
+
+// Retryable checks whether the cancellation is caused by a transient condition, such as throttling, a network
+// failure or a service outage, so that the same request may succeed later.
+func (e *CancellationError) Retryable() bool {
+	if e.Reason != Error {
+		return false
+	}
+	switch e.ErrorCode {
+	case TooManyRequests, ConnectionFailure, ServiceTimeout, ServiceError, ServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// IsRetryable checks whether err, or an error it wraps, is transient: a retryable cancellation, a timeout, or any
+// error with a Retryable method returning true.
+func IsRetryable(err error) bool {
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+	return errors.Is(err, ErrTimeout)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	err := fmt.Errorf("recognize: %w", CarbonError{Code: 0x006, Message: "timed out waiting for the service"})
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("errors.Is(%v, ErrTimeout) = false, want true", err)
+	}
+	if errors.Is(err, ErrInvalidArg) {
+		t.Errorf("errors.Is(%v, ErrInvalidArg) = true, want false", err)
+	}
+	if ErrInvalidArg.Error() != "Exception with an error code: 0x5 (SPXERR_INVALID_ARG)" {
+		t.Errorf("ErrInvalidArg = %q", ErrInvalidArg.Error())
+	}
+}
+
+func TestCancellationError(t *testing.T) {
+	canceled := &CancellationError{Reason: Error, ErrorCode: ServiceUnavailable, ErrorDetails: "try later", SessionID: "abc"}
+	err := fmt.Errorf("translate: %w", canceled)
+	if !errors.Is(err, ErrCanceled) {
+		t.Errorf("errors.Is(%v, ErrCanceled) = false, want true", err)
+	}
+	var target *CancellationError
+	if !errors.As(err, &target) || target != canceled {
+		t.Errorf("errors.As(%v) = %v, want %v", err, target, canceled)
+	}
+	if got, want := canceled.Error(), "canceled: Error: ServiceUnavailable: try later (session abc)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !IsRetryable(err) {
+		t.Errorf("IsRetryable(%v) = false, want true", err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		want bool
+	}{
+		{&CancellationError{Reason: Error, ErrorCode: TooManyRequests}, true},
+		{&CancellationError{Reason: Error, ErrorCode: AuthenticationFailure}, false},
+		{&CancellationError{Reason: EndOfStream}, false},
+		{ErrTimeout, true},
+		{ErrInvalidArg, false},
+		{errors.New("other"), false},
+		{nil, false},
+	} {
+		if got := IsRetryable(test.err); got != test.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
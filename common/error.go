@@ -10,6 +10,8 @@ import (
 // #include <speechapi_c_error.h>
 import "C"
 
+// CarbonError is an error reported by the native Speech SDK library. Code is the SPXERR code of the error, which
+// can be tested with errors.Is and the sentinel errors below, e.g. errors.Is(err, common.ErrTimeout).
 type CarbonError struct {
 	Code    int
 	Message string
@@ -67,14 +69,79 @@ var errorString = map[int]string{
 	0x032: "SPXERR_CANCELED",
 }
 
+// Sentinel errors for the SPXERR codes. A CarbonError matches the sentinel with the same code in errors.Is,
+// whatever its message:
+//
+//	if errors.Is(outcome.Error, common.ErrTimeout) {
+//		// retry
+//	}
+var (
+	ErrNotImplemented                              = newSentinelError(0xfff)
+	ErrUninitialized                               = newSentinelError(0x001)
+	ErrAlreadyInitialized                          = newSentinelError(0x002)
+	ErrUnhandledException                          = newSentinelError(0x003)
+	ErrNotFound                                    = newSentinelError(0x004)
+	ErrInvalidArg                                  = newSentinelError(0x005)
+	ErrTimeout                                     = newSentinelError(0x006)
+	ErrAlreadyInProgress                           = newSentinelError(0x007)
+	ErrFileOpenFailed                              = newSentinelError(0x008)
+	ErrUnexpectedEOF                               = newSentinelError(0x009)
+	ErrInvalidHeader                               = newSentinelError(0x00a)
+	ErrAudioIsPumping                              = newSentinelError(0x00b)
+	ErrUnsupportedFormat                           = newSentinelError(0x00c)
+	ErrAbort                                       = newSentinelError(0x00d)
+	ErrMicNotAvailable                             = newSentinelError(0x00e)
+	ErrInvalidState                                = newSentinelError(0x00f)
+	ErrUUIDCreateFailed                            = newSentinelError(0x010)
+	ErrSetFormatUnexpectedStateTransition          = newSentinelError(0x011)
+	ErrProcessAudioInvalidState                    = newSentinelError(0x012)
+	ErrStartRecognizingInvalidStateTransition      = newSentinelError(0x013)
+	ErrUnexpectedCreateObjectFailure               = newSentinelError(0x014)
+	ErrMicError                                    = newSentinelError(0x015)
+	ErrNoAudioInput                                = newSentinelError(0x016)
+	ErrUnexpectedUSPSiteFailure                    = newSentinelError(0x017)
+	ErrUnexpectedUnidecSiteFailure                 = newSentinelError(0x018)
+	ErrBufferTooSmall                              = newSentinelError(0x019)
+	ErrOutOfMemory                                 = newSentinelError(0x01A)
+	ErrRuntimeError                                = newSentinelError(0x01B)
+	ErrInvalidURL                                  = newSentinelError(0x01C)
+	ErrInvalidRegion                               = newSentinelError(0x01D)
+	ErrSwitchModeNotAllowed                        = newSentinelError(0x01E)
+	ErrChangeConnectionStatusNotAllowed            = newSentinelError(0x01F)
+	ErrExplicitConnectionNotSupportedByRecognizer  = newSentinelError(0x020)
+	ErrInvalidHandle                               = newSentinelError(0x021)
+	ErrInvalidRecognizer                           = newSentinelError(0x022)
+	ErrOutOfRange                                  = newSentinelError(0x023)
+	ErrExtensionLibraryNotFound                    = newSentinelError(0x024)
+	ErrUnexpectedTTSEngineSiteFailure              = newSentinelError(0x025)
+	ErrUnexpectedAudioOutputFailure                = newSentinelError(0x026)
+	ErrGStreamerInternalError                      = newSentinelError(0x027)
+	ErrContainerFormatNotSupported                 = newSentinelError(0x028)
+	ErrGStreamerNotFound                           = newSentinelError(0x029)
+	ErrInvalidLanguage                             = newSentinelError(0x02A)
+	ErrUnsupportedAPI                              = newSentinelError(0x02B)
+	ErrRingBufferDataUnavailable                   = newSentinelError(0x02C)
+	ErrUnexpectedConversationSiteFailure           = newSentinelError(0x030)
+	ErrUnexpectedConversationTranslatorSiteFailure = newSentinelError(0x031)
+	ErrCanceled                                    = newSentinelError(0x032)
+)
+
+func newSentinelError(code int) CarbonError {
+	return CarbonError{Code: code, Message: defaultErrorMessage(code)}
+}
+
+func defaultErrorMessage(code int) string {
+	codeAsHexString := fmt.Sprintf("0x%0x", code)
+	return "Exception with an error code: " + codeAsHexString + " (" + errorString[code] + ")"
+}
+
 func NewCarbonError(errorHandle uintptr) CarbonError {
 	var carbonError CarbonError
 	carbonError.Code = getErrorCode(SPXHandle(errorHandle))
 	carbonError.Message = getErrorMessage(SPXHandle(errorHandle))
 	// When the message is empty, construct the error message using the errorHandle value directly.
 	if carbonError.Message == "" {
-		codeAsHexString := fmt.Sprintf("0x%0x", carbonError.Code)
-		carbonError.Message = "Exception with an error code: " + codeAsHexString + " (" + errorString[carbonError.Code] + ")"
+		carbonError.Message = defaultErrorMessage(carbonError.Code)
 	}
 	return carbonError
 }
@@ -83,6 +150,17 @@ func (e CarbonError) Error() string {
 	return e.Message
 }
 
+// Is reports whether target is a CarbonError with the same code, for errors.Is.
+func (e CarbonError) Is(target error) bool {
+	switch t := target.(type) {
+	case CarbonError:
+		return t.Code == e.Code
+	case *CarbonError:
+		return t != nil && t.Code == e.Code
+	}
+	return false
+}
+
 func getErrorCode(errorHandle SPXHandle) int {
 	ret := int(C.error_get_error_code(uintptr2handle(errorHandle)))
 	// A 0 means there was no corresponding event stored.
@@ -0,0 +1,45 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LanguageIDMode defines when the spoken language is identified, set through the
+// SpeechServiceConnectionLanguageIDMode property.
+type LanguageIDMode int
+
+const (
+	// AtStartLanguageID identifies the language once, at the start of the audio.
+	AtStartLanguageID LanguageIDMode = 0
+
+	// ContinuousLanguageID identifies the language continuously, so that it can change during the recognition.
+	ContinuousLanguageID LanguageIDMode = 1
+)
+
+var languageIDModeValues = []string{"AtStart", "Continuous"}
+
+// String returns the property value of the mode: "AtStart" or "Continuous".
+func (mode LanguageIDMode) String() string {
+	if mode < 0 || int(mode) >= len(languageIDModeValues) {
+		return fmt.Sprintf("LanguageIDMode(%d)", int(mode))
+	}
+	return languageIDModeValues[mode]
+}
+
+// ParseLanguageIDMode returns the mode with the given property value, matched case-insensitively. An empty value is
+// AtStartLanguageID, the default of the service.
+func ParseLanguageIDMode(value string) (LanguageIDMode, error) {
+	if value == "" {
+		return AtStartLanguageID, nil
+	}
+	for i, name := range languageIDModeValues {
+		if strings.EqualFold(name, value) {
+			return LanguageIDMode(i), nil
+		}
+	}
+	return AtStartLanguageID, fmt.Errorf("unknown language identification mode %q", value)
+}
@@ -384,6 +384,16 @@ const (
 	// ConversationSpeechActivityTemplate is use to stamp properties in the template on the activity generated by the service for speech.
 	ConversationSpeechActivityTemplate PropertyID = 10006
 
+	// ConversationParticipantID is the identifier of the participant in the conversation.
+	ConversationParticipantID PropertyID = 10007
+
+	// ConversationRequestBotStatusMessages enables the turn status messages from the bot, delivered by the
+	// TurnStatusReceived event of the dialog service connector. Allowed values are "true" and "false".
+	ConversationRequestBotStatusMessages PropertyID = 10008
+
+	// ConversationConnectionID is an additional identifier used to associate the connection with the dialog backend.
+	ConversationConnectionID PropertyID = 10009
+
 	// DataBufferTimeStamp is the time stamp associated to data buffer written by client when using Pull/Push
 	// audio input streams.
 	// The time stamp is a 64-bit value with a resolution of 90 kHz. It is the same as the presentation timestamp
@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package common
+
+import "fmt"
+
+var propertyIDsByName = map[string]PropertyID{
+	"SpeechServiceConnectionKey":                                    SpeechServiceConnectionKey,
+	"SpeechServiceConnectionEndpoint":                               SpeechServiceConnectionEndpoint,
+	"SpeechServiceConnectionRegion":                                 SpeechServiceConnectionRegion,
+	"SpeechServiceAuthorizationToken":                               SpeechServiceAuthorizationToken,
+	"SpeechServiceAuthorizationType":                                SpeechServiceAuthorizationType,
+	"SpeechServiceConnectionEndpointID":                             SpeechServiceConnectionEndpointID,
+	"SpeechServiceConnectionHost":                                   SpeechServiceConnectionHost,
+	"SpeechServiceConnectionProxyHostName":                          SpeechServiceConnectionProxyHostName,
+	"SpeechServiceConnectionProxyPort":                              SpeechServiceConnectionProxyPort,
+	"SpeechServiceConnectionProxyUserName":                          SpeechServiceConnectionProxyUserName,
+	"SpeechServiceConnectionProxyPassword":                          SpeechServiceConnectionProxyPassword,
+	"SpeechServiceConnectionURL":                                    SpeechServiceConnectionURL,
+	"SpeechServiceConnectionProxyHostBypass":                        SpeechServiceConnectionProxyHostBypass,
+	"SpeechServiceConnectionTranslationToLanguages":                 SpeechServiceConnectionTranslationToLanguages,
+	"SpeechServiceConnectionTranslationVoice":                       SpeechServiceConnectionTranslationVoice,
+	"SpeechServiceConnectionTranslationFeatures":                    SpeechServiceConnectionTranslationFeatures,
+	"SpeechTranslationModelName":                                    SpeechTranslationModelName,
+	"SpeechTranslationModelKey":                                     SpeechTranslationModelKey,
+	"SpeechServiceConnectionRecoMode":                               SpeechServiceConnectionRecoMode,
+	"SpeechServiceConnectionRecoLanguage":                           SpeechServiceConnectionRecoLanguage,
+	"SpeechSessionID":                                               SpeechSessionID,
+	"SpeechServiceConnectionUserDefinedQueryParameters":             SpeechServiceConnectionUserDefinedQueryParameters,
+	"SpeechServiceConnectionRecoModelName":                          SpeechServiceConnectionRecoModelName,
+	"SpeechServiceConnectionRecoModelKey":                           SpeechServiceConnectionRecoModelKey,
+	"SpeechServiceConnectionSynthLanguage":                          SpeechServiceConnectionSynthLanguage,
+	"SpeechServiceConnectionSynthVoice":                             SpeechServiceConnectionSynthVoice,
+	"SpeechServiceConnectionSynthOutputFormat":                      SpeechServiceConnectionSynthOutputFormat,
+	"SpeechServiceConnectionSynthEnableCompressedAudioTransmission": SpeechServiceConnectionSynthEnableCompressedAudioTransmission,
+	"SpeechServiceConnectionSynthOfflineVoice":                      SpeechServiceConnectionSynthOfflineVoice,
+	"SpeechServiceConnectionSynthModelKey":                          SpeechServiceConnectionSynthModelKey,
+	"SpeechServiceConnectionInitialSilenceTimeoutMs":                SpeechServiceConnectionInitialSilenceTimeoutMs,
+	"SpeechServiceConnectionEndSilenceTimeoutMs":                    SpeechServiceConnectionEndSilenceTimeoutMs,
+	"SpeechServiceConnectionEnableAudioLogging":                     SpeechServiceConnectionEnableAudioLogging,
+	"SpeechServiceConnectionLanguageIDMode":                         SpeechServiceConnectionLanguageIDMode,
+	"SpeechServiceConnectionAutoDetectSourceLanguages":              SpeechServiceConnectionAutoDetectSourceLanguages,
+	"SpeechServiceConnectionAutoDetectSourceLanguageResult":         SpeechServiceConnectionAutoDetectSourceLanguageResult,
+	"SpeechServiceResponseRequestDetailedResultTrueFalse":           SpeechServiceResponseRequestDetailedResultTrueFalse,
+	"SpeechServiceResponseRequestProfanityFilterTrueFalse":          SpeechServiceResponseRequestProfanityFilterTrueFalse,
+	"SpeechServiceResponseProfanityOption":                          SpeechServiceResponseProfanityOption,
+	"SpeechServiceResponsePostProcessingOption":                     SpeechServiceResponsePostProcessingOption,
+	"SpeechServiceResponseRequestWordLevelTimestamps":               SpeechServiceResponseRequestWordLevelTimestamps,
+	"SpeechServiceResponseStablePartialResultThreshold":             SpeechServiceResponseStablePartialResultThreshold,
+	"SpeechServiceResponseOutputFormatOption":                       SpeechServiceResponseOutputFormatOption,
+	"SpeechServiceResponseTranslationRequestStablePartialResult":    SpeechServiceResponseTranslationRequestStablePartialResult,
+	"SpeechServiceResponseRequestWordBoundary":                      SpeechServiceResponseRequestWordBoundary,
+	"SpeechServiceResponseRequestPunctuationBoundary":               SpeechServiceResponseRequestPunctuationBoundary,
+	"SpeechServiceResponseRequestSentenceBoundary":                  SpeechServiceResponseRequestSentenceBoundary,
+	"SpeechServiceResponseJSONResult":                               SpeechServiceResponseJSONResult,
+	"SpeechServiceResponseJSONErrorDetails":                         SpeechServiceResponseJSONErrorDetails,
+	"SpeechServiceResponseRecognitionLatencyMs":                     SpeechServiceResponseRecognitionLatencyMs,
+	"SpeechServiceResponseSynthesisFirstByteLatencyMs":              SpeechServiceResponseSynthesisFirstByteLatencyMs,
+	"SpeechServiceResponseSynthesisFinishLatencyMs":                 SpeechServiceResponseSynthesisFinishLatencyMs,
+	"SpeechServiceResponseSynthesisUnderrunTimeMs":                  SpeechServiceResponseSynthesisUnderrunTimeMs,
+	"SpeechServiceResponseSynthesisConnectionLatencyMs":             SpeechServiceResponseSynthesisConnectionLatencyMs,
+	"SpeechServiceResponseSynthesisNetworkLatencyMs":                SpeechServiceResponseSynthesisNetworkLatencyMs,
+	"SpeechServiceResponseSynthesisServiceLatencyMs":                SpeechServiceResponseSynthesisServiceLatencyMs,
+	"SpeechServiceResponseSynthesisBackend":                         SpeechServiceResponseSynthesisBackend,
+	"CancellationDetailsReason":                                     CancellationDetailsReason,
+	"CancellationDetailsReasonText":                                 CancellationDetailsReasonText,
+	"CancellationDetailsReasonDetailedText":                         CancellationDetailsReasonDetailedText,
+	"AudioConfigDeviceNameForCapture":                               AudioConfigDeviceNameForCapture,
+	"AudioConfigNumberOfChannelsForCapture":                         AudioConfigNumberOfChannelsForCapture,
+	"AudioConfigSampleRateForCapture":                               AudioConfigSampleRateForCapture,
+	"AudioConfigBitsPerSampleForCapture":                            AudioConfigBitsPerSampleForCapture,
+	"AudioConfigAudioSource":                                        AudioConfigAudioSource,
+	"AudioConfigDeviceNameForRender":                                AudioConfigDeviceNameForRender,
+	"AudioConfigPlaybackBufferLengthInMs":                           AudioConfigPlaybackBufferLengthInMs,
+	"AudioProcessingOptions":                                        AudioProcessingOptions,
+	"SpeechLogFilename":                                             SpeechLogFilename,
+	"SegmentationSilenceTimeoutMs":                                  SegmentationSilenceTimeoutMs,
+	"SegmentationMaximumTimeMs":                                     SegmentationMaximumTimeMs,
+	"SegmentationStrategy":                                          SegmentationStrategy,
+	"EnableMultiChannelProcessing":                                  EnableMultiChannelProcessing,
+	"ConversationApplicationID":                                     ConversationApplicationID,
+	"ConversationDialogType":                                        ConversationDialogType,
+	"ConversationInitialSilenceTimeout":                             ConversationInitialSilenceTimeout,
+	"ConversationFromID":                                            ConversationFromID,
+	"ConversationConversationID":                                    ConversationConversationID,
+	"ConversationCustomVoiceDeploymentIDs":                          ConversationCustomVoiceDeploymentIDs,
+	"ConversationSpeechActivityTemplate":                            ConversationSpeechActivityTemplate,
+	"ConversationParticipantID":                                     ConversationParticipantID,
+	"ConversationRequestBotStatusMessages":                          ConversationRequestBotStatusMessages,
+	"ConversationConnectionID":                                      ConversationConnectionID,
+	"DataBufferTimeStamp":                                           DataBufferTimeStamp,
+	"DataBufferUserID":                                              DataBufferUserID,
+	"KeywordRecognitionModelName":                                   KeywordRecognitionModelName,
+	"KeywordRecognitionModelKey":                                    KeywordRecognitionModelKey,
+}
+
+// ParsePropertyID returns the property ID with the given constant name, e.g. SpeechServiceConnectionEndpointID.
+func ParsePropertyID(name string) (PropertyID, error) {
+	if id, ok := propertyIDsByName[name]; ok {
+		return id, nil
+	}
+	return 0, fmt.Errorf("unknown property ID %q", name)
+}
@@ -0,0 +1,221 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Limits of the Speech Service for the segmentation properties.
+const (
+	MinSegmentationSilenceTimeout = 100 * time.Millisecond
+	MaxSegmentationSilenceTimeout = 5 * time.Second
+	MinSegmentationMaximumTime    = 20 * time.Second
+	MaxSegmentationMaximumTime    = 70 * time.Second
+)
+
+type durationLimit struct {
+	min time.Duration
+	max time.Duration
+}
+
+var durationLimits = map[PropertyID]durationLimit{
+	SegmentationSilenceTimeoutMs:                   {MinSegmentationSilenceTimeout, MaxSegmentationSilenceTimeout},
+	SegmentationMaximumTimeMs:                      {MinSegmentationMaximumTime, MaxSegmentationMaximumTime},
+	SpeechServiceConnectionInitialSilenceTimeoutMs: {},
+	SpeechServiceConnectionEndSilenceTimeoutMs:     {},
+	ConversationInitialSilenceTimeout:              {},
+}
+
+// InvalidPropertyError reports a property value rejected before being set. It matches ErrInvalidArg with errors.Is.
+type InvalidPropertyError struct {
+	Property PropertyID
+	Value    string
+	Reason   string
+}
+
+func (e *InvalidPropertyError) Error() string {
+	name := strconv.Itoa(int(e.Property))
+	for n, id := range propertyIDsByName {
+		if id == e.Property {
+			name = n
+			break
+		}
+	}
+	return fmt.Sprintf("invalid value %s for %s: %s", e.Value, name, e.Reason)
+}
+
+// Is reports whether target is ErrInvalidArg.
+func (e *InvalidPropertyError) Is(target error) bool {
+	return ErrInvalidArg.Is(target)
+}
+
+// ValidatePropertyDuration checks a duration against the limits of the Speech Service for the given timeout or
+// segmentation property. Durations must not be negative, SegmentationSilenceTimeoutMs must be between
+// MinSegmentationSilenceTimeout and MaxSegmentationSilenceTimeout, and SegmentationMaximumTimeMs between
+// MinSegmentationMaximumTime and MaxSegmentationMaximumTime.
+func ValidatePropertyDuration(id PropertyID, value time.Duration) error {
+	limit, ok := durationLimits[id]
+	switch {
+	case !ok:
+		return &InvalidPropertyError{Property: id, Value: value.String(), Reason: "not a duration property"}
+	case value < 0:
+		return &InvalidPropertyError{Property: id, Value: value.String(), Reason: "must not be negative"}
+	case limit.max != 0 && (value < limit.min || value > limit.max):
+		return &InvalidPropertyError{Property: id, Value: value.String(), Reason: fmt.Sprintf("must be between %v and %v", limit.min, limit.max)}
+	}
+	return nil
+}
+
+// PropertyAccessor gets and sets properties by ID. SpeechConfig implements it.
+type PropertyAccessor interface {
+	GetProperty(id PropertyID) string
+	SetProperty(id PropertyID, value string) error
+}
+
+type collectionAccessor struct {
+	properties *PropertyCollection
+}
+
+func (accessor collectionAccessor) GetProperty(id PropertyID) string {
+	return accessor.properties.GetProperty(id, "")
+}
+
+func (accessor collectionAccessor) SetProperty(id PropertyID, value string) error {
+	return accessor.properties.SetProperty(id, value)
+}
+
+// RecognitionProperties gives typed access to the timeout, segmentation, stable partial result and language
+// identification properties, which are otherwise set as strings. It is embedded in SpeechConfig and the recognizers.
+// Durations are set in whole milliseconds and getters return zero when a property is not set, meaning the service
+// default applies.
+type RecognitionProperties struct {
+	accessor PropertyAccessor
+}
+
+// NewRecognitionProperties creates typed accessors for the properties of accessor.
+func NewRecognitionProperties(accessor PropertyAccessor) RecognitionProperties {
+	return RecognitionProperties{accessor: accessor}
+}
+
+// NewRecognitionPropertiesFromCollection creates typed accessors for a property collection.
+func NewRecognitionPropertiesFromCollection(properties *PropertyCollection) RecognitionProperties {
+	return RecognitionProperties{accessor: collectionAccessor{properties}}
+}
+
+func (properties RecognitionProperties) duration(id PropertyID) time.Duration {
+	ms, err := strconv.ParseInt(properties.accessor.GetProperty(id), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func (properties RecognitionProperties) setDuration(id PropertyID, value time.Duration) error {
+	if err := ValidatePropertyDuration(id, value); err != nil {
+		return err
+	}
+	return properties.accessor.SetProperty(id, strconv.FormatInt(int64(value/time.Millisecond), 10))
+}
+
+// SegmentationSilenceTimeout is the duration of silence after which a phrase is considered finished.
+func (properties RecognitionProperties) SegmentationSilenceTimeout() time.Duration {
+	return properties.duration(SegmentationSilenceTimeoutMs)
+}
+
+// SetSegmentationSilenceTimeout sets the duration of silence after which a phrase is considered finished, between
+// MinSegmentationSilenceTimeout and MaxSegmentationSilenceTimeout.
+func (properties RecognitionProperties) SetSegmentationSilenceTimeout(timeout time.Duration) error {
+	return properties.setDuration(SegmentationSilenceTimeoutMs, timeout)
+}
+
+// SegmentationMaximumTime is the maximum length of a phrase with the time segmentation strategy.
+func (properties RecognitionProperties) SegmentationMaximumTime() time.Duration {
+	return properties.duration(SegmentationMaximumTimeMs)
+}
+
+// SetSegmentationMaximumTime sets the maximum length of a phrase with the time segmentation strategy, between
+// MinSegmentationMaximumTime and MaxSegmentationMaximumTime.
+func (properties RecognitionProperties) SetSegmentationMaximumTime(maximum time.Duration) error {
+	return properties.setDuration(SegmentationMaximumTimeMs, maximum)
+}
+
+// SegmentationStrategy is the strategy used to determine when a phrase has ended.
+func (properties RecognitionProperties) SegmentationStrategy() SegmentationMode {
+	mode, _ := ParseSegmentationMode(properties.accessor.GetProperty(SegmentationStrategy))
+	return mode
+}
+
+// SetSegmentationStrategy sets the strategy used to determine when a phrase has ended.
+func (properties RecognitionProperties) SetSegmentationStrategy(mode SegmentationMode) error {
+	if mode < DefaultSegmentation || mode > SemanticSegmentation {
+		return &InvalidPropertyError{Property: SegmentationStrategy, Value: mode.String(), Reason: "unknown segmentation strategy"}
+	}
+	return properties.accessor.SetProperty(SegmentationStrategy, mode.String())
+}
+
+// InitialSilenceTimeout is how long the recognition waits for speech before ending with no match.
+func (properties RecognitionProperties) InitialSilenceTimeout() time.Duration {
+	return properties.duration(SpeechServiceConnectionInitialSilenceTimeoutMs)
+}
+
+// SetInitialSilenceTimeout sets how long the recognition waits for speech before ending with no match.
+func (properties RecognitionProperties) SetInitialSilenceTimeout(timeout time.Duration) error {
+	return properties.setDuration(SpeechServiceConnectionInitialSilenceTimeoutMs, timeout)
+}
+
+// EndSilenceTimeout is the duration of silence after which a single-shot recognition ends.
+func (properties RecognitionProperties) EndSilenceTimeout() time.Duration {
+	return properties.duration(SpeechServiceConnectionEndSilenceTimeoutMs)
+}
+
+// SetEndSilenceTimeout sets the duration of silence after which a single-shot recognition ends.
+func (properties RecognitionProperties) SetEndSilenceTimeout(timeout time.Duration) error {
+	return properties.setDuration(SpeechServiceConnectionEndSilenceTimeoutMs, timeout)
+}
+
+// ConversationInitialSilenceTimeout is how long a dialog turn waits for speech.
+func (properties RecognitionProperties) ConversationInitialSilenceTimeout() time.Duration {
+	return properties.duration(ConversationInitialSilenceTimeout)
+}
+
+// SetConversationInitialSilenceTimeout sets how long a dialog turn waits for speech.
+func (properties RecognitionProperties) SetConversationInitialSilenceTimeout(timeout time.Duration) error {
+	return properties.setDuration(ConversationInitialSilenceTimeout, timeout)
+}
+
+// StablePartialResultThreshold is the number of times a word has to be in partial results to be returned, or zero
+// when not set.
+func (properties RecognitionProperties) StablePartialResultThreshold() int {
+	threshold, err := strconv.Atoi(properties.accessor.GetProperty(SpeechServiceResponseStablePartialResultThreshold))
+	if err != nil {
+		return 0
+	}
+	return threshold
+}
+
+// SetStablePartialResultThreshold sets the number of times a word has to be in partial results to be returned. Higher
+// thresholds make partial results more stable, at the cost of latency.
+func (properties RecognitionProperties) SetStablePartialResultThreshold(threshold int) error {
+	if threshold < 1 {
+		return &InvalidPropertyError{Property: SpeechServiceResponseStablePartialResultThreshold, Value: strconv.Itoa(threshold), Reason: "must be at least 1"}
+	}
+	return properties.accessor.SetProperty(SpeechServiceResponseStablePartialResultThreshold, strconv.Itoa(threshold))
+}
+
+// LanguageIDMode is when the spoken language is identified.
+func (properties RecognitionProperties) LanguageIDMode() LanguageIDMode {
+	mode, _ := ParseLanguageIDMode(properties.accessor.GetProperty(SpeechServiceConnectionLanguageIDMode))
+	return mode
+}
+
+// SetLanguageIDMode sets when the spoken language is identified.
+func (properties RecognitionProperties) SetLanguageIDMode(mode LanguageIDMode) error {
+	if mode != AtStartLanguageID && mode != ContinuousLanguageID {
+		return &InvalidPropertyError{Property: SpeechServiceConnectionLanguageIDMode, Value: mode.String(), Reason: "unknown language identification mode"}
+	}
+	return properties.accessor.SetProperty(SpeechServiceConnectionLanguageIDMode, mode.String())
+}
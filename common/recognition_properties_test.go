@@ -0,0 +1,99 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mapAccessor map[PropertyID]string
+
+func (accessor mapAccessor) GetProperty(id PropertyID) string {
+	return accessor[id]
+}
+
+func (accessor mapAccessor) SetProperty(id PropertyID, value string) error {
+	accessor[id] = value
+	return nil
+}
+
+func TestRecognitionPropertiesDurations(t *testing.T) {
+	values := mapAccessor{}
+	properties := NewRecognitionProperties(values)
+	if properties.SegmentationSilenceTimeout() != 0 {
+		t.Error("Unexpected default segmentation silence timeout: ", properties.SegmentationSilenceTimeout())
+	}
+	if err := properties.SetSegmentationSilenceTimeout(750 * time.Millisecond); err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	if values[SegmentationSilenceTimeoutMs] != "750" || properties.SegmentationSilenceTimeout() != 750*time.Millisecond {
+		t.Error("Unexpected segmentation silence timeout: ", values[SegmentationSilenceTimeoutMs])
+	}
+	if err := properties.SetInitialSilenceTimeout(0); err != nil || values[SpeechServiceConnectionInitialSilenceTimeoutMs] != "0" {
+		t.Error("Unexpected initial silence timeout: ", values[SpeechServiceConnectionInitialSilenceTimeoutMs], err)
+	}
+	if err := properties.SetConversationInitialSilenceTimeout(8 * time.Second); err != nil || properties.ConversationInitialSilenceTimeout() != 8*time.Second {
+		t.Error("Unexpected conversation initial silence timeout: ", properties.ConversationInitialSilenceTimeout(), err)
+	}
+
+	invalid := []struct {
+		set   func(time.Duration) error
+		value time.Duration
+	}{
+		{properties.SetSegmentationSilenceTimeout, 50 * time.Millisecond},
+		{properties.SetSegmentationSilenceTimeout, 6 * time.Second},
+		{properties.SetSegmentationMaximumTime, 10 * time.Second},
+		{properties.SetSegmentationMaximumTime, 90 * time.Second},
+		{properties.SetInitialSilenceTimeout, -time.Second},
+		{properties.SetEndSilenceTimeout, -time.Millisecond},
+	}
+	for _, test := range invalid {
+		if err := test.set(test.value); !errors.Is(err, ErrInvalidArg) {
+			t.Error("Expected an invalid argument error for ", test.value, ", got ", err)
+		}
+	}
+	if values[SegmentationSilenceTimeoutMs] != "750" {
+		t.Error("Expected the last valid segmentation silence timeout, got ", values[SegmentationSilenceTimeoutMs])
+	}
+	err := properties.SetSegmentationMaximumTime(90 * time.Second)
+	if err == nil || !strings.Contains(err.Error(), "SegmentationMaximumTimeMs") || !strings.Contains(err.Error(), "1m10s") {
+		t.Error("Unexpected error: ", err)
+	}
+}
+
+func TestRecognitionPropertiesEnumsAndThreshold(t *testing.T) {
+	values := mapAccessor{}
+	properties := NewRecognitionProperties(values)
+	if properties.SegmentationStrategy() != DefaultSegmentation || properties.LanguageIDMode() != AtStartLanguageID {
+		t.Error("Unexpected defaults: ", properties.SegmentationStrategy(), properties.LanguageIDMode())
+	}
+	if err := properties.SetSegmentationStrategy(SemanticSegmentation); err != nil || values[SegmentationStrategy] != "Semantic" {
+		t.Error("Unexpected segmentation strategy: ", values[SegmentationStrategy], err)
+	}
+	values[SegmentationStrategy] = "time"
+	if properties.SegmentationStrategy() != TimeSegmentation {
+		t.Error("Unexpected segmentation strategy: ", properties.SegmentationStrategy())
+	}
+	if err := properties.SetLanguageIDMode(ContinuousLanguageID); err != nil || values[SpeechServiceConnectionLanguageIDMode] != "Continuous" {
+		t.Error("Unexpected language ID mode: ", values[SpeechServiceConnectionLanguageIDMode], err)
+	}
+	if err := properties.SetLanguageIDMode(LanguageIDMode(5)); !errors.Is(err, ErrInvalidArg) {
+		t.Error("Expected an invalid argument error, got ", err)
+	}
+	if err := properties.SetSegmentationStrategy(SegmentationMode(-1)); !errors.Is(err, ErrInvalidArg) {
+		t.Error("Expected an invalid argument error, got ", err)
+	}
+	if err := properties.SetStablePartialResultThreshold(3); err != nil || properties.StablePartialResultThreshold() != 3 {
+		t.Error("Unexpected stable partial result threshold: ", properties.StablePartialResultThreshold(), err)
+	}
+	if err := properties.SetStablePartialResultThreshold(0); !errors.Is(err, ErrInvalidArg) {
+		t.Error("Expected an invalid argument error, got ", err)
+	}
+	if _, err := ParseSegmentationMode("Phrase"); err == nil {
+		t.Error("Expected an error parsing an unknown segmentation mode")
+	}
+}
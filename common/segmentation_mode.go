@@ -0,0 +1,49 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SegmentationMode defines the strategy used to determine when a spoken phrase has ended, set through the
+// SegmentationStrategy property.
+type SegmentationMode int
+
+const (
+	// DefaultSegmentation uses the strategy and settings determined by the Speech Service.
+	DefaultSegmentation SegmentationMode = 0
+
+	// TimeSegmentation ends a phrase after an amount of silence, see SegmentationSilenceTimeoutMs and
+	// SegmentationMaximumTimeMs.
+	TimeSegmentation SegmentationMode = 1
+
+	// SemanticSegmentation uses an AI model to determine the end of a phrase based on its content.
+	SemanticSegmentation SegmentationMode = 2
+)
+
+var segmentationModeValues = []string{"Default", "Time", "Semantic"}
+
+// String returns the property value of the mode: "Default", "Time" or "Semantic".
+func (mode SegmentationMode) String() string {
+	if mode < 0 || int(mode) >= len(segmentationModeValues) {
+		return fmt.Sprintf("SegmentationMode(%d)", int(mode))
+	}
+	return segmentationModeValues[mode]
+}
+
+// ParseSegmentationMode returns the mode with the given property value, matched case-insensitively. An empty value
+// is DefaultSegmentation.
+func ParseSegmentationMode(value string) (SegmentationMode, error) {
+	if value == "" {
+		return DefaultSegmentation, nil
+	}
+	for i, name := range segmentationModeValues {
+		if strings.EqualFold(name, value) {
+			return SegmentationMode(i), nil
+		}
+	}
+	return DefaultSegmentation, fmt.Errorf("unknown segmentation strategy %q", value)
+}
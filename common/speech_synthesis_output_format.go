@@ -131,4 +131,8 @@ const (
 	// AmrWb16000Hz stands for amr-wb-16000hz
 	// AMR-WB audio at 16kHz sampling rate.
 	AmrWb16000Hz SpeechSynthesisOutputFormat = 38
+
+	// G72216Khz64Kbps stands for g722-16khz-64kbps
+	// G.722 audio at 16kHz sampling rate and 64kbps bitrate.
+	G72216Khz64Kbps SpeechSynthesisOutputFormat = 39
 )
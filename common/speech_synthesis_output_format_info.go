@@ -0,0 +1,211 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AudioContainer defines the container (or framing) of the audio produced by a speech synthesis output format.
+type AudioContainer string
+
+const (
+	// ContainerRaw indicates raw samples without any header.
+	ContainerRaw AudioContainer = "raw"
+
+	// ContainerRiff indicates a RIFF (WAV) header followed by the samples.
+	ContainerRiff AudioContainer = "riff"
+
+	// ContainerOgg indicates an Ogg container.
+	ContainerOgg AudioContainer = "ogg"
+
+	// ContainerWebm indicates a WebM container.
+	ContainerWebm AudioContainer = "webm"
+
+	// ContainerMp3 indicates an MP3 stream.
+	ContainerMp3 AudioContainer = "mp3"
+
+	// ContainerAmr indicates an AMR stream.
+	ContainerAmr AudioContainer = "amr"
+
+	// ContainerOpus indicates an Opus stream without container.
+	ContainerOpus AudioContainer = "opus"
+
+	// ContainerG722 indicates a G.722 stream.
+	ContainerG722 AudioContainer = "g722"
+
+	// ContainerALaw indicates raw A-law samples.
+	ContainerALaw AudioContainer = "alaw"
+
+	// ContainerMULaw indicates raw mu-law samples.
+	ContainerMULaw AudioContainer = "mulaw"
+)
+
+// SpeechSynthesisOutputFormatInfo describes the audio produced by a speech synthesis output format.
+type SpeechSynthesisOutputFormatInfo struct {
+	// Format is the described output format.
+	Format SpeechSynthesisOutputFormat
+
+	// Name is the name of the format used by the service, e.g. riff-16khz-16bit-mono-pcm.
+	Name string
+
+	// SampleRate is the sample rate, in Hz.
+	SampleRate int
+
+	// BitsPerSample is the bit depth of the (decoded) samples.
+	BitsPerSample int
+
+	// Channels is the number of channels.
+	Channels int
+
+	// Container is the container of the audio.
+	Container AudioContainer
+
+	// Codec is the codec of the audio, e.g. pcm, mp3 or opus.
+	Codec string
+
+	// BitRate is the bitrate, in bits per second, or 0 if it is variable or not specified by the format.
+	BitRate int
+
+	// MimeType is the MIME type to use as HTTP Content-Type of the audio.
+	MimeType string
+
+	// FileExtension is the conventional file extension of the audio, including the leading dot.
+	FileExtension string
+}
+
+// IsPCM checks whether the format produces uncompressed PCM samples, with or without RIFF header.
+func (info SpeechSynthesisOutputFormatInfo) IsPCM() bool {
+	return info.Codec == "pcm"
+}
+
+func pcmFormat(format SpeechSynthesisOutputFormat, name string, container AudioContainer, sampleRate int) SpeechSynthesisOutputFormatInfo {
+	info := SpeechSynthesisOutputFormatInfo{
+		Format:        format,
+		Name:          name,
+		SampleRate:    sampleRate,
+		BitsPerSample: 16,
+		Channels:      1,
+		Container:     container,
+		Codec:         "pcm",
+		BitRate:       sampleRate * 16,
+		MimeType:      "audio/pcm",
+		FileExtension: ".pcm",
+	}
+	if container == ContainerRiff {
+		info.MimeType = "audio/wav"
+		info.FileExtension = ".wav"
+	}
+	return info
+}
+
+func compressedFormat(format SpeechSynthesisOutputFormat, name string, container AudioContainer, codec string, sampleRate int, bitRate int) SpeechSynthesisOutputFormatInfo {
+	info := SpeechSynthesisOutputFormatInfo{
+		Format:        format,
+		Name:          name,
+		SampleRate:    sampleRate,
+		BitsPerSample: 16,
+		Channels:      1,
+		Container:     container,
+		Codec:         codec,
+		BitRate:       bitRate,
+	}
+	switch container {
+	case ContainerRiff:
+		info.MimeType, info.FileExtension = "audio/wav", ".wav"
+	case ContainerOgg:
+		info.MimeType, info.FileExtension = "audio/ogg", ".ogg"
+	case ContainerWebm:
+		info.MimeType, info.FileExtension = "audio/webm", ".webm"
+	case ContainerMp3:
+		info.MimeType, info.FileExtension = "audio/mpeg", ".mp3"
+	case ContainerAmr:
+		info.MimeType, info.FileExtension = "audio/amr-wb", ".awb"
+	case ContainerOpus:
+		info.MimeType, info.FileExtension = "audio/opus", ".opus"
+	case ContainerG722:
+		info.MimeType, info.FileExtension = "audio/G722", ".g722"
+	case ContainerALaw:
+		info.MimeType, info.FileExtension = "audio/x-alaw-basic", ".alaw"
+	case ContainerMULaw:
+		info.MimeType, info.FileExtension = "audio/basic", ".ulaw"
+	default:
+		info.MimeType, info.FileExtension = "application/octet-stream", "."+codec
+	}
+	if codec == "alaw" || codec == "mulaw" {
+		info.BitsPerSample = 8
+	}
+	return info
+}
+
+var speechSynthesisOutputFormatInfos = []SpeechSynthesisOutputFormatInfo{
+	compressedFormat(Raw8Khz8BitMonoMULaw, "raw-8khz-8bit-mono-mulaw", ContainerMULaw, "mulaw", 8000, 64000),
+	compressedFormat(Riff16Khz16KbpsMonoSiren, "riff-16khz-16kbps-mono-siren", ContainerRiff, "siren", 16000, 16000),
+	compressedFormat(Audio16Khz16KbpsMonoSiren, "audio-16khz-16kbps-mono-siren", ContainerRaw, "siren", 16000, 16000),
+	compressedFormat(Audio16Khz32KBitRateMonoMp3, "audio-16khz-32kbitrate-mono-mp3", ContainerMp3, "mp3", 16000, 32000),
+	compressedFormat(Audio16Khz128KBitRateMonoMp3, "audio-16khz-128kbitrate-mono-mp3", ContainerMp3, "mp3", 16000, 128000),
+	compressedFormat(Audio16Khz64KBitRateMonoMp3, "audio-16khz-64kbitrate-mono-mp3", ContainerMp3, "mp3", 16000, 64000),
+	compressedFormat(Audio24Khz48KBitRateMonoMp3, "audio-24khz-48kbitrate-mono-mp3", ContainerMp3, "mp3", 24000, 48000),
+	compressedFormat(Audio24Khz96KBitRateMonoMp3, "audio-24khz-96kbitrate-mono-mp3", ContainerMp3, "mp3", 24000, 96000),
+	compressedFormat(Audio24Khz160KBitRateMonoMp3, "audio-24khz-160kbitrate-mono-mp3", ContainerMp3, "mp3", 24000, 160000),
+	compressedFormat(Raw16Khz16BitMonoTrueSilk, "raw-16khz-16bit-mono-truesilk", ContainerRaw, "silk", 16000, 0),
+	pcmFormat(Riff16Khz16BitMonoPcm, "riff-16khz-16bit-mono-pcm", ContainerRiff, 16000),
+	pcmFormat(Riff8Khz16BitMonoPcm, "riff-8khz-16bit-mono-pcm", ContainerRiff, 8000),
+	pcmFormat(Riff24Khz16BitMonoPcm, "riff-24khz-16bit-mono-pcm", ContainerRiff, 24000),
+	compressedFormat(Riff8Khz8BitMonoMULaw, "riff-8khz-8bit-mono-mulaw", ContainerRiff, "mulaw", 8000, 64000),
+	pcmFormat(Raw16Khz16BitMonoPcm, "raw-16khz-16bit-mono-pcm", ContainerRaw, 16000),
+	pcmFormat(Raw24Khz16BitMonoPcm, "raw-24khz-16bit-mono-pcm", ContainerRaw, 24000),
+	pcmFormat(Raw8Khz16BitMonoPcm, "raw-8khz-16bit-mono-pcm", ContainerRaw, 8000),
+	compressedFormat(Ogg16Khz16BitMonoOpus, "ogg-16khz-16bit-mono-opus", ContainerOgg, "opus", 16000, 0),
+	compressedFormat(Ogg24Khz16BitMonoOpus, "ogg-24khz-16bit-mono-opus", ContainerOgg, "opus", 24000, 0),
+	pcmFormat(Raw48Khz16BitMonoPcm, "raw-48khz-16bit-mono-pcm", ContainerRaw, 48000),
+	pcmFormat(Riff48Khz16BitMonoPcm, "riff-48khz-16bit-mono-pcm", ContainerRiff, 48000),
+	compressedFormat(Audio48Khz96KBitRateMonoMp3, "audio-48khz-96kbitrate-mono-mp3", ContainerMp3, "mp3", 48000, 96000),
+	compressedFormat(Audio48Khz192KBitRateMonoMp3, "audio-48khz-192kbitrate-mono-mp3", ContainerMp3, "mp3", 48000, 192000),
+	compressedFormat(Ogg48Khz16BitMonoOpus, "ogg-48khz-16bit-mono-opus", ContainerOgg, "opus", 48000, 0),
+	compressedFormat(Webm16Khz16BitMonoOpus, "webm-16khz-16bit-mono-opus", ContainerWebm, "opus", 16000, 0),
+	compressedFormat(Webm24Khz16BitMonoOpus, "webm-24khz-16bit-mono-opus", ContainerWebm, "opus", 24000, 0),
+	compressedFormat(Raw24Khz16BitMonoTrueSilk, "raw-24khz-16bit-mono-truesilk", ContainerRaw, "silk", 24000, 0),
+	compressedFormat(Raw8Khz8BitMonoALaw, "raw-8khz-8bit-mono-alaw", ContainerALaw, "alaw", 8000, 64000),
+	compressedFormat(Riff8Khz8BitMonoALaw, "riff-8khz-8bit-mono-alaw", ContainerRiff, "alaw", 8000, 64000),
+	compressedFormat(Webm24Khz16Bit24KbpsMonoOpus, "webm-24khz-16bit-24kbps-mono-opus", ContainerWebm, "opus", 24000, 24000),
+	compressedFormat(Audio16Khz16Bit32KbpsMonoOpus, "audio-16khz-16bit-32kbps-mono-opus", ContainerOpus, "opus", 16000, 32000),
+	compressedFormat(Audio24Khz16Bit48KbpsMonoOpus, "audio-24khz-16bit-48kbps-mono-opus", ContainerOpus, "opus", 24000, 48000),
+	compressedFormat(Audio24Khz16Bit24KbpsMonoOpus, "audio-24khz-16bit-24kbps-mono-opus", ContainerOpus, "opus", 24000, 24000),
+	pcmFormat(Raw22050Hz16BitMonoPcm, "raw-22050hz-16bit-mono-pcm", ContainerRaw, 22050),
+	pcmFormat(Riff22050Hz16BitMonoPcm, "riff-22050hz-16bit-mono-pcm", ContainerRiff, 22050),
+	pcmFormat(Raw44100Hz16BitMonoPcm, "raw-44100hz-16bit-mono-pcm", ContainerRaw, 44100),
+	pcmFormat(Riff44100Hz16BitMonoPcm, "riff-44100hz-16bit-mono-pcm", ContainerRiff, 44100),
+	compressedFormat(AmrWb16000Hz, "amr-wb-16000hz", ContainerAmr, "amr-wb", 16000, 0),
+	compressedFormat(G72216Khz64Kbps, "g722-16khz-64kbps", ContainerG722, "g722", 16000, 64000),
+}
+
+// Describe returns the audio properties of the output format.
+// For an unknown format, only the Format field of the returned value is set.
+func (format SpeechSynthesisOutputFormat) Describe() SpeechSynthesisOutputFormatInfo {
+	for _, info := range speechSynthesisOutputFormatInfos {
+		if info.Format == format {
+			return info
+		}
+	}
+	return SpeechSynthesisOutputFormatInfo{Format: format}
+}
+
+// Name returns the name of the output format used by the service, e.g. riff-16khz-16bit-mono-pcm, or an empty string
+// for an unknown format.
+func (format SpeechSynthesisOutputFormat) Name() string {
+	return format.Describe().Name
+}
+
+// ParseSpeechSynthesisOutputFormat returns the output format with the given service name, e.g. riff-16khz-16bit-mono-pcm.
+// The name is matched case-insensitively.
+func ParseSpeechSynthesisOutputFormat(name string) (SpeechSynthesisOutputFormat, error) {
+	for _, info := range speechSynthesisOutputFormatInfos {
+		if strings.EqualFold(info.Name, name) {
+			return info.Format, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown speech synthesis output format %q", name)
+}
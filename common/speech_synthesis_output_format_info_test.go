@@ -0,0 +1,39 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpeechSynthesisOutputFormatDescribe(t *testing.T) {
+	for format := Raw8Khz8BitMonoMULaw; format <= G72216Khz64Kbps; format++ {
+		info := format.Describe()
+		if info.Format != format || info.Name == "" || info.SampleRate == 0 || info.Channels != 1 {
+			t.Errorf("Incomplete description for format %d: %+v", format, info)
+		}
+		if info.MimeType == "" || !strings.HasPrefix(info.FileExtension, ".") {
+			t.Errorf("Missing MIME type or extension for format %s", info.Name)
+		}
+		parsed, err := ParseSpeechSynthesisOutputFormat(strings.ToUpper(info.Name))
+		if err != nil || parsed != format {
+			t.Errorf("Parsing %s returned %d, %v", info.Name, parsed, err)
+		}
+	}
+	info := Riff24Khz16BitMonoPcm.Describe()
+	if info.Container != ContainerRiff || info.BitsPerSample != 16 || info.BitRate != 384000 || info.MimeType != "audio/wav" || !info.IsPCM() {
+		t.Error("Unexpected description: ", info)
+	}
+	info = Audio48Khz192KBitRateMonoMp3.Describe()
+	if info.Container != ContainerMp3 || info.Codec != "mp3" || info.BitRate != 192000 || info.FileExtension != ".mp3" || info.IsPCM() {
+		t.Error("Unexpected description: ", info)
+	}
+	if SpeechSynthesisOutputFormat(1000).Name() != "" {
+		t.Error("Unknown format should have no name")
+	}
+	if _, err := ParseSpeechSynthesisOutputFormat("riff-1khz-1bit-mono-pcm"); err == nil {
+		t.Error("Expected an error for an unknown format name")
+	}
+}
@@ -16,3 +16,5 @@ const (
 	// Male indicates male.
 	Male SynthesisVoiceGender = 2
 )
+
+//go:generate stringer -type=SynthesisVoiceGender -output=synthesis_voice_gender_string.go
@@ -18,4 +18,9 @@ const (
 
 	// OfflineStandard indicates offline started voice.
 	OfflineStandard SynthesisVoiceType = 4
+
+	// OnlineNeuralHD indicates online neural HD voice.
+	OnlineNeuralHD SynthesisVoiceType = 5
 )
+
+//go:generate stringer -type=SynthesisVoiceType -output=synthesis_voice_type_string.go
@@ -38,17 +38,17 @@ func SetMemoryLogFilters(filters string) {
 	logging.MemoryLogger.SetFilters(filters)
 }
 
-// Deprecated: Use logging.MemoryLogger.DumpToSlice() instead.
+// Deprecated: Use logging.MemoryLogSnapshot() instead.
 func GetMemoryLogLineNumOldest() uint {
 	return uint(C.diagnostics_log_memory_get_line_num_oldest())
 }
 
-// Deprecated: Use logging.MemoryLogger.DumpToSlice() instead.
+// Deprecated: Use logging.MemoryLogSnapshot() instead.
 func GetMemoryLogLineNumNewest() uint {
 	return uint(C.diagnostics_log_memory_get_line_num_newest())
 }
 
-// Deprecated: Use logging.MemoryLogger.DumpToSlice() instead.
+// Deprecated: Use logging.MemoryLogSnapshot() instead.
 func GetMemoryLogLine(lineNum uint) string {
 	cLine := C.diagnostics_log_memory_get_line(C.size_t(lineNum))
 	if cLine == nil {
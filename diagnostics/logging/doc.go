@@ -3,4 +3,7 @@
 
 // Package logging provides process-wide diagnostics logging for the Speech SDK.
 // It includes FileLogger, MemoryLogger, EventLogger, ConsoleLogger, and Trace* helpers.
+// Trace lines can be parsed into LogEvent values with ParseLogLine, and routed to log/slog with SlogCallback (Go 1.21+).
+// SessionLogCapture splits the trace lines by session, so that the log of a failed session can be inspected on its own.
+// MemoryLogSnapshot copies the memory log for filtering, iteration and tailing.
 package logging
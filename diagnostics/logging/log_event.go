@@ -0,0 +1,136 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEvent is a trace line of the native SDK, parsed into its parts.
+type LogEvent struct {
+	// Time is the wall-clock time of the trace when the line carries one, and otherwise the time it was parsed.
+	Time time.Time
+
+	// Elapsed is the time since the SDK started tracing, as reported by the line.
+	Elapsed time.Duration
+
+	// ThreadID is the identifier of the native thread that emitted the trace.
+	ThreadID uint64
+
+	// Level is the severity of the trace. Scope and debug traces are reported as Verbose.
+	Level Level
+
+	// Title is the trace title, e.g. SPX_TRACE_INFO.
+	Title string
+
+	// File is the name of the source file that emitted the trace.
+	File string
+
+	// Line is the line in the source file that emitted the trace.
+	Line int
+
+	// Component is the class that emitted the trace, e.g. CSpxAudioStreamSession, or the source file name without
+	// extension when the message does not name a class.
+	Component string
+
+	// SessionID is the session, or synthesis request, the trace refers to when the message mentions one.
+	SessionID string
+
+	// Message is the trace message.
+	Message string
+
+	// Raw is the unparsed line, without the trailing line break.
+	Raw string
+}
+
+var (
+	logLinePattern = regexp.MustCompile(`^\s*(?:(\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?)\s+)?` +
+		`[\[(](\d+)[\])]:?\s*(\d+)ms\s+(SPX_[A-Z_]+):\s*(\S+?):(\d+)\s?(.*)$`)
+	logComponentPattern = regexp.MustCompile(`\b(C[A-Z][A-Za-z0-9_]*)::`)
+	logSessionPattern   = regexp.MustCompile(`(?i)(?:session|request)[ _-]?id\W{0,3}([0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12})`)
+)
+
+// ParseLogLine parses a trace line as delivered to the EventLogger callback or written by the FileLogger and
+// MemoryLogger. When the line does not have the expected format, an error is returned together with an event whose
+// Message and Raw hold the line.
+func ParseLogLine(line string) (LogEvent, error) {
+	return parseLogLine(line, time.Now())
+}
+
+// parseLogLine parses a trace line, using readTime as the time of lines without wall-clock timestamp.
+func parseLogLine(line string, readTime time.Time) (LogEvent, error) {
+	raw := strings.TrimRight(line, "\r\n")
+	event := LogEvent{Time: readTime, Level: Info, Message: strings.TrimSpace(raw), Raw: raw}
+	match := logLinePattern.FindStringSubmatch(raw)
+	if match == nil {
+		return event, fmt.Errorf("unrecognized trace line %q", raw)
+	}
+	if match[1] != "" {
+		if wallClock, err := parseLogTime(match[1]); err == nil {
+			event.Time = wallClock
+		}
+	}
+	event.ThreadID, _ = strconv.ParseUint(match[2], 10, 64)
+	elapsed, _ := strconv.ParseInt(match[3], 10, 64)
+	event.Elapsed = time.Duration(elapsed) * time.Millisecond
+	event.Title = match[4]
+	event.Level = levelFromTitle(event.Title)
+	event.File = match[5]
+	event.Line, _ = strconv.Atoi(match[6])
+	event.Message = strings.TrimSpace(match[7])
+	if component := logComponentPattern.FindStringSubmatch(event.Message); component != nil {
+		event.Component = component[1]
+	} else {
+		event.Component = strings.TrimSuffix(filepath.Base(event.File), filepath.Ext(event.File))
+	}
+	if session := logSessionPattern.FindStringSubmatch(event.Message); session != nil {
+		event.SessionID = session[1]
+	}
+	return event, nil
+}
+
+func parseLogTime(value string) (time.Time, error) {
+	value = strings.Replace(value, " ", "T", 1)
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05.999999999", value, time.Local)
+}
+
+func levelFromTitle(title string) Level {
+	switch {
+	case strings.HasSuffix(title, "_ERROR"):
+		return Error
+	case strings.HasSuffix(title, "_WARNING"):
+		return Warning
+	case strings.HasSuffix(title, "_INFO"):
+		return Info
+	default:
+		return Verbose
+	}
+}
+
+// String returns the event in a compact single-line form.
+func (event LogEvent) String() string {
+	return fmt.Sprintf("%s [%d] %s %s:%d %s", event.Level, event.ThreadID, event.Component, event.File, event.Line, event.Message)
+}
+
+// EventCallbackFunc adapts a handler of parsed events to an EventCallback for EventLogger.SetCallback.
+// Lines that cannot be parsed are delivered with only Message and Raw set.
+func EventCallbackFunc(handler func(event LogEvent)) EventCallback {
+	return func(message string) {
+		for _, line := range strings.Split(strings.TrimRight(message, "\r\n"), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			event, _ := ParseLogLine(line)
+			handler(event)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLogLine(t *testing.T) {
+	line := "[20648]: 1523ms SPX_TRACE_INFO:  audio_stream_session.cpp:1264 [0x7f2c4c0010f0] CSpxAudioStreamSession::StartRecognizing: SessionId: 3f2504e04f8941d39a0c0305e82c3301\n"
+	event, err := ParseLogLine(line)
+	if err != nil {
+		t.Fatalf("ParseLogLine: %v", err)
+	}
+	if event.ThreadID != 20648 || event.Elapsed != 1523*time.Millisecond {
+		t.Errorf("thread/elapsed = %d/%v, want 20648/1.523s", event.ThreadID, event.Elapsed)
+	}
+	if event.Level != Info || event.Title != "SPX_TRACE_INFO" {
+		t.Errorf("level/title = %v/%q, want info/SPX_TRACE_INFO", event.Level, event.Title)
+	}
+	if event.File != "audio_stream_session.cpp" || event.Line != 1264 {
+		t.Errorf("source = %s:%d, want audio_stream_session.cpp:1264", event.File, event.Line)
+	}
+	if event.Component != "CSpxAudioStreamSession" {
+		t.Errorf("component = %q, want CSpxAudioStreamSession", event.Component)
+	}
+	if event.SessionID != "3f2504e04f8941d39a0c0305e82c3301" {
+		t.Errorf("session ID = %q", event.SessionID)
+	}
+	if event.Raw != line[:len(line)-1] {
+		t.Errorf("raw = %q", event.Raw)
+	}
+}
+
+func TestParseLogLineLevelsAndComponents(t *testing.T) {
+	tests := []struct {
+		line      string
+		level     Level
+		component string
+	}{
+		{"(42): 0ms SPX_DBG_TRACE_VERBOSE:  site_helpers.h:62 creating object", Verbose, "site_helpers"},
+		{"[7]: 3ms SPX_TRACE_SCOPE_ENTER:  speechapi_c_factory.cpp:61 speech_config_from_subscription", Verbose, "speechapi_c_factory"},
+		{"[7]: 3ms SPX_TRACE_ERROR:  usp_reco_engine_adapter.cpp:912 CSpxUspRecoEngineAdapter::OnError: connection failed", Error, "CSpxUspRecoEngineAdapter"},
+		{"[7]: 3ms SPX_TRACE_WARNING:  /src/go/speech/recognizer.go:40 slow callback", Warning, "recognizer"},
+	}
+	for _, tc := range tests {
+		event, err := ParseLogLine(tc.line)
+		if err != nil {
+			t.Errorf("ParseLogLine(%q): %v", tc.line, err)
+			continue
+		}
+		if event.Level != tc.level || event.Component != tc.component {
+			t.Errorf("ParseLogLine(%q) = %v/%q, want %v/%q", tc.line, event.Level, event.Component, tc.level, tc.component)
+		}
+	}
+}
+
+func TestParseLogLineWallClock(t *testing.T) {
+	event, err := ParseLogLine("2024-05-01T10:20:30.250Z [1]: 10ms SPX_TRACE_INFO:  a.cpp:1 hello")
+	if err != nil {
+		t.Fatalf("ParseLogLine: %v", err)
+	}
+	want := time.Date(2024, 5, 1, 10, 20, 30, 250000000, time.UTC)
+	if !event.Time.Equal(want) || event.Message != "hello" {
+		t.Errorf("time/message = %v/%q, want %v/hello", event.Time, event.Message, want)
+	}
+}
+
+func TestParseLogLineUnrecognized(t *testing.T) {
+	event, err := ParseLogLine("not a trace line\r\n")
+	if err == nil {
+		t.Fatal("expected error for unrecognized line")
+	}
+	if event.Message != "not a trace line" || event.Raw != "not a trace line" {
+		t.Errorf("message/raw = %q/%q", event.Message, event.Raw)
+	}
+}
+
+func TestEventCallbackFunc(t *testing.T) {
+	var events []LogEvent
+	callback := EventCallbackFunc(func(event LogEvent) {
+		events = append(events, event)
+	})
+	callback("[1]: 1ms SPX_TRACE_INFO:  a.cpp:1 first\n[1]: 2ms SPX_TRACE_ERROR:  a.cpp:2 second\n")
+	if len(events) != 2 || events[0].Message != "first" || events[1].Level != Error {
+		t.Errorf("events = %v", events)
+	}
+}
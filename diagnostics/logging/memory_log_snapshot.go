@@ -0,0 +1,225 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package logging
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryLogSource abstracts the native ring buffer, whose line numbers grow monotonically.
+type memoryLogSource interface {
+	oldest() uint
+	newest() uint
+	line(lineNum uint) (string, bool)
+}
+
+var (
+	memoryLogMu sync.Mutex
+	memoryLog   memoryLogSource = nativeMemoryLog{}
+)
+
+// TailPollInterval is how often LogSnapshot.Tail checks the memory log for new lines.
+var TailPollInterval = 100 * time.Millisecond
+
+// LogSnapshot is a consistent copy of the memory log.
+type LogSnapshot struct {
+	// Time is when the snapshot was taken.
+	Time time.Time
+
+	// FirstLine is the line number of the first line in Lines.
+	FirstLine uint
+
+	// Lines are the copied lines, oldest first.
+	Lines []string
+}
+
+// MemoryLogSnapshot copies the lines currently held by the memory logger (see MemoryLogger.Start).
+// Lines overwritten by the ring buffer while copying are left out, so the snapshot is contiguous.
+func MemoryLogSnapshot() *LogSnapshot {
+	memoryLogMu.Lock()
+	defer memoryLogMu.Unlock()
+	return readMemoryLog(memoryLog, memoryLog.oldest())
+}
+
+// readMemoryLog copies the lines from line number start to the newest line. After copying, the lines that the ring
+// buffer recycled in the meantime are dropped, as their content may belong to newer lines.
+func readMemoryLog(source memoryLogSource, start uint) *LogSnapshot {
+	snapshot := &LogSnapshot{Time: time.Now()}
+	if oldest := source.oldest(); start < oldest {
+		start = oldest
+	}
+	stop := source.newest()
+	if stop < start {
+		stop = start
+	}
+	lines := make([]string, 0, stop-start)
+	for i := start; i < stop; i++ {
+		line, ok := source.line(i)
+		if !ok {
+			line = ""
+		}
+		lines = append(lines, line)
+	}
+	if oldest := source.oldest(); oldest > start {
+		skip := oldest - start
+		if skip > uint(len(lines)) {
+			skip = uint(len(lines))
+		}
+		lines = lines[skip:]
+		start += skip
+	}
+	snapshot.FirstLine = start
+	snapshot.Lines = lines
+	return snapshot
+}
+
+// NextLine is the line number following the last line of the snapshot.
+func (snapshot *LogSnapshot) NextLine() uint {
+	return snapshot.FirstLine + uint(len(snapshot.Lines))
+}
+
+// Events parses the lines of the snapshot that match the filter.
+func (snapshot *LogSnapshot) Events(filter LogFilter) []LogEvent {
+	var events []LogEvent
+	iterator := snapshot.Iterator(filter)
+	for iterator.Next() {
+		events = append(events, iterator.Event())
+	}
+	return events
+}
+
+// Iterator returns an iterator over the lines of the snapshot that match the filter.
+func (snapshot *LogSnapshot) Iterator(filter LogFilter) *LogIterator {
+	return &LogIterator{snapshot: snapshot, filter: filter, index: -1}
+}
+
+// WriteTo writes the lines of the snapshot to w, implementing io.WriterTo.
+func (snapshot *LogSnapshot) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for _, line := range snapshot.Lines {
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+		n, err := io.WriteString(w, line)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Tail delivers the lines added to the memory log after the snapshot and matching the filter, as they arrive.
+// The channel is closed when ctx is done. Lines recycled by the ring buffer before they could be read are skipped.
+func (snapshot *LogSnapshot) Tail(ctx context.Context, filter LogFilter) <-chan LogEvent {
+	events := make(chan LogEvent)
+	go func() {
+		defer close(events)
+		next := snapshot.NextLine()
+		ticker := time.NewTicker(TailPollInterval)
+		defer ticker.Stop()
+		for {
+			memoryLogMu.Lock()
+			update := readMemoryLog(memoryLog, next)
+			memoryLogMu.Unlock()
+			next = update.NextLine()
+			iterator := update.Iterator(filter)
+			for iterator.Next() {
+				select {
+				case events <- iterator.Event():
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// LogFilter selects log events. The zero value matches every event.
+type LogFilter struct {
+	// Level is the most verbose level to include, e.g. Warning includes errors and warnings. Zero includes all levels.
+	Level Level
+
+	// Contains, if not empty, is a substring the raw line must contain.
+	Contains string
+
+	// Pattern, if not nil, must match the raw line.
+	Pattern *regexp.Regexp
+
+	// Since and Until, if not zero, bound the time of the event. Lines without a wall-clock timestamp carry the time
+	// they were read.
+	Since time.Time
+	Until time.Time
+}
+
+// Match checks whether the event passes the filter.
+func (filter LogFilter) Match(event LogEvent) bool {
+	if filter.Level != 0 && event.Level > filter.Level {
+		return false
+	}
+	if filter.Contains != "" && !strings.Contains(event.Raw, filter.Contains) {
+		return false
+	}
+	if filter.Pattern != nil && !filter.Pattern.MatchString(event.Raw) {
+		return false
+	}
+	if !filter.Since.IsZero() && event.Time.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && event.Time.After(filter.Until) {
+		return false
+	}
+	return true
+}
+
+// LogIterator iterates over the matching lines of a snapshot:
+//
+//	iterator := snapshot.Iterator(logging.LogFilter{Level: logging.Warning})
+//	for iterator.Next() {
+//		fmt.Println(iterator.Event())
+//	}
+type LogIterator struct {
+	snapshot *LogSnapshot
+	filter   LogFilter
+	index    int
+	event    LogEvent
+}
+
+// Next advances to the next matching line, returning false when there are no more.
+func (iterator *LogIterator) Next() bool {
+	for iterator.index+1 < len(iterator.snapshot.Lines) {
+		iterator.index++
+		line := iterator.snapshot.Lines[iterator.index]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		event, _ := parseLogLine(line, iterator.snapshot.Time)
+		if iterator.filter.Match(event) {
+			iterator.event = event
+			return true
+		}
+	}
+	return false
+}
+
+// Event returns the current line.
+func (iterator *LogIterator) Event() LogEvent {
+	return iterator.event
+}
+
+// LineNumber returns the line number of the current line in the memory log.
+func (iterator *LogIterator) LineNumber() uint {
+	return iterator.snapshot.FirstLine + uint(iterator.index)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMemoryLog is a ring buffer of the given capacity, optionally appending lines whenever a line is read to
+// simulate concurrent logging.
+type fakeMemoryLog struct {
+	mu           sync.Mutex
+	capacity     uint
+	lines        map[uint]string
+	next         uint
+	appendOnRead int
+}
+
+func (log *fakeMemoryLog) add(line string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.addLocked(line)
+}
+
+func (log *fakeMemoryLog) addLocked(line string) {
+	log.lines[log.next] = line
+	log.next++
+	if log.next > log.capacity {
+		delete(log.lines, log.next-log.capacity-1)
+	}
+}
+
+func (log *fakeMemoryLog) oldest() uint {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if log.next > log.capacity {
+		return log.next - log.capacity
+	}
+	return 0
+}
+
+func (log *fakeMemoryLog) newest() uint {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	return log.next
+}
+
+func (log *fakeMemoryLog) line(lineNum uint) (string, bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	line, ok := log.lines[lineNum]
+	for i := 0; i < log.appendOnRead; i++ {
+		log.addLocked(fmt.Sprintf("[9]: 0ms SPX_TRACE_VERBOSE:  noise.cpp:1 noise %d", log.next))
+	}
+	return line, ok
+}
+
+// useFakeMemoryLog replaces the native memory log, returning the fake and a function restoring the native log.
+func useFakeMemoryLog(capacity uint) (*fakeMemoryLog, func()) {
+	fake := &fakeMemoryLog{capacity: capacity, lines: make(map[uint]string)}
+	memoryLogMu.Lock()
+	previous := memoryLog
+	memoryLog = fake
+	memoryLogMu.Unlock()
+	return fake, func() {
+		memoryLogMu.Lock()
+		memoryLog = previous
+		memoryLogMu.Unlock()
+	}
+}
+
+func TestMemoryLogSnapshotFilters(t *testing.T) {
+	fake, restore := useFakeMemoryLog(3)
+	defer restore()
+	fake.add("[1]: 1ms SPX_TRACE_INFO:  a.cpp:1 dropped by the ring buffer")
+	fake.add("[1]: 2ms SPX_TRACE_ERROR:  a.cpp:2 connection failed")
+	fake.add("[1]: 3ms SPX_TRACE_VERBOSE:  a.cpp:3 audio chunk 42")
+	fake.add("2024-05-01T10:00:00Z [1]: 4ms SPX_TRACE_WARNING:  a.cpp:4 slow network")
+
+	snapshot := MemoryLogSnapshot()
+	if snapshot.FirstLine != 1 || len(snapshot.Lines) != 3 || snapshot.NextLine() != 4 {
+		t.Fatalf("snapshot = %d/%v", snapshot.FirstLine, snapshot.Lines)
+	}
+	if events := snapshot.Events(LogFilter{Level: Warning}); len(events) != 2 {
+		t.Errorf("warning events = %v", events)
+	}
+	if events := snapshot.Events(LogFilter{Contains: "chunk"}); len(events) != 1 || events[0].Line != 3 {
+		t.Errorf("substring events = %v", events)
+	}
+	if events := snapshot.Events(LogFilter{Pattern: regexp.MustCompile(`chunk \d+`)}); len(events) != 1 {
+		t.Errorf("regexp events = %v", events)
+	}
+	until := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if events := snapshot.Events(LogFilter{Until: until}); len(events) != 1 || events[0].Level != Warning {
+		t.Errorf("time range events = %v", events)
+	}
+	iterator := snapshot.Iterator(LogFilter{Level: Error})
+	if !iterator.Next() || iterator.LineNumber() != 1 || iterator.Next() {
+		t.Errorf("iterator stopped at line %d", iterator.LineNumber())
+	}
+
+	var output bytes.Buffer
+	written, err := snapshot.WriteTo(&output)
+	if err != nil || written != int64(output.Len()) || bytes.Count(output.Bytes(), []byte("\n")) != 3 {
+		t.Errorf("WriteTo = %d, %v: %q", written, err, output.String())
+	}
+}
+
+func TestMemoryLogSnapshotConcurrentWrites(t *testing.T) {
+	fake, restore := useFakeMemoryLog(4)
+	defer restore()
+	for i := 0; i < 4; i++ {
+		fake.add(fmt.Sprintf("[1]: %dms SPX_TRACE_INFO:  a.cpp:1 line %d", i, i))
+	}
+	fake.appendOnRead = 1
+	snapshot := MemoryLogSnapshot()
+	for i, line := range snapshot.Lines {
+		if want := fmt.Sprintf("line %d", snapshot.FirstLine+uint(i)); !bytes.Contains([]byte(line), []byte(want)) {
+			t.Errorf("line %d = %q, want it to contain %q", snapshot.FirstLine+uint(i), line, want)
+		}
+	}
+}
+
+func TestMemoryLogTail(t *testing.T) {
+	fake, restore := useFakeMemoryLog(100)
+	defer restore()
+	fake.add("[1]: 1ms SPX_TRACE_INFO:  a.cpp:1 before snapshot")
+	snapshot := MemoryLogSnapshot()
+	previousInterval := TailPollInterval
+	TailPollInterval = time.Millisecond
+	defer func() { TailPollInterval = previousInterval }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := snapshot.Tail(ctx, LogFilter{Contains: "tail"})
+	fake.add("[1]: 2ms SPX_TRACE_INFO:  a.cpp:2 tail one")
+	fake.add("[1]: 3ms SPX_TRACE_INFO:  a.cpp:3 ignored")
+	fake.add("[1]: 4ms SPX_TRACE_INFO:  a.cpp:4 tail two")
+	for _, want := range []string{"tail one", "tail two"} {
+		select {
+		case event := <-events:
+			if event.Message != want {
+				t.Errorf("tail event = %q, want %q", event.Message, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %q", want)
+		}
+	}
+	cancel()
+	for range events {
+	}
+}
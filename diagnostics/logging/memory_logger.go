@@ -15,6 +15,25 @@ import (
 
 type memoryLogger struct{}
 
+// nativeMemoryLog reads the ring buffer of the native memory logger.
+type nativeMemoryLog struct{}
+
+func (nativeMemoryLog) oldest() uint {
+	return uint(C.diagnostics_log_memory_get_line_num_oldest())
+}
+
+func (nativeMemoryLog) newest() uint {
+	return uint(C.diagnostics_log_memory_get_line_num_newest())
+}
+
+func (nativeMemoryLog) line(lineNum uint) (string, bool) {
+	cLine := C.diagnostics_log_memory_get_line(C.size_t(lineNum))
+	if cLine == nil {
+		return "", false
+	}
+	return C.GoString(cLine), true
+}
+
 // MemoryLogger is the process-wide memory logging singleton.
 var MemoryLogger memoryLogger
 
@@ -0,0 +1,256 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package logging
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Default bounds of a SessionLogCapture.
+const (
+	DefaultSessionLogLines = 500
+	DefaultSessionLogCount = 256
+)
+
+// SessionLog is the log captured for a single session.
+type SessionLog struct {
+	// SessionID is the identifier of the session.
+	SessionID string
+
+	// Events are the captured trace lines, oldest first.
+	Events []LogEvent
+
+	// Dropped is the number of older lines discarded because the buffer was full.
+	Dropped int
+}
+
+// String returns the captured lines, one per line.
+func (log SessionLog) String() string {
+	var builder strings.Builder
+	for _, event := range log.Events {
+		builder.WriteString(event.Raw)
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+type sessionBuffer struct {
+	log     SessionLog
+	objects []string
+}
+
+// SessionLogCapture splits the trace lines of the SDK by session. A line is attributed to a session when it mentions
+// the session ID, or when it mentions a native object (e.g. [0x7f2c4c0010f0]) that appeared in a line attributed to the
+// session. Each session keeps its most recent lines, and the least recently started sessions are evicted first.
+type SessionLogCapture struct {
+	// Forward, if set, also receives every trace line, e.g. to keep another EventLogger consumer working.
+	Forward EventCallback
+
+	maxLines    int
+	maxSessions int
+
+	mu       sync.Mutex
+	sessions map[string]*sessionBuffer
+	order    []string
+	objects  map[string]string
+}
+
+var logObjectPattern = regexp.MustCompile(`0x[0-9a-fA-F]{6,16}`)
+
+// NewSessionLogCapture creates a capture keeping up to maxLines lines for each of up to maxSessions sessions.
+// Non-positive values select DefaultSessionLogLines and DefaultSessionLogCount.
+func NewSessionLogCapture(maxLines int, maxSessions int) *SessionLogCapture {
+	if maxLines <= 0 {
+		maxLines = DefaultSessionLogLines
+	}
+	if maxSessions <= 0 {
+		maxSessions = DefaultSessionLogCount
+	}
+	return &SessionLogCapture{
+		maxLines:    maxLines,
+		maxSessions: maxSessions,
+		sessions:    make(map[string]*sessionBuffer),
+		objects:     make(map[string]string),
+	}
+}
+
+// Callback returns the EventCallback feeding the capture, for use with EventLogger.SetCallback.
+func (capture *SessionLogCapture) Callback() EventCallback {
+	parse := EventCallbackFunc(capture.Handle)
+	return func(message string) {
+		parse(message)
+		if forward := capture.Forward; forward != nil {
+			forward(message)
+		}
+	}
+}
+
+// Handle attributes a parsed trace line to its session, if any.
+func (capture *SessionLogCapture) Handle(event LogEvent) {
+	objects := logObjectPattern.FindAllString(event.Message, -1)
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	sessionID := normalizeSessionID(event.SessionID)
+	if sessionID == "" {
+		for _, object := range objects {
+			if owner, ok := capture.objects[object]; ok {
+				sessionID = owner
+				break
+			}
+		}
+	}
+	if sessionID == "" {
+		return
+	}
+	buffer := capture.session(sessionID)
+	for _, object := range objects {
+		if _, ok := capture.objects[object]; !ok {
+			capture.objects[object] = sessionID
+			buffer.objects = append(buffer.objects, object)
+		}
+	}
+	buffer.log.Events = append(buffer.log.Events, event)
+	if excess := len(buffer.log.Events) - capture.maxLines; excess > 0 {
+		buffer.log.Events = append(buffer.log.Events[:0:0], buffer.log.Events[excess:]...)
+		buffer.log.Dropped += excess
+	}
+}
+
+func (capture *SessionLogCapture) session(sessionID string) *sessionBuffer {
+	if buffer, ok := capture.sessions[sessionID]; ok {
+		return buffer
+	}
+	if len(capture.order) >= capture.maxSessions {
+		capture.release(capture.order[0])
+	}
+	buffer := &sessionBuffer{log: SessionLog{SessionID: sessionID}}
+	capture.sessions[sessionID] = buffer
+	capture.order = append(capture.order, sessionID)
+	return buffer
+}
+
+// Log returns a copy of the log captured for the session.
+func (capture *SessionLogCapture) Log(sessionID string) (SessionLog, bool) {
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	buffer, ok := capture.sessions[normalizeSessionID(sessionID)]
+	if !ok {
+		return SessionLog{SessionID: sessionID}, false
+	}
+	log := buffer.log
+	log.Events = append([]LogEvent(nil), buffer.log.Events...)
+	return log, true
+}
+
+// Release discards the log captured for the session.
+func (capture *SessionLogCapture) Release(sessionID string) {
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	capture.release(normalizeSessionID(sessionID))
+}
+
+func (capture *SessionLogCapture) release(sessionID string) {
+	buffer, ok := capture.sessions[sessionID]
+	if !ok {
+		return
+	}
+	for _, object := range buffer.objects {
+		delete(capture.objects, object)
+	}
+	delete(capture.sessions, sessionID)
+	for i, id := range capture.order {
+		if id == sessionID {
+			capture.order = append(capture.order[:i], capture.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// normalizeSessionID makes the GUID forms with and without dashes, in any case, compare equal.
+func normalizeSessionID(sessionID string) string {
+	return strings.ToLower(strings.Replace(sessionID, "-", "", -1))
+}
+
+var (
+	activeCaptureMu sync.Mutex
+	activeCapture   *SessionLogCapture
+)
+
+// EnableSessionLogCapture routes the EventLogger output to capture and makes it the capture used to attach logs to
+// sessions canceled with an error. Pass nil to disable the capture and unregister the callback.
+func EnableSessionLogCapture(capture *SessionLogCapture) error {
+	activeCaptureMu.Lock()
+	defer activeCaptureMu.Unlock()
+	var err error
+	if capture != nil {
+		err = EventLogger.SetCallback(capture.Callback())
+	} else {
+		err = EventLogger.SetCallback(nil)
+	}
+	if err != nil {
+		return err
+	}
+	activeCapture = capture
+	return nil
+}
+
+// ActiveSessionLogCapture returns the capture enabled with EnableSessionLogCapture, or nil.
+func ActiveSessionLogCapture() *SessionLogCapture {
+	activeCaptureMu.Lock()
+	defer activeCaptureMu.Unlock()
+	return activeCapture
+}
+
+// CapturedSessionLog returns the log captured for the session by the active capture, or nil if none was captured.
+func CapturedSessionLog(sessionID string) *SessionLog {
+	capture := ActiveSessionLogCapture()
+	if capture == nil || sessionID == "" {
+		return nil
+	}
+	log, ok := capture.Log(sessionID)
+	if !ok {
+		return nil
+	}
+	return &log
+}
+
+// SessionLogError is an error carrying the log captured for the session that failed.
+type SessionLogError struct {
+	Err error
+	Log SessionLog
+}
+
+func (e *SessionLogError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *SessionLogError) Unwrap() error {
+	return e.Err
+}
+
+// WithSessionLog attaches the log captured for the session to err. It returns err unchanged when err is nil or no
+// log was captured.
+func WithSessionLog(err error, sessionID string) error {
+	if err == nil {
+		return nil
+	}
+	log := CapturedSessionLog(sessionID)
+	if log == nil {
+		return err
+	}
+	return &SessionLogError{Err: err, Log: *log}
+}
+
+// SessionLogFromError returns the session log attached to err or to any error it wraps.
+func SessionLogFromError(err error) (SessionLog, bool) {
+	var logError *SessionLogError
+	if errors.As(err, &logError) {
+		return logError.Log, true
+	}
+	return SessionLog{}, false
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const (
+	testSessionA = "3f2504e04f8941d39a0c0305e82c3301"
+	testSessionB = "9a0c0305e82c33013f2504e04f8941d3"
+)
+
+func TestSessionLogCaptureCorrelation(t *testing.T) {
+	capture := NewSessionLogCapture(10, 10)
+	callback := capture.Callback()
+	callback("[1]: 1ms SPX_TRACE_INFO:  session.cpp:10 [0x7f0000001000] CSpxAudioStreamSession::Start: SessionId: " + testSessionA + "\n")
+	callback("[2]: 2ms SPX_TRACE_INFO:  session.cpp:10 [0x7f0000002000] CSpxAudioStreamSession::Start: SessionId: " + testSessionB + "\n")
+	callback("[1]: 3ms SPX_TRACE_ERROR:  usp.cpp:20 [0x7f0000001000] CSpxUspRecoEngineAdapter::OnError: connection failed\n")
+	callback("[3]: 4ms SPX_TRACE_INFO:  other.cpp:1 unrelated\n")
+
+	log, ok := capture.Log(strings.ToUpper(testSessionA))
+	if !ok {
+		t.Fatal("expected a log for session A")
+	}
+	if len(log.Events) != 2 || log.Events[1].Level != Error {
+		t.Errorf("session A events = %v", log.Events)
+	}
+	if !strings.Contains(log.String(), "connection failed") || strings.Contains(log.String(), "unrelated") {
+		t.Errorf("session A log = %q", log.String())
+	}
+	if log, _ = capture.Log(testSessionB); len(log.Events) != 1 {
+		t.Errorf("session B events = %v", log.Events)
+	}
+
+	capture.Release(testSessionA)
+	if _, ok = capture.Log(testSessionA); ok {
+		t.Error("expected session A to be released")
+	}
+	callback("[1]: 5ms SPX_TRACE_INFO:  usp.cpp:21 [0x7f0000001000] late line\n")
+	if _, ok = capture.Log(testSessionA); ok {
+		t.Error("expected lines of released objects to be dropped")
+	}
+}
+
+func TestSessionLogCaptureBounds(t *testing.T) {
+	capture := NewSessionLogCapture(3, 2)
+	for i := 0; i < 5; i++ {
+		capture.Handle(LogEvent{SessionID: testSessionA, Message: fmt.Sprint(i), Raw: fmt.Sprint(i)})
+	}
+	log, _ := capture.Log(testSessionA)
+	if len(log.Events) != 3 || log.Dropped != 2 || log.Events[0].Message != "2" {
+		t.Errorf("events/dropped = %v/%d, want 2..4/2", log.Events, log.Dropped)
+	}
+
+	capture.Handle(LogEvent{SessionID: testSessionB})
+	capture.Handle(LogEvent{SessionID: "00000000-0000-0000-0000-000000000000"})
+	if _, ok := capture.Log(testSessionA); ok {
+		t.Error("expected the oldest session to be evicted")
+	}
+	if _, ok := capture.Log("00000000000000000000000000000000"); !ok {
+		t.Error("expected session IDs to match with and without dashes")
+	}
+}
+
+func TestSessionLogError(t *testing.T) {
+	base := errors.New("canceled")
+	if err := WithSessionLog(base, testSessionA); err != base {
+		t.Errorf("WithSessionLog without capture = %v, want the original error", err)
+	}
+	wrapped := fmt.Errorf("recognize: %w", &SessionLogError{Err: base, Log: SessionLog{SessionID: testSessionA}})
+	log, ok := SessionLogFromError(wrapped)
+	if !ok || log.SessionID != testSessionA || !errors.Is(wrapped, base) {
+		t.Errorf("SessionLogFromError = %v/%v", log, ok)
+	}
+}
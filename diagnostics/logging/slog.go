@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogLevel returns the slog level matching the level. Verbose maps to slog.LevelDebug.
+func (l Level) SlogLevel() slog.Level {
+	switch l {
+	case Error:
+		return slog.LevelError
+	case Warning:
+		return slog.LevelWarn
+	case Info:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// Attrs returns the fields of the event as slog attributes. Empty fields are omitted.
+func (event LogEvent) Attrs() []slog.Attr {
+	attrs := make([]slog.Attr, 0, 7)
+	if event.Title == "" {
+		return append(attrs, slog.String("raw", event.Raw))
+	}
+	attrs = append(attrs,
+		slog.Uint64("thread_id", event.ThreadID),
+		slog.Duration("elapsed", event.Elapsed),
+		slog.String("title", event.Title),
+		slog.String("file", event.File),
+		slog.Int("line", event.Line),
+		slog.String("component", event.Component))
+	if event.SessionID != "" {
+		attrs = append(attrs, slog.String("session_id", event.SessionID))
+	}
+	return attrs
+}
+
+// SlogCallback returns an EventCallback that logs each trace line of the SDK to logger, with the level and
+// attributes of the parsed event. A nil logger logs to slog.Default().
+//
+//	logging.EventLogger.SetCallback(logging.SlogCallback(logger.With("source", "speechsdk")))
+func SlogCallback(logger *slog.Logger) EventCallback {
+	return EventCallbackFunc(func(event LogEvent) {
+		target := logger
+		if target == nil {
+			target = slog.Default()
+		}
+		ctx := context.Background()
+		level := event.Level.SlogLevel()
+		if !target.Enabled(ctx, level) {
+			return
+		}
+		record := slog.NewRecord(event.Time, level, event.Message, 0)
+		record.AddAttrs(event.Attrs()...)
+		_ = target.Handler().Handle(ctx, record)
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogCallback(t *testing.T) {
+	var output bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	callback := SlogCallback(logger)
+	callback("[5]: 8ms SPX_TRACE_VERBOSE:  a.cpp:1 filtered out\n")
+	callback("[5]: 9ms SPX_TRACE_WARNING:  recognizer.cpp:77 CSpxRecognizer::Fire: SessionId=3f2504e0-4f89-41d3-9a0c-0305e82c3301 retrying\n")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", output.String(), err)
+	}
+	if record["level"] != "WARN" || record["component"] != "CSpxRecognizer" || record["line"] != float64(77) {
+		t.Errorf("record = %v", record)
+	}
+	if record["session_id"] != "3f2504e0-4f89-41d3-9a0c-0305e82c3301" || record["thread_id"] != float64(5) {
+		t.Errorf("record = %v", record)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package activity
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Type is the type of a Bot Framework activity.
+type Type string
+
+const (
+	// Message is an activity carrying text, speech and attachments.
+	Message Type = "message"
+
+	// Event is an activity carrying a named value, typically used for programmatic signals.
+	Event Type = "event"
+
+	// Typing indicates that the sender is preparing a response.
+	Typing Type = "typing"
+
+	// EndOfConversation indicates that the conversation has ended.
+	EndOfConversation Type = "endOfConversation"
+)
+
+// InputHint tells the client whether the bot expects a response to the activity.
+type InputHint string
+
+const (
+	// AcceptingInput indicates that the bot is passively ready for input but is not waiting for a response.
+	AcceptingInput InputHint = "acceptingInput"
+
+	// ExpectingInput indicates that the bot is actively expecting a response from the user.
+	ExpectingInput InputHint = "expectingInput"
+
+	// IgnoringInput indicates that the bot is not ready to receive input.
+	IgnoringInput InputHint = "ignoringInput"
+)
+
+// ChannelAccount identifies the sender or the recipient of an activity.
+type ChannelAccount struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Role string `json:"role,omitempty"`
+}
+
+// ConversationAccount identifies the conversation an activity belongs to.
+type ConversationAccount struct {
+	ID               string `json:"id,omitempty"`
+	Name             string `json:"name,omitempty"`
+	IsGroup          bool   `json:"isGroup,omitempty"`
+	ConversationType string `json:"conversationType,omitempty"`
+}
+
+// CardAction is a clickable action, e.g. a suggested reply.
+type CardAction struct {
+	Type        string      `json:"type"`
+	Title       string      `json:"title,omitempty"`
+	Image       string      `json:"image,omitempty"`
+	Text        string      `json:"text,omitempty"`
+	DisplayText string      `json:"displayText,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+}
+
+// SuggestedActions are actions the user can choose from to reply to an activity.
+type SuggestedActions struct {
+	To      []string     `json:"to,omitempty"`
+	Actions []CardAction `json:"actions"`
+}
+
+// Attachment is a media file or card attached to an activity.
+type Attachment struct {
+	ContentType  string          `json:"contentType"`
+	ContentURL   string          `json:"contentUrl,omitempty"`
+	Content      json.RawMessage `json:"content,omitempty"`
+	Name         string          `json:"name,omitempty"`
+	ThumbnailURL string          `json:"thumbnailUrl,omitempty"`
+}
+
+// Activity is a Bot Framework activity. Fields that are not modeled explicitly are kept in Extra, so that
+// a parsed activity serializes back without losing information.
+type Activity struct {
+	Type             Type                 `json:"type,omitempty"`
+	ID               string               `json:"id,omitempty"`
+	Timestamp        *time.Time           `json:"timestamp,omitempty"`
+	ChannelID        string               `json:"channelId,omitempty"`
+	From             *ChannelAccount      `json:"from,omitempty"`
+	Recipient        *ChannelAccount      `json:"recipient,omitempty"`
+	Conversation     *ConversationAccount `json:"conversation,omitempty"`
+	ReplyToID        string               `json:"replyToId,omitempty"`
+	Locale           string               `json:"locale,omitempty"`
+	Text             string               `json:"text,omitempty"`
+	TextFormat       string               `json:"textFormat,omitempty"`
+	Speak            string               `json:"speak,omitempty"`
+	InputHint        InputHint            `json:"inputHint,omitempty"`
+	SuggestedActions *SuggestedActions    `json:"suggestedActions,omitempty"`
+	Attachments      []Attachment         `json:"attachments,omitempty"`
+	AttachmentLayout string               `json:"attachmentLayout,omitempty"`
+	Name             string               `json:"name,omitempty"`
+	Value            json.RawMessage      `json:"value,omitempty"`
+	Code             string               `json:"code,omitempty"`
+	ChannelData      json.RawMessage      `json:"channelData,omitempty"`
+
+	// Extra holds the members of the activity JSON that have no corresponding field.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// NewMessage creates a message activity with the given text.
+func NewMessage(text string) *Activity {
+	return &Activity{Type: Message, Text: text}
+}
+
+// NewEvent creates an event activity with the given name. The value is serialized to JSON; a nil value is omitted.
+func NewEvent(name string, value interface{}) (*Activity, error) {
+	activity := &Activity{Type: Event, Name: name}
+	if value != nil {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		activity.Value = data
+	}
+	return activity, nil
+}
+
+// NewTyping creates a typing activity.
+func NewTyping() *Activity {
+	return &Activity{Type: Typing}
+}
+
+// NewEndOfConversation creates an endOfConversation activity with the given code, e.g. completedSuccessfully.
+func NewEndOfConversation(code string) *Activity {
+	return &Activity{Type: EndOfConversation, Code: code}
+}
+
+// Parse parses an activity from its JSON representation.
+func Parse(activityJSON string) (*Activity, error) {
+	activity := new(Activity)
+	if err := json.Unmarshal([]byte(activityJSON), activity); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// JSON returns the JSON representation of the activity, as expected by DialogServiceConnector.SendActivityAsync.
+func (activity *Activity) JSON() (string, error) {
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// AddSuggestedAction adds an imBack action, which sends the given text back to the bot when selected.
+func (activity *Activity) AddSuggestedAction(title string, text string) {
+	if activity.SuggestedActions == nil {
+		activity.SuggestedActions = new(SuggestedActions)
+	}
+	activity.SuggestedActions.Actions = append(activity.SuggestedActions.Actions, CardAction{Type: "imBack", Title: title, Value: text})
+}
+
+// DecodeValue decodes the value of the activity, typically an event payload, into v.
+func (activity *Activity) DecodeValue(v interface{}) error {
+	if len(activity.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(activity.Value, v)
+}
+
+// ExpectsInput checks whether the bot expects the user to respond to the activity.
+func (activity *Activity) ExpectsInput() bool {
+	return activity.InputHint == ExpectingInput
+}
+
+// activityMembers are the JSON member names of the Activity fields.
+var activityMembers = func() map[string]bool {
+	members := make(map[string]bool)
+	t := reflect.TypeOf(Activity{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			members[name] = true
+		}
+	}
+	return members
+}()
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown members in Extra.
+func (activity *Activity) UnmarshalJSON(data []byte) error {
+	type plain Activity
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	decoded.Extra = nil
+	for name, value := range members {
+		if activityMembers[name] {
+			continue
+		}
+		if decoded.Extra == nil {
+			decoded.Extra = make(map[string]json.RawMessage)
+		}
+		decoded.Extra[name] = value
+	}
+	*activity = Activity(decoded)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, merging the members in Extra into the output.
+func (activity Activity) MarshalJSON() ([]byte, error) {
+	type plain Activity
+	data, err := json.Marshal(plain(activity))
+	if err != nil || len(activity.Extra) == 0 {
+		return data, err
+	}
+	var members map[string]json.RawMessage
+	if err = json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for name, value := range activity.Extra {
+		if _, ok := members[name]; !ok {
+			members[name] = value
+		}
+	}
+	return json.Marshal(members)
+}
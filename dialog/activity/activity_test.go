@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package activity
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseMessage(t *testing.T) {
+	activityJSON := `{"type":"message","id":"1","text":"Hi","speak":"<speak>Hi</speak>","inputHint":"expectingInput",
+		"from":{"id":"bot","role":"bot"},"conversation":{"id":"conv"},
+		"suggestedActions":{"actions":[{"type":"imBack","title":"Yes","value":"yes"}]},
+		"attachments":[{"contentType":"application/vnd.microsoft.card.hero","content":{"title":"Card"}}],
+		"entities":[{"type":"mention"}],"serviceUrl":"https://example.com"}`
+	parsed, err := Parse(activityJSON)
+	if err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	if parsed.Type != Message || parsed.Text != "Hi" || parsed.Speak != "<speak>Hi</speak>" || !parsed.ExpectsInput() {
+		t.Error("Unexpected activity: ", parsed)
+	}
+	if parsed.From == nil || parsed.From.Role != "bot" || parsed.Conversation == nil || parsed.Conversation.ID != "conv" {
+		t.Error("Unexpected accounts: ", parsed.From, parsed.Conversation)
+	}
+	if parsed.SuggestedActions == nil || len(parsed.SuggestedActions.Actions) != 1 || parsed.SuggestedActions.Actions[0].Title != "Yes" {
+		t.Error("Unexpected suggested actions: ", parsed.SuggestedActions)
+	}
+	if len(parsed.Attachments) != 1 || !strings.Contains(string(parsed.Attachments[0].Content), "Card") {
+		t.Error("Unexpected attachments: ", parsed.Attachments)
+	}
+	if len(parsed.Extra) != 2 || parsed.Extra["serviceUrl"] == nil {
+		t.Error("Unexpected extra members: ", parsed.Extra)
+	}
+
+	roundTrip, err := parsed.JSON()
+	if err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	var members map[string]interface{}
+	if err = json.Unmarshal([]byte(roundTrip), &members); err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	if members["serviceUrl"] != "https://example.com" || members["entities"] == nil || members["type"] != "message" {
+		t.Error("Unexpected serialized activity: ", roundTrip)
+	}
+
+	if _, err = Parse("{"); err == nil {
+		t.Error("Expected a parsing error")
+	}
+}
+
+func TestBuildActivities(t *testing.T) {
+	event, err := NewEvent("setLocale", map[string]string{"locale": "de-DE"})
+	if err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	eventJSON, err := event.JSON()
+	if err != nil || eventJSON != `{"type":"event","name":"setLocale","value":{"locale":"de-DE"}}` {
+		t.Error("Unexpected event: ", eventJSON, err)
+	}
+	var value map[string]string
+	if err = event.DecodeValue(&value); err != nil || value["locale"] != "de-DE" {
+		t.Error("Unexpected value: ", value, err)
+	}
+
+	message := NewMessage("What's the weather?")
+	message.InputHint = IgnoringInput
+	message.AddSuggestedAction("Today", "today")
+	messageJSON, _ := message.JSON()
+	if !strings.Contains(messageJSON, `"suggestedActions":{"actions":[{"type":"imBack","title":"Today","value":"today"}]}`) {
+		t.Error("Unexpected message: ", messageJSON)
+	}
+
+	if typing, _ := NewTyping().JSON(); typing != `{"type":"typing"}` {
+		t.Error("Unexpected typing activity: ", typing)
+	}
+	if end, _ := NewEndOfConversation("completedSuccessfully").JSON(); end != `{"type":"endOfConversation","code":"completedSuccessfully"}` {
+		t.Error("Unexpected endOfConversation activity: ", end)
+	}
+	if template, _ := (&Activity{Locale: "de-DE"}).JSON(); template != `{"locale":"de-DE"}` {
+		t.Error("Unexpected template activity: ", template)
+	}
+}
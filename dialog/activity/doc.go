@@ -0,0 +1,6 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package activity provides typed Bot Framework activities exchanged with a dialog backend through the
+// DialogServiceConnector.
+package activity
@@ -164,3 +164,32 @@ func dialogFireEventActivityReceived(handle C.SPXRECOHANDLE, eventHandle C.SPXEV
 	}
 	handler(*event)
 }
+
+var turnStatusReceivedCallbacks = make(map[C.SPXHANDLE]TurnStatusReceivedEventHandler)
+
+func registerTurnStatusReceivedCallback(handler TurnStatusReceivedEventHandler, handle C.SPXHANDLE) {
+	mu.Lock()
+	defer mu.Unlock()
+	turnStatusReceivedCallbacks[handle] = handler
+}
+
+func getTurnStatusReceivedCallback(handle C.SPXHANDLE) TurnStatusReceivedEventHandler {
+	mu.Lock()
+	defer mu.Unlock()
+	return turnStatusReceivedCallbacks[handle]
+}
+
+//export dialogFireEventTurnStatusReceived
+func dialogFireEventTurnStatusReceived(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHANDLE) {
+	handler := getTurnStatusReceivedCallback(handle)
+	event, err := NewTurnStatusReceivedEventArgsFromHandle(handle2uintptr(eventHandle))
+	if err != nil {
+		// The event handle is already released on failure.
+		return
+	}
+	if handler == nil {
+		event.Close()
+		return
+	}
+	handler(*event)
+}
@@ -49,4 +49,11 @@ package dialog
 //     dialogFireEventActivityReceived(handle, event);
 // }
 //
+// extern void dialogFireEventTurnStatusReceived(SPXRECOHANDLE handle, SPXEVENTHANDLE event);
+//
+// void cgo_dialog_turn_status_received(SPXRECOHANDLE handle, SPXEVENTHANDLE event, void* context)
+// {
+//     dialogFireEventTurnStatusReceived(handle, event);
+// }
+//
 import "C"
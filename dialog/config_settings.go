@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package dialog
+
+import (
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// NewBotFrameworkConfigFromSettings creates a bot framework service config from settings loaded by speech.LoadConfig,
+// using the key or the token, the region, the bot ID of the dialog settings, the language, the proxy and the
+// properties.
+func NewBotFrameworkConfigFromSettings(settings *speech.ConfigSettings) (*BotFrameworkConfig, error) {
+	if err := validateDialogSettings(settings); err != nil {
+		return nil, err
+	}
+	botID := ""
+	if settings.Dialog != nil {
+		botID = settings.Dialog.BotID
+	}
+	var config *BotFrameworkConfig
+	var err error
+	switch {
+	case settings.Key != "" && botID != "":
+		config, err = NewBotFrameworkConfigFromSubscriptionAndBotID(settings.Key, settings.Region, botID)
+	case settings.Key != "":
+		config, err = NewBotFrameworkConfigFromSubscription(settings.Key, settings.Region)
+	case botID != "":
+		config, err = NewBotFrameworkConfigFromAuthorizationTokenAndBotID(settings.Token, settings.Region, botID)
+	default:
+		config, err = NewBotFrameworkConfigFromAuthorizationToken(settings.Token, settings.Region)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = applyDialogSettings(config, settings); err != nil {
+		config.Close()
+		return nil, err
+	}
+	return config, nil
+}
+
+// NewCustomCommandsConfigFromSettings creates a Custom Commands config from settings loaded by speech.LoadConfig,
+// using the key or the token, the region, the application ID of the dialog settings, the language, the proxy and the
+// properties.
+func NewCustomCommandsConfigFromSettings(settings *speech.ConfigSettings) (*CustomCommandsConfig, error) {
+	if err := validateDialogSettings(settings); err != nil {
+		return nil, err
+	}
+	if settings.Dialog == nil || settings.Dialog.ApplicationID == "" {
+		return nil, &speech.OptionError{Option: "Dialog", Message: "an application ID is required for Custom Commands"}
+	}
+	var config *CustomCommandsConfig
+	var err error
+	if settings.Key != "" {
+		config, err = NewCustomCommandsConfigFromSubscription(settings.Dialog.ApplicationID, settings.Key, settings.Region)
+	} else {
+		config, err = NewCustomCommandsConfigFromAuthorizationToken(settings.Dialog.ApplicationID, settings.Token, settings.Region)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = applyDialogSettings(config, settings); err != nil {
+		config.Close()
+		return nil, err
+	}
+	return config, nil
+}
+
+func validateDialogSettings(settings *speech.ConfigSettings) error {
+	if settings.Region == "" || settings.Endpoint != "" || settings.Host != "" {
+		return &speech.OptionError{Option: "Auth", Message: "dialog configs require a Region, without an Endpoint or a Host"}
+	}
+	if settings.Key == "" && settings.Token == "" {
+		return &speech.OptionError{Option: "Auth", Message: "a Key or a Token is required with a Region"}
+	}
+	return nil
+}
+
+func applyDialogSettings(config DialogServiceConfig, settings *speech.ConfigSettings) error {
+	if settings.Language != "" {
+		if err := config.SetLanguage(settings.Language); err != nil {
+			return err
+		}
+	}
+	if proxy := settings.Proxy; proxy != nil {
+		var err error
+		if proxy.Username != "" || proxy.Password != "" {
+			err = config.SetProxyWithUsernameAndPassword(proxy.Host, proxy.Port, proxy.Username, proxy.Password)
+		} else {
+			err = config.SetProxy(proxy.Host, proxy.Port)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return settings.Properties.ApplyTo(config)
+}
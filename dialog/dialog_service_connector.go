@@ -8,6 +8,7 @@ import (
 
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/cancellation"
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
 )
 
@@ -23,10 +24,12 @@ import (
 // void cgo_dialog_recognizing(SPXRECOHANDLE handle, SPXEVENTHANDLE event, void* context);
 // void cgo_dialog_canceled(SPXRECOHANDLE handle, SPXEVENTHANDLE event, void* context);
 // void cgo_dialog_activity_received(SPXRECOHANDLE handle, SPXEVENTHANDLE event, void* context);
+// void cgo_dialog_turn_status_received(SPXRECOHANDLE handle, SPXEVENTHANDLE event, void* context);
 import "C"
 
 // DialogServiceConnector connects to a speech enabled dialog backend.
 type DialogServiceConnector struct {
+	common.RecognitionProperties
 	Properties *common.PropertyCollection
 	handle     C.SPXHANDLE
 }
@@ -40,6 +43,7 @@ func newDialogServiceConnectorFromHandle(handle C.SPXHANDLE) (*DialogServiceConn
 	connector := new(DialogServiceConnector)
 	connector.handle = handle
 	connector.Properties = common.NewPropertyCollectionFromHandle(handle2uintptr(propBagHandle))
+	connector.RecognitionProperties = common.NewRecognitionPropertiesFromCollection(connector.Properties)
 	return connector, nil
 }
 
@@ -125,6 +129,7 @@ func (connector DialogServiceConnector) SendActivityAsync(message string) chan S
 }
 
 // ListenOnceAsync starts a listening session that will terminate after the first utterance.
+// When the result is canceled, the outcome holds both the result and a *common.CancellationError.
 func (connector DialogServiceConnector) ListenOnceAsync() <-chan speech.SpeechRecognitionOutcome {
 	outcome := make(chan speech.SpeechRecognitionOutcome)
 	go func() {
@@ -134,6 +139,11 @@ func (connector DialogServiceConnector) ListenOnceAsync() <-chan speech.SpeechRe
 			outcome <- speech.SpeechRecognitionOutcome{Result: nil, OperationOutcome: common.OperationOutcome{common.NewCarbonError(ret)}}
 		} else {
 			result, err := speech.NewSpeechRecognitionResultFromHandle(handle2uintptr(handle))
+			if err == nil && result.Reason == common.Canceled {
+				if details, detailsErr := speech.NewCancellationDetailsFromSpeechRecognitionResult(result); detailsErr == nil {
+					err = cancellation.Error(details.Err(), connector.Properties)
+				}
+			}
 			outcome <- speech.SpeechRecognitionOutcome{Result: result, OperationOutcome: common.OperationOutcome{err}}
 		}
 	}()
@@ -169,6 +179,21 @@ func (connector DialogServiceConnector) StopKeywordRecognitionAsync() chan error
 	return outcome
 }
 
+// StopListeningAsync stops the current listening session, if any. A pending ListenOnceAsync completes with the
+// audio recognized so far.
+func (connector DialogServiceConnector) StopListeningAsync() chan error {
+	outcome := make(chan error)
+	go func() {
+		ret := uintptr(C.dialog_service_connector_stop_listening(connector.handle))
+		if ret != C.SPX_NOERROR {
+			outcome <- common.NewCarbonError(ret)
+		} else {
+			outcome <- nil
+		}
+	}()
+	return outcome
+}
+
 // SetAuthorizationToken sets the authorization token that will be used for connecting to the service.
 // Note: The caller needs to ensure that the authorization token is valid. Before the authorization token
 // expires, the caller needs to refresh it by calling this setter with a new valid token.
@@ -270,3 +295,17 @@ func (connector DialogServiceConnector) ActivityReceived(handler ActivityReceive
 		C.dialog_service_connector_activity_received_set_callback(connector.handle, nil, nil)
 	}
 }
+
+// TurnStatusReceived signals that the backing dialog completed a turn. Turn status messages are only sent when
+// common.ConversationRequestBotStatusMessages is enabled, which is the default.
+func (connector DialogServiceConnector) TurnStatusReceived(handler TurnStatusReceivedEventHandler) {
+	registerTurnStatusReceivedCallback(handler, connector.handle)
+	if handler != nil {
+		C.dialog_service_connector_turn_status_received_set_callback(
+			connector.handle,
+			(C.PRECOGNITION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_dialog_turn_status_received)),
+			nil)
+	} else {
+		C.dialog_service_connector_turn_status_received_set_callback(connector.handle, nil, nil)
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/dialog/activity"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// DefaultTurnTimeout is how long a MultiTurnListener waits for the bot to complete a turn.
+const DefaultTurnTimeout = 30 * time.Second
+
+// Turn is one turn of a multi-turn interaction: the utterance of the user and the activities the bot sent in reply.
+type Turn struct {
+	// InteractionID is the identifier of the interaction, as reported by the turn status of the bot.
+	// It is empty if the bot did not report a turn status. The native SDK reports interaction IDs only with the turn
+	// status, so the listener sets it on the Result and the Activities of the turn once the status is received.
+	InteractionID string
+
+	// ConversationID is the identifier of the conversation, as reported by the turn status of the bot.
+	ConversationID string
+
+	// Result is the recognized utterance, or nil if listening failed.
+	Result *TurnResult
+
+	// Activities are the activities the bot sent during the turn, in the order received.
+	Activities []*TurnActivity
+
+	// StatusCode is the status of the turn reported by the bot, or 0 if none was reported.
+	StatusCode int
+
+	// Err is set when listening failed, e.g. with a *common.CancellationError, or the bot reported a failed turn,
+	// with a *TurnError.
+	Err error
+}
+
+// TurnResult is the recognized utterance of a turn.
+type TurnResult struct {
+	*speech.SpeechRecognitionResult
+
+	// InteractionID is the identifier of the interaction started by the utterance, or empty if the bot did not report
+	// a turn status.
+	InteractionID string
+}
+
+// TurnActivity is an activity the bot sent during a turn.
+type TurnActivity struct {
+	*activity.Activity
+
+	// InteractionID is the identifier of the interaction the activity replies to, or empty if the bot did not report
+	// a turn status.
+	InteractionID string
+}
+
+// TurnError is the error of a turn that the bot reported as failed.
+type TurnError struct {
+	InteractionID string
+	StatusCode    int
+}
+
+func (e *TurnError) Error() string {
+	return fmt.Sprintf("dialog turn %s failed with status %d", e.InteractionID, e.StatusCode)
+}
+
+// Retryable checks whether the status denotes a transient failure, i.e. throttling (429) or a server error (5xx).
+func (e *TurnError) Retryable() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
+
+// InputHint returns the last input hint sent by the bot during the turn, or an empty hint if there was none.
+func (turn Turn) InputHint() activity.InputHint {
+	for i := len(turn.Activities) - 1; i >= 0; i-- {
+		if turn.Activities[i].InputHint != "" {
+			return turn.Activities[i].InputHint
+		}
+	}
+	return ""
+}
+
+// endsInteraction checks whether the bot asked to stop listening after this turn.
+func (turn Turn) endsInteraction() bool {
+	if turn.Err != nil {
+		return true
+	}
+	for _, received := range turn.Activities {
+		if received.Type == activity.EndOfConversation {
+			return true
+		}
+	}
+	return turn.InputHint() == activity.IgnoringInput
+}
+
+type turnStatus struct {
+	interactionID  string
+	conversationID string
+	statusCode     int
+}
+
+// MultiTurnListener listens to the user over multiple turns of a DialogServiceConnector. After each utterance it
+// waits for the bot to complete its reply, then listens again, until the bot replies with the ignoringInput input
+// hint or ends the conversation, the user says nothing, or Stop is called.
+// The end of a turn is signaled by the turn status of the bot. When common.ConversationRequestBotStatusMessages is
+// disabled, a turn ends with the first expectingInput or ignoringInput input hint, or after TurnTimeout.
+// Creating a listener replaces the ActivityReceived and TurnStatusReceived handlers of the connector.
+type MultiTurnListener struct {
+	// TurnTimeout is how long to wait for the bot to complete a turn. It must be set before calling Start.
+	TurnTimeout time.Duration
+
+	listen         func() speech.SpeechRecognitionOutcome
+	stopListening  func()
+	statusMessages bool
+
+	mu              sync.Mutex
+	activityHandler TypedActivityReceivedEventHandler
+	pending         []interface{}
+	notify          chan struct{}
+	started         bool
+	stopped         bool
+	stop            chan struct{}
+	turns           chan Turn
+	done            chan struct{}
+}
+
+// NewMultiTurnListener creates a multi-turn listener on the connector.
+func NewMultiTurnListener(connector *DialogServiceConnector) *MultiTurnListener {
+	statusMessages := !strings.EqualFold(connector.Properties.GetProperty(common.ConversationRequestBotStatusMessages, "true"), "false")
+	listener := newMultiTurnListener(
+		func() speech.SpeechRecognitionOutcome { return <-connector.ListenOnceAsync() },
+		func() { <-connector.StopListeningAsync() },
+		statusMessages)
+	connector.TypedActivityReceived(listener.onActivity)
+	connector.TurnStatusReceived(func(event TurnStatusReceivedEventArgs) {
+		defer event.Close()
+		listener.onTurnStatus(turnStatus{event.InteractionID, event.ConversationID, event.StatusCode})
+	})
+	return listener
+}
+
+func newMultiTurnListener(listen func() speech.SpeechRecognitionOutcome, stopListening func(), statusMessages bool) *MultiTurnListener {
+	listener := new(MultiTurnListener)
+	listener.TurnTimeout = DefaultTurnTimeout
+	listener.listen = listen
+	listener.stopListening = stopListening
+	listener.statusMessages = statusMessages
+	listener.notify = make(chan struct{}, 1)
+	listener.stop = make(chan struct{})
+	listener.turns = make(chan Turn)
+	listener.done = make(chan struct{})
+	return listener
+}
+
+// ActivityReceived sets a handler that receives every activity, with its audio, as soon as it arrives.
+// The handler must close the event. Without a handler, the listener closes the events itself.
+func (listener *MultiTurnListener) ActivityReceived(handler TypedActivityReceivedEventHandler) {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	listener.activityHandler = handler
+}
+
+// Turns returns the channel on which completed turns are delivered. It is closed when the listener finishes.
+func (listener *MultiTurnListener) Turns() <-chan Turn {
+	return listener.turns
+}
+
+// Done returns a channel that is closed when the listener finishes.
+func (listener *MultiTurnListener) Done() <-chan struct{} {
+	return listener.done
+}
+
+// Start starts listening. Completed turns must be received from Turns.
+func (listener *MultiTurnListener) Start() {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	if listener.started || listener.stopped {
+		return
+	}
+	listener.started = true
+	go listener.run()
+}
+
+// Stop stops listening and finishes the listener. A turn in progress is discarded unless it is already being received.
+func (listener *MultiTurnListener) Stop() {
+	listener.mu.Lock()
+	if listener.stopped {
+		listener.mu.Unlock()
+		return
+	}
+	listener.stopped = true
+	started := listener.started
+	close(listener.stop)
+	listener.mu.Unlock()
+	if started {
+		listener.stopListening()
+	} else {
+		close(listener.turns)
+		close(listener.done)
+	}
+}
+
+func (listener *MultiTurnListener) isStopped() bool {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	return listener.stopped
+}
+
+func (listener *MultiTurnListener) onActivity(event TypedActivityReceivedEventArgs) {
+	listener.mu.Lock()
+	handler := listener.activityHandler
+	if event.Activity != nil {
+		listener.pending = append(listener.pending, event.Activity)
+	}
+	listener.mu.Unlock()
+	listener.signal()
+	if handler != nil {
+		handler(event)
+	} else {
+		event.Close()
+	}
+}
+
+func (listener *MultiTurnListener) onTurnStatus(status turnStatus) {
+	listener.mu.Lock()
+	listener.pending = append(listener.pending, status)
+	listener.mu.Unlock()
+	listener.signal()
+}
+
+func (listener *MultiTurnListener) signal() {
+	select {
+	case listener.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (listener *MultiTurnListener) takePending() []interface{} {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	pending := listener.pending
+	listener.pending = nil
+	return pending
+}
+
+func (listener *MultiTurnListener) run() {
+	defer close(listener.done)
+	defer close(listener.turns)
+	for !listener.isStopped() {
+		outcome := listener.listen()
+		turn := Turn{Err: outcome.Error}
+		if outcome.Result != nil {
+			turn.Result = &TurnResult{SpeechRecognitionResult: outcome.Result}
+		}
+		if turn.Err == nil && turn.Result != nil && turn.Result.Reason == common.RecognizedSpeech {
+			listener.awaitReply(&turn)
+		} else if turn.Err == nil {
+			listener.collect(&turn)
+			listener.deliver(turn)
+			return
+		}
+		if !listener.deliver(turn) || turn.endsInteraction() {
+			return
+		}
+	}
+}
+
+// collect drains the received activities and turn status into the turn. The interaction ID of the turn status is set
+// on the result and the activities of the turn.
+func (listener *MultiTurnListener) collect(turn *Turn) {
+	for _, item := range listener.takePending() {
+		switch received := item.(type) {
+		case *activity.Activity:
+			turn.Activities = append(turn.Activities, &TurnActivity{Activity: received, InteractionID: turn.InteractionID})
+		case turnStatus:
+			turn.InteractionID = received.interactionID
+			turn.ConversationID = received.conversationID
+			turn.StatusCode = received.statusCode
+			if turn.Result != nil {
+				turn.Result.InteractionID = received.interactionID
+			}
+			for _, replied := range turn.Activities {
+				replied.InteractionID = received.interactionID
+			}
+		}
+	}
+}
+
+// awaitReply collects the reply of the bot until it completes the turn.
+func (listener *MultiTurnListener) awaitReply(turn *Turn) {
+	timer := time.NewTimer(listener.TurnTimeout)
+	defer timer.Stop()
+	for {
+		listener.collect(turn)
+		if turn.StatusCode != 0 {
+			if turn.StatusCode < 200 || turn.StatusCode >= 300 {
+				turn.Err = &TurnError{InteractionID: turn.InteractionID, StatusCode: turn.StatusCode}
+			}
+			return
+		}
+		if !listener.statusMessages {
+			if hint := turn.InputHint(); hint == activity.ExpectingInput || hint == activity.IgnoringInput {
+				return
+			}
+		}
+		select {
+		case <-listener.notify:
+		case <-timer.C:
+			return
+		case <-listener.stop:
+			listener.collect(turn)
+			return
+		}
+	}
+}
+
+func (listener *MultiTurnListener) deliver(turn Turn) bool {
+	select {
+	case listener.turns <- turn:
+		return true
+	case <-listener.stop:
+		return false
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package dialog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/dialog/activity"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+func receiveTurn(t *testing.T, listener *MultiTurnListener) Turn {
+	select {
+	case turn := <-listener.Turns():
+		return turn
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout waiting for turn.")
+	}
+	return Turn{}
+}
+
+func TestMultiTurnListenerFollowsInputHints(t *testing.T) {
+	var listener *MultiTurnListener
+	replies := []activity.InputHint{activity.ExpectingInput, activity.IgnoringInput}
+	listens := 0
+	listener = newMultiTurnListener(func() speech.SpeechRecognitionOutcome {
+		reply := activity.NewMessage("reply")
+		reply.InputHint = replies[listens]
+		listens++
+		go func() {
+			listener.onActivity(TypedActivityReceivedEventArgs{Activity: reply})
+			listener.onTurnStatus(turnStatus{"interaction", "conversation", 200})
+		}()
+		return speech.SpeechRecognitionOutcome{Result: &speech.SpeechRecognitionResult{Text: "hello", Reason: common.RecognizedSpeech}}
+	}, func() {}, true)
+	listener.Start()
+
+	turn := receiveTurn(t, listener)
+	if turn.Err != nil || turn.Result.Text != "hello" || len(turn.Activities) != 1 || turn.InputHint() != activity.ExpectingInput {
+		t.Error("Unexpected first turn: ", turn)
+	}
+	if turn.StatusCode != 200 || turn.ConversationID != "conversation" {
+		t.Error("Unexpected turn status: ", turn)
+	}
+	if turn.Result.InteractionID != "interaction" || turn.Activities[0].InteractionID != "interaction" {
+		t.Error("Unexpected interaction IDs: ", turn.Result.InteractionID, turn.Activities[0].InteractionID)
+	}
+	turn = receiveTurn(t, listener)
+	if turn.InputHint() != activity.IgnoringInput {
+		t.Error("Unexpected second turn: ", turn)
+	}
+	select {
+	case <-listener.Done():
+	case <-time.After(10 * time.Second):
+		t.Error("Listener did not stop after ignoringInput")
+	}
+	if listens != 2 {
+		t.Error("Unexpected number of listens: ", listens)
+	}
+}
+
+func TestMultiTurnListenerStopsOnNoMatch(t *testing.T) {
+	listener := newMultiTurnListener(func() speech.SpeechRecognitionOutcome {
+		return speech.SpeechRecognitionOutcome{Result: &speech.SpeechRecognitionResult{Reason: common.NoMatch}}
+	}, func() {}, false)
+	listener.TurnTimeout = time.Millisecond
+	listener.Start()
+	if turn := receiveTurn(t, listener); turn.Result.Reason != common.NoMatch {
+		t.Error("Unexpected turn: ", turn)
+	}
+	if _, ok := <-listener.Turns(); ok {
+		t.Error("Expected the listener to finish")
+	}
+}
+
+func TestMultiTurnListenerReportsFailedTurn(t *testing.T) {
+	var listener *MultiTurnListener
+	listener = newMultiTurnListener(func() speech.SpeechRecognitionOutcome {
+		go listener.onTurnStatus(turnStatus{"interaction", "conversation", 503})
+		return speech.SpeechRecognitionOutcome{Result: &speech.SpeechRecognitionResult{Text: "hello", Reason: common.RecognizedSpeech}}
+	}, func() {}, true)
+	listener.Start()
+
+	turn := receiveTurn(t, listener)
+	var turnErr *TurnError
+	if !errors.As(turn.Err, &turnErr) || turnErr.StatusCode != 503 || !common.IsRetryable(turn.Err) {
+		t.Error("Unexpected turn error: ", turn.Err)
+	}
+	if _, ok := <-listener.Turns(); ok {
+		t.Error("Expected the listener to finish")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package dialog
+
+import (
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// #include <stdlib.h>
+// #include <stdint.h>
+// #include <speechapi_c_dialog_service_connector.h>
+import "C"
+import "unsafe"
+
+// TurnStatusReceivedEventArgs contains the status reported by the backing dialog when it completes a turn.
+type TurnStatusReceivedEventArgs struct {
+	handle C.SPXHANDLE
+
+	// InteractionID is the identifier of the interaction the turn belongs to.
+	InteractionID string
+
+	// ConversationID is the identifier of the conversation the turn belongs to.
+	ConversationID string
+
+	// StatusCode is the status reported by the bot, using HTTP status code semantics.
+	StatusCode int
+}
+
+// Close releases the underlying resources
+func (event TurnStatusReceivedEventArgs) Close() {
+	C.dialog_service_connector_turn_status_received_release(event.handle)
+}
+
+// Succeeded checks if the bot completed the turn successfully.
+func (event TurnStatusReceivedEventArgs) Succeeded() bool {
+	return event.StatusCode >= 200 && event.StatusCode < 300
+}
+
+// NewTurnStatusReceivedEventArgsFromHandle creates the object from the handle (for internal use)
+func NewTurnStatusReceivedEventArgsFromHandle(handle common.SPXHandle) (*TurnStatusReceivedEventArgs, error) {
+	event := new(TurnStatusReceivedEventArgs)
+	event.handle = uintptr2handle(handle)
+	var size C.size_t
+	ret := uintptr(C.turn_status_received_get_interaction_id_size(event.handle, &size))
+	if ret != C.SPX_NOERROR {
+		event.Close()
+		return nil, common.NewCarbonError(ret)
+	}
+	interactionBuffer := C.malloc(C.sizeof_char * (size + 1))
+	defer C.free(unsafe.Pointer(interactionBuffer))
+	ret = uintptr(C.turn_status_received_get_interaction_id(event.handle, (*C.char)(interactionBuffer), size+1))
+	if ret != C.SPX_NOERROR {
+		event.Close()
+		return nil, common.NewCarbonError(ret)
+	}
+	event.InteractionID = C.GoString((*C.char)(interactionBuffer))
+	ret = uintptr(C.turn_status_received_get_conversation_id_size(event.handle, &size))
+	if ret != C.SPX_NOERROR {
+		event.Close()
+		return nil, common.NewCarbonError(ret)
+	}
+	conversationBuffer := C.malloc(C.sizeof_char * (size + 1))
+	defer C.free(unsafe.Pointer(conversationBuffer))
+	ret = uintptr(C.turn_status_received_get_conversation_id(event.handle, (*C.char)(conversationBuffer), size+1))
+	if ret != C.SPX_NOERROR {
+		event.Close()
+		return nil, common.NewCarbonError(ret)
+	}
+	event.ConversationID = C.GoString((*C.char)(conversationBuffer))
+	var status C.int
+	ret = uintptr(C.turn_status_received_get_status(event.handle, &status))
+	if ret != C.SPX_NOERROR {
+		event.Close()
+		return nil, common.NewCarbonError(ret)
+	}
+	event.StatusCode = int(status)
+	return event, nil
+}
+
+// TurnStatusReceivedEventHandler is the type of the event handler that receives turn status events.
+type TurnStatusReceivedEventHandler func(event TurnStatusReceivedEventArgs)
@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package dialog
+
+import (
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/dialog/activity"
+)
+
+// TypedActivityReceivedEventArgs contains the parsed activity received from the backing dialog and its audio, if any.
+type TypedActivityReceivedEventArgs struct {
+	event ActivityReceivedEventArgs
+
+	// Activity is the parsed activity, or nil if it could not be parsed (see Err).
+	Activity *activity.Activity
+
+	// RawActivity is the activity JSON as received from the service.
+	RawActivity string
+
+	// Audio is the audio stream associated with the activity, or nil if it has none.
+	Audio *audio.PullAudioOutputStream
+
+	// Err is set when the activity could not be parsed or its audio could not be retrieved.
+	Err error
+}
+
+// Close releases the underlying resources, including the audio stream.
+func (event TypedActivityReceivedEventArgs) Close() {
+	if event.Audio != nil {
+		event.Audio.Close()
+	}
+	event.event.Close()
+}
+
+// TypedActivityReceivedEventHandler is the type of the event handler that receives typed activities.
+type TypedActivityReceivedEventHandler func(event TypedActivityReceivedEventArgs)
+
+// ParseActivity parses the activity carried by the event.
+func (event ActivityReceivedEventArgs) ParseActivity() (*activity.Activity, error) {
+	return activity.Parse(event.Activity)
+}
+
+func newTypedActivityReceivedEventArgs(event ActivityReceivedEventArgs) TypedActivityReceivedEventArgs {
+	typed := TypedActivityReceivedEventArgs{event: event, RawActivity: event.Activity}
+	typed.Activity, typed.Err = event.ParseActivity()
+	if event.HasAudio() {
+		stream, err := event.GetAudio()
+		if err != nil && typed.Err == nil {
+			typed.Err = err
+		}
+		typed.Audio = stream
+	}
+	return typed
+}
+
+// TypedActivityReceived signals that an activity was received from the backing dialog, delivering the parsed activity
+// and its audio stream. It replaces any handler registered with ActivityReceived.
+func (connector DialogServiceConnector) TypedActivityReceived(handler TypedActivityReceivedEventHandler) {
+	if handler == nil {
+		connector.ActivityReceived(nil)
+		return
+	}
+	connector.ActivityReceived(func(event ActivityReceivedEventArgs) {
+		handler(newTypedActivityReceivedEventArgs(event))
+	})
+}
+
+// SendTypedActivityAsync serializes the activity and sends it to the backing dialog.
+func (connector DialogServiceConnector) SendTypedActivityAsync(message *activity.Activity) chan SendActivityOutcome {
+	if message == nil {
+		outcome := make(chan SendActivityOutcome, 1)
+		outcome <- SendActivityOutcome{OperationOutcome: common.OperationOutcome{Error: common.ErrInvalidArg}}
+		return outcome
+	}
+	activityJSON, err := message.JSON()
+	if err != nil {
+		outcome := make(chan SendActivityOutcome, 1)
+		outcome <- SendActivityOutcome{OperationOutcome: common.OperationOutcome{Error: err}}
+		return outcome
+	}
+	return connector.SendActivityAsync(activityJSON)
+}
+
+// SetTypedSpeechActivityTemplate sets the speech activity template from an activity. Properties of the template are
+// stamped on the activities the service generates for speech.
+func (connector DialogServiceConnector) SetTypedSpeechActivityTemplate(template *activity.Activity) error {
+	if template == nil {
+		return connector.SetSpeechActivityTemplate("")
+	}
+	templateJSON, err := template.JSON()
+	if err != nil {
+		return err
+	}
+	return connector.SetSpeechActivityTemplate(templateJSON)
+}
+
+// TypedSpeechActivityTemplate returns the parsed speech activity template, or nil if none is set.
+func (connector DialogServiceConnector) TypedSpeechActivityTemplate() (*activity.Activity, error) {
+	template := connector.SpeechActivityTemplate()
+	if template == "" {
+		return nil, nil
+	}
+	return activity.Parse(template)
+}
@@ -0,0 +1,238 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package eval
+
+import (
+	"strings"
+)
+
+// Op is the kind of an alignment step.
+type Op int
+
+const (
+	// Match is a hypothesis token equal to the reference token.
+	Match Op = iota
+
+	// Substitution is a hypothesis token replacing a reference token.
+	Substitution
+
+	// Deletion is a reference token missing from the hypothesis.
+	Deletion
+
+	// Insertion is a hypothesis token not in the reference.
+	Insertion
+)
+
+func (op Op) String() string {
+	switch op {
+	case Match:
+		return "Match"
+	case Substitution:
+		return "Substitution"
+	case Deletion:
+		return "Deletion"
+	case Insertion:
+		return "Insertion"
+	}
+	return "Unknown"
+}
+
+// Edit is a step of the alignment of a hypothesis to a reference. Reference is empty for insertions and Hypothesis
+// for deletions.
+type Edit struct {
+	Op         Op
+	Reference  string
+	Hypothesis string
+}
+
+// ErrorCounts counts the steps of an alignment.
+type ErrorCounts struct {
+	// Reference is the number of reference tokens, the denominator of the error rate.
+	Reference int `json:"reference"`
+
+	Matches       int `json:"matches"`
+	Substitutions int `json:"substitutions"`
+	Deletions     int `json:"deletions"`
+	Insertions    int `json:"insertions"`
+}
+
+// Errors returns the number of substitutions, deletions and insertions.
+func (counts ErrorCounts) Errors() int {
+	return counts.Substitutions + counts.Deletions + counts.Insertions
+}
+
+// Rate returns the number of errors divided by the number of reference tokens. It exceeds 1 when the hypothesis has
+// more errors than the reference has tokens. With an empty reference, it is 0 for an empty hypothesis and 1 otherwise.
+func (counts ErrorCounts) Rate() float64 {
+	if counts.Reference == 0 {
+		if counts.Insertions == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(counts.Errors()) / float64(counts.Reference)
+}
+
+// Add returns the sum of the counts.
+func (counts ErrorCounts) Add(other ErrorCounts) ErrorCounts {
+	return ErrorCounts{
+		Reference:     counts.Reference + other.Reference,
+		Matches:       counts.Matches + other.Matches,
+		Substitutions: counts.Substitutions + other.Substitutions,
+		Deletions:     counts.Deletions + other.Deletions,
+		Insertions:    counts.Insertions + other.Insertions,
+	}
+}
+
+func (counts *ErrorCounts) count(op Op) {
+	switch op {
+	case Match:
+		counts.Matches++
+	case Substitution:
+		counts.Substitutions++
+	case Deletion:
+		counts.Deletions++
+	case Insertion:
+		counts.Insertions++
+	}
+	if op != Insertion {
+		counts.Reference++
+	}
+}
+
+// step chooses the cheapest step to cell (i, j) of the Levenshtein matrix, given the costs of the cells above-left
+// (diagonal), above (up) and left. Ties between errors are broken in favor of deletions, then insertions, which keeps
+// substitutions next to each other, e.g. "a [-b-]{+c+} [-d-]" rather than "a [-b-] [-d-]{+c+}". Align and Count use the
+// same order, so that they agree.
+func step(diagonal int, up int, left int, equal bool) (Op, int) {
+	if equal {
+		return Match, diagonal
+	}
+	op, cost := Deletion, up+1
+	if left+1 < cost {
+		op, cost = Insertion, left+1
+	}
+	if diagonal+1 < cost {
+		op, cost = Substitution, diagonal+1
+	}
+	return op, cost
+}
+
+// Align aligns the hypothesis tokens to the reference tokens with the minimum number of substitutions, deletions
+// and insertions, and returns the steps in order. It uses memory proportional to the product of the lengths, see Count
+// for long sequences.
+func Align(reference []string, hypothesis []string) []Edit {
+	n, m := len(reference), len(hypothesis)
+	ops := make([]Op, (n+1)*(m+1))
+	previous, current := make([]int, m+1), make([]int, m+1)
+	for j := 1; j <= m; j++ {
+		previous[j] = j
+		ops[j] = Insertion
+	}
+	for i := 1; i <= n; i++ {
+		current[0] = i
+		ops[i*(m+1)] = Deletion
+		for j := 1; j <= m; j++ {
+			ops[i*(m+1)+j], current[j] = step(previous[j-1], previous[j], current[j-1], reference[i-1] == hypothesis[j-1])
+		}
+		previous, current = current, previous
+	}
+
+	edits := make([]Edit, 0, n+m)
+	for i, j := n, m; i > 0 || j > 0; {
+		switch op := ops[i*(m+1)+j]; op {
+		case Match, Substitution:
+			edits = append(edits, Edit{Op: op, Reference: reference[i-1], Hypothesis: hypothesis[j-1]})
+			i--
+			j--
+		case Deletion:
+			edits = append(edits, Edit{Op: op, Reference: reference[i-1]})
+			i--
+		case Insertion:
+			edits = append(edits, Edit{Op: op, Hypothesis: hypothesis[j-1]})
+			j--
+		}
+	}
+	for left, right := 0, len(edits)-1; left < right; left, right = left+1, right-1 {
+		edits[left], edits[right] = edits[right], edits[left]
+	}
+	return edits
+}
+
+// CountEdits counts the steps of an alignment.
+func CountEdits(edits []Edit) ErrorCounts {
+	var counts ErrorCounts
+	for _, edit := range edits {
+		counts.count(edit.Op)
+	}
+	return counts
+}
+
+// Count counts the steps of the alignment of the hypothesis tokens to the reference tokens, like
+// CountEdits(Align(reference, hypothesis)), with memory proportional to the length of the hypothesis only.
+func Count(reference []string, hypothesis []string) ErrorCounts {
+	m := len(hypothesis)
+	previous, current := make([]ErrorCounts, m+1), make([]ErrorCounts, m+1)
+	costs, currentCosts := make([]int, m+1), make([]int, m+1)
+	for j := 1; j <= m; j++ {
+		previous[j] = previous[j-1]
+		previous[j].count(Insertion)
+		costs[j] = j
+	}
+	for i := 1; i <= len(reference); i++ {
+		current[0] = previous[0]
+		current[0].count(Deletion)
+		currentCosts[0] = i
+		for j := 1; j <= m; j++ {
+			op, cost := step(costs[j-1], costs[j], currentCosts[j-1], reference[i-1] == hypothesis[j-1])
+			switch op {
+			case Match, Substitution:
+				current[j] = previous[j-1]
+			case Deletion:
+				current[j] = previous[j]
+			case Insertion:
+				current[j] = current[j-1]
+			}
+			current[j].count(op)
+			currentCosts[j] = cost
+		}
+		previous, current = current, previous
+		costs, currentCosts = currentCosts, costs
+	}
+	return previous[m]
+}
+
+// Characters splits words into characters for the character error rate. Spaces are left out, so that differences of
+// word segmentation only count once.
+func Characters(words []string) []string {
+	var characters []string
+	for _, word := range words {
+		for _, r := range word {
+			characters = append(characters, string(r))
+		}
+	}
+	return characters
+}
+
+// Diff renders an alignment as a word diff: deleted reference tokens are written as [-token-], inserted hypothesis
+// tokens as {+token+}, and substitutions as both.
+func Diff(edits []Edit) string {
+	var diff strings.Builder
+	for i, edit := range edits {
+		if i > 0 {
+			diff.WriteByte(' ')
+		}
+		switch edit.Op {
+		case Match:
+			diff.WriteString(edit.Reference)
+		case Substitution:
+			diff.WriteString("[-" + edit.Reference + "-]{+" + edit.Hypothesis + "+}")
+		case Deletion:
+			diff.WriteString("[-" + edit.Reference + "-]")
+		case Insertion:
+			diff.WriteString("{+" + edit.Hypothesis + "+}")
+		}
+	}
+	return diff.String()
+}
@@ -0,0 +1,15 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package eval scores recognition results against reference transcripts, to catch regressions when changing models,
+// endpoints or configurations.
+//
+// Texts are normalized first (see Normalizer), then the hypothesis is aligned to the reference with the Levenshtein
+// distance over words and over characters, giving the word error rate (WER) and the character error rate (CER)
+// with their substitutions, deletions and insertions. Run recognizes a corpus of WAV files with reference .txt files
+// next to them (see LoadSamples) and gathers the scores in a Report, which can be written as text with per-file
+// diffs, or as JSON and checked against Thresholds in continuous integration.
+//
+// The speech-eval command in cmd/speech-eval runs an evaluation from the command line, with the Speech Service or
+// embedded models.
+package eval
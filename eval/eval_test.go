@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package eval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizer(t *testing.T) {
+	tests := []struct {
+		normalizer Normalizer
+		text       string
+		want       string
+	}{
+		{Normalizer{}, "Hello, World! Don't   stop.", "hello world don't stop"},
+		{Normalizer{}, "It costs 1,250.5 dollars, 50% off, on the 22nd.", "it costs one thousand two hundred fifty point five dollars fifty percent off on the twenty second"},
+		{Normalizer{}, "Twenty-five and/or 25; room 007, 1st U.S. ‘trial’", "twenty five and or twenty five room zero zero seven first us trial"},
+		{Normalizer{}, "100 2000000 3rd 11th 40th", "one hundred two million third eleventh fortieth"},
+		{Normalizer{KeepCase: true, KeepNumbers: true}, "Call 911, Bob.", "Call 911 Bob"},
+		{Normalizer{KeepPunctuation: true}, "Hi, 5 people.", "hi, five people."},
+	}
+	for _, test := range tests {
+		if got := test.normalizer.Normalize(test.text); got != test.want {
+			t.Errorf("Normalize(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestAlign(t *testing.T) {
+	reference := strings.Fields("the cat sat on the mat")
+	hypothesis := strings.Fields("the cat sit on mat today")
+	edits := Align(reference, hypothesis)
+	want := []Edit{
+		{Match, "the", "the"},
+		{Match, "cat", "cat"},
+		{Substitution, "sat", "sit"},
+		{Match, "on", "on"},
+		{Deletion, "the", ""},
+		{Match, "mat", "mat"},
+		{Insertion, "", "today"},
+	}
+	if !reflect.DeepEqual(edits, want) {
+		t.Errorf("Align() = %v, want %v", edits, want)
+	}
+	counts := CountEdits(edits)
+	if counts != (ErrorCounts{Reference: 6, Matches: 4, Substitutions: 1, Deletions: 1, Insertions: 1}) {
+		t.Errorf("CountEdits() = %+v", counts)
+	}
+	if got := Count(reference, hypothesis); got != counts {
+		t.Errorf("Count() = %+v, want %+v", got, counts)
+	}
+	if got := Diff(edits); got != "the cat [-sat-]{+sit+} on [-the-] mat {+today+}" {
+		t.Errorf("Diff() = %q", got)
+	}
+	if rate := counts.Rate(); rate != 0.5 {
+		t.Errorf("Rate() = %v, want 0.5", rate)
+	}
+	if rate := Count(nil, hypothesis).Rate(); rate != 1 {
+		t.Errorf("Rate() with an empty reference = %v, want 1", rate)
+	}
+	if got := Count(reference, nil); got.Deletions != 6 || got.Reference != 6 {
+		t.Errorf("Count() with an empty hypothesis = %+v", got)
+	}
+}
+
+func TestScoreAndReport(t *testing.T) {
+	var report Report
+	report.Add(Score("a", "The 2 cats.", "the two cats", Normalizer{}))
+	report.Add(Score("b", "Good morning everyone", "good mourning", Normalizer{}))
+	report.Add(FileResult{Name: "c", Error: "canceled"})
+	if first := report.Files[0]; first.WER != 0 || first.CER != 0 || first.Diff != "" {
+		t.Errorf("Files[0] = %+v, want a perfect score", first)
+	}
+	second := report.Files[1]
+	if second.Words != (ErrorCounts{Reference: 3, Matches: 1, Substitutions: 1, Deletions: 1}) || second.Diff != "good [-morning-]{+mourning+} [-everyone-]" {
+		t.Errorf("Files[1] = %+v", second)
+	}
+	if report.Words.Reference != 6 || report.WER != 2.0/6 || report.Failed != 1 {
+		t.Errorf("totals = %+v, WER %v, failed %d", report.Words, report.WER, report.Failed)
+	}
+	if report.Characters.Insertions != 1 || report.Characters.Deletions != 8 {
+		t.Errorf("Characters = %+v", report.Characters)
+	}
+
+	var thresholdErr *ThresholdError
+	err := report.Check(Thresholds{MaxWER: 0.2, MaxFileWER: 0.5})
+	if !errors.As(err, &thresholdErr) || len(thresholdErr.Violations) != 3 {
+		t.Fatalf("Check() = %v, want 3 violations", err)
+	}
+	if !strings.Contains(err.Error(), "WER 33.33% > 20.00%") || !strings.Contains(err.Error(), "b: WER 66.67%") {
+		t.Errorf("Check() = %v", err)
+	}
+	if err = report.Check(Thresholds{MaxWER: 0.4, AllowFailures: true}); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+
+	var text bytes.Buffer
+	if err = report.WriteText(&text, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"b: WER 66.67% (S=1 D=1 I=0 N=3)",
+		"    good [-morning-]{+mourning+} [-everyone-]\n",
+		"c: error: canceled\n",
+		"TOTAL (3 files, 1 failed): WER 33.33%",
+	} {
+		if !strings.Contains(text.String(), line) {
+			t.Errorf("WriteText() = %q, want %q", text.String(), line)
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err = report.WriteJSON(&encoded); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err = json.Unmarshal(encoded.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.WER != report.WER || decoded.Failed != 1 || decoded.Files[1].Words != second.Words || decoded.Files[2].Error != "canceled" {
+		t.Errorf("WriteJSON() = %s", encoded.String())
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eval")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"one.wav":        "",
+		"one.txt":        "Hello world.",
+		"sub/two.WAV":    "",
+		"sub/two.txt":    "See you at 5.",
+		"sub/notes.json": "{}",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	samples, err := LoadSamples(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0].Name != "one" || samples[1].Name != "sub/two" {
+		t.Fatalf("LoadSamples() = %+v", samples)
+	}
+
+	hypotheses := map[string]string{"one": "hello word", "sub/two": "see you at five"}
+	recognize := func(ctx context.Context, path string) (string, error) {
+		name, _ := filepath.Rel(dir, strings.TrimSuffix(path, filepath.Ext(path)))
+		return hypotheses[filepath.ToSlash(name)], nil
+	}
+	report, err := Run(context.Background(), samples, recognize, Normalizer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Words.Reference != 6 || report.Words.Substitutions != 1 || report.Files[1].WER != 0 {
+		t.Errorf("Run() = %+v", report)
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "three.wav"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadSamples(dir); err == nil || !strings.Contains(err.Error(), "three.txt") {
+		t.Errorf("LoadSamples() = %v, want a missing reference error", err)
+	}
+}
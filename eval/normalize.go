@@ -0,0 +1,200 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package eval
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Normalizer normalizes reference and hypothesis texts before they are scored, so that differences of case,
+// punctuation and number forms are not counted as errors. The zero value applies all the normalizations.
+//
+// Numbers written with digits are spelled out in English: "25" becomes "twenty five", "3.5" "three point five",
+// "1,000" "one thousand", "2nd" "second" and "50%" "fifty percent". Spelled-out numbers are split at hyphens, so
+// that "twenty-five" and "25" match. Set KeepNumbers for other languages.
+type Normalizer struct {
+	// KeepCase disables lowercasing.
+	KeepCase bool
+
+	// KeepPunctuation disables the removal of punctuation. Apostrophes within words, as in "don't", are always kept.
+	KeepPunctuation bool
+
+	// KeepNumbers disables spelling out numbers.
+	KeepNumbers bool
+}
+
+var (
+	cardinalPattern = regexp.MustCompile(`^(?:\d{1,3}(?:,\d{3})+|\d+)(?:\.\d+)?$`)
+	ordinalPattern  = regexp.MustCompile(`^(\d+)(?:st|nd|rd|th)$`)
+)
+
+// Words returns the normalized words of text.
+func (normalizer Normalizer) Words(text string) []string {
+	if !normalizer.KeepCase {
+		text = strings.ToLower(text)
+	}
+	text = strings.NewReplacer("’", "'", "‘", "'").Replace(text)
+	var tokens []string
+	if normalizer.KeepPunctuation {
+		tokens = strings.Fields(text)
+	} else {
+		tokens = strings.FieldsFunc(text, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '-' || r == '/' || unicode.Is(unicode.Pd, r)
+		})
+	}
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !normalizer.KeepPunctuation {
+			token = strings.TrimFunc(token, func(r rune) bool {
+				return r != '%' && isPunctuation(r)
+			})
+		}
+		if !normalizer.KeepNumbers {
+			if spelled := spellNumber(token, normalizer.KeepCase); spelled != nil {
+				words = append(words, spelled...)
+				continue
+			}
+		}
+		if !normalizer.KeepPunctuation {
+			token = strings.Map(func(r rune) rune {
+				if r != '\'' && isPunctuation(r) {
+					return -1
+				}
+				return r
+			}, strings.Trim(token, "'"))
+		}
+		if token != "" {
+			words = append(words, token)
+		}
+	}
+	return words
+}
+
+// Normalize returns the normalized words of text, separated by single spaces.
+func (normalizer Normalizer) Normalize(text string) string {
+	return strings.Join(normalizer.Words(text), " ")
+}
+
+func isPunctuation(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// spellNumber spells out a cardinal, decimal, ordinal or percentage written with digits, or returns nil if token is
+// not a number.
+func spellNumber(token string, keepCase bool) []string {
+	var suffix []string
+	if strings.HasSuffix(token, "%") {
+		token = strings.TrimSuffix(token, "%")
+		suffix = []string{"percent"}
+	}
+	if !keepCase {
+		token = strings.ToLower(token)
+	}
+	if match := ordinalPattern.FindStringSubmatch(token); match != nil && suffix == nil {
+		words := spellInteger(match[1])
+		last := len(words) - 1
+		words[last] = ordinal(words[last])
+		return words
+	}
+	if !cardinalPattern.MatchString(token) {
+		return nil
+	}
+	token = strings.Replace(token, ",", "", -1)
+	integer, fraction := token, ""
+	if dot := strings.IndexByte(token, '.'); dot >= 0 {
+		integer, fraction = token[:dot], token[dot+1:]
+	}
+	words := spellInteger(integer)
+	if fraction != "" {
+		words = append(words, "point")
+		words = append(words, spellDigits(fraction)...)
+	}
+	return append(words, suffix...)
+}
+
+var (
+	smallNumbers = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	tens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scales = []string{"", "thousand", "million", "billion", "trillion"}
+)
+
+// spellInteger spells out a string of digits. Numbers with leading zeros, such as codes, and numbers too large to be
+// read as such are spelled digit by digit.
+func spellInteger(digits string) []string {
+	if len(digits) > 1 && digits[0] == '0' || len(digits) > 3*len(scales) {
+		return spellDigits(digits)
+	}
+	if digits == "0" {
+		return []string{smallNumbers[0]}
+	}
+	var groups [][]string
+	for end := len(digits); end > 0; end -= 3 {
+		start := end - 3
+		if start < 0 {
+			start = 0
+		}
+		groups = append(groups, spellHundreds(digits[start:end]))
+	}
+	var words []string
+	for scale := len(groups) - 1; scale >= 0; scale-- {
+		if len(groups[scale]) == 0 {
+			continue
+		}
+		words = append(words, groups[scale]...)
+		if scales[scale] != "" {
+			words = append(words, scales[scale])
+		}
+	}
+	return words
+}
+
+// spellHundreds spells out up to three digits, returning nothing for zero.
+func spellHundreds(digits string) []string {
+	n := 0
+	for _, d := range digits {
+		n = n*10 + int(d-'0')
+	}
+	var words []string
+	if n >= 100 {
+		words = append(words, smallNumbers[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		words = append(words, smallNumbers[n])
+	default:
+		words = append(words, tens[n/10])
+		if n%10 != 0 {
+			words = append(words, smallNumbers[n%10])
+		}
+	}
+	return words
+}
+
+func spellDigits(digits string) []string {
+	words := make([]string, 0, len(digits))
+	for _, d := range digits {
+		words = append(words, smallNumbers[d-'0'])
+	}
+	return words
+}
+
+var irregularOrdinals = map[string]string{
+	"one": "first", "two": "second", "three": "third", "five": "fifth", "eight": "eighth", "nine": "ninth",
+	"twelve": "twelfth",
+}
+
+func ordinal(word string) string {
+	if irregular, ok := irregularOrdinals[word]; ok {
+		return irregular
+	}
+	if strings.HasSuffix(word, "y") {
+		return strings.TrimSuffix(word, "y") + "ieth"
+	}
+	return word + "th"
+}
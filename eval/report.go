@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FileResult is the score of the recognition of one audio file.
+type FileResult struct {
+	// Name identifies the file, e.g. its path relative to the corpus directory without extension.
+	Name string `json:"name"`
+
+	// Reference and Hypothesis are the normalized texts.
+	Reference  string `json:"reference"`
+	Hypothesis string `json:"hypothesis"`
+
+	WER        float64     `json:"wer"`
+	CER        float64     `json:"cer"`
+	Words      ErrorCounts `json:"words"`
+	Characters ErrorCounts `json:"characters"`
+
+	// Diff is the word diff of the hypothesis against the reference, see Diff.
+	Diff string `json:"diff,omitempty"`
+
+	// Error is set when the file could not be recognized or its reference could not be read. The other fields are
+	// then empty.
+	Error string `json:"error,omitempty"`
+
+	// Edits is the word alignment.
+	Edits []Edit `json:"-"`
+}
+
+// Score normalizes a reference and a hypothesis and scores the hypothesis.
+func Score(name string, reference string, hypothesis string, normalizer Normalizer) FileResult {
+	referenceWords, hypothesisWords := normalizer.Words(reference), normalizer.Words(hypothesis)
+	edits := Align(referenceWords, hypothesisWords)
+	result := FileResult{
+		Name:       name,
+		Reference:  strings.Join(referenceWords, " "),
+		Hypothesis: strings.Join(hypothesisWords, " "),
+		Words:      CountEdits(edits),
+		Characters: Count(Characters(referenceWords), Characters(hypothesisWords)),
+		Edits:      edits,
+	}
+	result.WER = result.Words.Rate()
+	result.CER = result.Characters.Rate()
+	if result.Words.Errors() > 0 {
+		result.Diff = Diff(edits)
+	}
+	return result
+}
+
+// Report gathers the scores of a corpus. The totals are computed over all the words and characters of the corpus,
+// so that long files weigh more than short ones; the files that failed are left out.
+type Report struct {
+	Files      []FileResult `json:"files"`
+	WER        float64      `json:"wer"`
+	CER        float64      `json:"cer"`
+	Words      ErrorCounts  `json:"words"`
+	Characters ErrorCounts  `json:"characters"`
+
+	// Failed is the number of files with an Error.
+	Failed int `json:"failed"`
+}
+
+// Add adds the result of a file to the report and updates the totals.
+func (report *Report) Add(result FileResult) {
+	report.Files = append(report.Files, result)
+	if result.Error != "" {
+		report.Failed++
+		return
+	}
+	report.Words = report.Words.Add(result.Words)
+	report.Characters = report.Characters.Add(result.Characters)
+	report.WER = report.Words.Rate()
+	report.CER = report.Characters.Rate()
+}
+
+// Thresholds are the maximum error rates accepted by Report.Check, e.g. 0.15 for 15%. Zero thresholds are not
+// checked.
+type Thresholds struct {
+	MaxWER float64 `json:"maxWer,omitempty"`
+	MaxCER float64 `json:"maxCer,omitempty"`
+
+	// MaxFileWER is checked for each file, to catch regressions on a single file hidden by the total.
+	MaxFileWER float64 `json:"maxFileWer,omitempty"`
+
+	// AllowFailures accepts files that failed, which are rejected otherwise.
+	AllowFailures bool `json:"allowFailures,omitempty"`
+}
+
+// ThresholdError reports the thresholds exceeded by a report.
+type ThresholdError struct {
+	Violations []string
+}
+
+func (e *ThresholdError) Error() string {
+	return "thresholds exceeded: " + strings.Join(e.Violations, "; ")
+}
+
+// Check returns a *ThresholdError if the report exceeds the thresholds, or nil.
+func (report *Report) Check(thresholds Thresholds) error {
+	var violations []string
+	if report.Failed > 0 && !thresholds.AllowFailures {
+		violations = append(violations, fmt.Sprintf("%d of %d files failed", report.Failed, len(report.Files)))
+	}
+	if thresholds.MaxWER > 0 && report.WER > thresholds.MaxWER {
+		violations = append(violations, fmt.Sprintf("WER %s > %s", percent(report.WER), percent(thresholds.MaxWER)))
+	}
+	if thresholds.MaxCER > 0 && report.CER > thresholds.MaxCER {
+		violations = append(violations, fmt.Sprintf("CER %s > %s", percent(report.CER), percent(thresholds.MaxCER)))
+	}
+	if thresholds.MaxFileWER > 0 {
+		for _, file := range report.Files {
+			if file.Error == "" && file.WER > thresholds.MaxFileWER {
+				violations = append(violations, fmt.Sprintf("%s: WER %s > %s", file.Name, percent(file.WER), percent(thresholds.MaxFileWER)))
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return &ThresholdError{Violations: violations}
+	}
+	return nil
+}
+
+// WriteJSON writes the report as indented JSON.
+func (report *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteText writes one line per file with its error rates and breakdowns, followed by the totals. With diffs, the
+// word diff of each file with errors is written under it.
+func (report *Report) WriteText(w io.Writer, diffs bool) error {
+	writer := bufio.NewWriter(w)
+	for _, file := range report.Files {
+		if file.Error != "" {
+			fmt.Fprintf(writer, "%s: error: %s\n", file.Name, file.Error)
+			continue
+		}
+		fmt.Fprintf(writer, "%s: %s\n", file.Name, formatRates(file.WER, file.Words, file.CER, file.Characters))
+		if diffs && file.Diff != "" {
+			fmt.Fprintf(writer, "    %s\n", file.Diff)
+		}
+	}
+	fmt.Fprintf(writer, "TOTAL (%d files, %d failed): %s\n", len(report.Files), report.Failed, formatRates(report.WER, report.Words, report.CER, report.Characters))
+	return writer.Flush()
+}
+
+func formatRates(wer float64, words ErrorCounts, cer float64, characters ErrorCounts) string {
+	return fmt.Sprintf("WER %s (S=%d D=%d I=%d N=%d), CER %s (S=%d D=%d I=%d N=%d)",
+		percent(wer), words.Substitutions, words.Deletions, words.Insertions, words.Reference,
+		percent(cer), characters.Substitutions, characters.Deletions, characters.Insertions, characters.Reference)
+}
+
+func percent(rate float64) string {
+	return fmt.Sprintf("%.2f%%", rate*100)
+}
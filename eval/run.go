@@ -0,0 +1,135 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package eval
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recognition"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// Sample is an audio file of a corpus and its reference transcript.
+type Sample struct {
+	// Name is the path of the audio file relative to the corpus directory, without extension.
+	Name string
+
+	// Audio is the path of the WAV file.
+	Audio string
+
+	// Reference is the path of the reference .txt file.
+	Reference string
+}
+
+// LoadSamples finds the WAV files in dir and its subdirectories, and pairs each of them with the .txt file of the
+// same name holding its reference transcript. It fails if a reference is missing.
+func LoadSamples(dir string) ([]Sample, error) {
+	var samples []Sample
+	var missing []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.EqualFold(filepath.Ext(path), ".wav") {
+			return err
+		}
+		base := strings.TrimSuffix(path, filepath.Ext(path))
+		reference := base + ".txt"
+		if _, err := os.Stat(reference); err != nil {
+			missing = append(missing, reference)
+			return nil
+		}
+		name, err := filepath.Rel(dir, base)
+		if err != nil {
+			return err
+		}
+		samples = append(samples, Sample{Name: filepath.ToSlash(name), Audio: path, Reference: reference})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing reference transcripts: %s", strings.Join(missing, ", "))
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no WAV files in %s", dir)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Name < samples[j].Name
+	})
+	return samples, nil
+}
+
+// Recognizer recognizes the speech of an audio file and returns the text of all its phrases.
+type Recognizer func(ctx context.Context, path string) (string, error)
+
+// Run recognizes the samples one after the other and scores them against their references. Files that cannot be
+// recognized are reported with an Error. Run stops early, returning the report so far and the error of the context,
+// when the context is done.
+func Run(ctx context.Context, samples []Sample, recognize Recognizer, normalizer Normalizer) (*Report, error) {
+	report := new(Report)
+	for _, sample := range samples {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		reference, err := ioutil.ReadFile(sample.Reference)
+		if err != nil {
+			report.Add(FileResult{Name: sample.Name, Error: err.Error()})
+			continue
+		}
+		hypothesis, err := recognize(ctx, sample.Audio)
+		if err != nil {
+			report.Add(FileResult{Name: sample.Name, Error: err.Error()})
+			continue
+		}
+		report.Add(Score(sample.Name, string(reference), hypothesis, normalizer))
+	}
+	return report, nil
+}
+
+// NewRecognizer returns a Recognizer running a continuous recognition of each file with a SpeechRecognizer created
+// from config. The config must stay open while the Recognizer is used.
+func NewRecognizer(config *speech.SpeechConfig) Recognizer {
+	return func(ctx context.Context, path string) (string, error) {
+		return recognizeFile(ctx, config, path)
+	}
+}
+
+// NewEmbeddedRecognizer returns a Recognizer using the embedded models of config, see NewRecognizer.
+func NewEmbeddedRecognizer(config *speech.EmbeddedSpeechConfig) Recognizer {
+	return NewRecognizer(config.GetSpeechConfig())
+}
+
+func recognizeFile(ctx context.Context, config *speech.SpeechConfig, path string) (string, error) {
+	input, err := recognition.OpenFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer input.Close()
+	recognizer, err := speech.NewSpeechRecognizerFromConfig(config, input.Config)
+	if err != nil {
+		return "", err
+	}
+	defer recognizer.Close()
+
+	var mu sync.Mutex
+	var phrases []string
+	err = recognition.Recognize(ctx, recognizer, input, func(result *speech.SpeechRecognitionResult) error {
+		mu.Lock()
+		phrases = append(phrases, result.Text)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return strings.Join(phrases, " "), nil
+}
@@ -0,0 +1,27 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package cancellation builds the errors of the operations whose result was canceled. It is shared by the speech and
+// dialog packages.
+package cancellation
+
+import (
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/diagnostics/logging"
+)
+
+// Error returns the error of the outcome of an operation whose result was canceled. The session is taken from the
+// SpeechSessionID property of properties, if not nil, when canceled does not report it. When canceled with an error,
+// the log captured for the session, if any, is attached in a *logging.SessionLogError.
+func Error(canceled *common.CancellationError, properties *common.PropertyCollection) error {
+	if canceled.SessionID == "" && properties != nil {
+		canceled.SessionID = properties.GetProperty(common.SpeechSessionID, "")
+	}
+	if canceled.Reason != common.Error {
+		return canceled
+	}
+	if log := logging.CapturedSessionLog(canceled.SessionID); log != nil {
+		return &logging.SessionLogError{Err: canceled, Log: *log}
+	}
+	return canceled
+}
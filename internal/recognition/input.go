@@ -0,0 +1,155 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package recognition runs continuous recognitions of files, streams and the microphone until the end of their audio.
+// It is shared by the batch and eval packages and the spx-go command.
+package recognition
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+)
+
+// Input is the audio config of a recognition and, for streamed input, the source pumped into it.
+type Input struct {
+	// Config is the audio config to create the recognizer with.
+	Config *audio.AudioConfig
+
+	// Live is set for the microphone, whose recognition ends when interrupted rather than at the end of the audio.
+	Live bool
+
+	format *audio.AudioStreamFormat
+	stream *audio.PushAudioInputStream
+	source io.ReadCloser
+
+	stop    context.CancelFunc
+	stopped chan struct{}
+}
+
+// OpenFile opens the file at path. WAV files are read directly, other files are streamed in the container format
+// of their extension, or in any format the service detects if the extension is unknown.
+func OpenFile(path string) (*Input, error) {
+	if strings.EqualFold(filepath.Ext(path), ".wav") {
+		config, err := audio.NewAudioConfigFromWavFileInput(path)
+		if err != nil {
+			return nil, err
+		}
+		return &Input{Config: config}, nil
+	}
+	container, ok := audio.ContainerFormatFromFileName(path)
+	if !ok {
+		container = audio.ANY
+	}
+	format, err := audio.GetCompressedFormat(container)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		format.Close()
+		return nil, err
+	}
+	return NewStreamInput(format, file)
+}
+
+// NewStreamInput streams source in format. The input takes ownership of format and source, and releases them when
+// closed or when it cannot be created. Standard input is not closed.
+func NewStreamInput(format *audio.AudioStreamFormat, source io.ReadCloser) (*Input, error) {
+	input := &Input{format: format, source: source}
+	var err error
+	if input.stream, err = audio.CreatePushAudioInputStreamFromFormat(format); err != nil {
+		input.Close()
+		return nil, err
+	}
+	if input.Config, err = audio.NewAudioConfigFromStreamInput(input.stream); err != nil {
+		input.Close()
+		return nil, err
+	}
+	return input, nil
+}
+
+// NewMicrophoneInput opens the microphone device, or the default microphone if device is empty.
+func NewMicrophoneInput(device string) (*Input, error) {
+	var config *audio.AudioConfig
+	var err error
+	if device != "" {
+		config, err = audio.NewAudioConfigFromMicrophoneInput(device)
+	} else {
+		config, err = audio.NewAudioConfigFromDefaultMicrophoneInput()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Input{Config: config, Live: true}, nil
+}
+
+// Start pumps the source into the stream, if any, until its end or until the context is done. The stream is then
+// closed, which ends the recognition. The returned channel receives the error of the pump.
+func (input *Input) Start(ctx context.Context) <-chan error {
+	pumped := make(chan error, 1)
+	if input.stream == nil {
+		pumped <- nil
+		return pumped
+	}
+	ctx, input.stop = context.WithCancel(ctx)
+	input.stopped = make(chan struct{})
+	go func() {
+		defer close(input.stopped)
+		defer input.stream.CloseStream()
+		pumped <- pump(ctx, input.source, input.stream)
+	}()
+	return pumped
+}
+
+func pump(ctx context.Context, source io.Reader, stream *audio.PushAudioInputStream) error {
+	buffer := make([]byte, 32*1024)
+	for ctx.Err() == nil {
+		n, err := source.Read(buffer)
+		if n > 0 {
+			if writeErr := stream.Write(buffer[:n]); writeErr != nil {
+				return writeErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// Close stops the pump and releases the input.
+func (input *Input) Close() {
+	if input.stop != nil {
+		input.stop()
+		if input.source == os.Stdin {
+			select {
+			case <-input.stopped:
+			default:
+				// Reading standard input cannot be interrupted: leave the stream to the exit of the process rather
+				// than releasing it under the pump.
+				return
+			}
+		}
+		<-input.stopped
+	}
+	if input.source != nil && input.source != os.Stdin {
+		input.source.Close()
+	}
+	if input.Config != nil {
+		input.Config.Close()
+	}
+	if input.stream != nil {
+		input.stream.Close()
+	}
+	if input.format != nil {
+		input.format.Close()
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package recognition
+
+import (
+	"context"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// Session tracks a continuous recognition, translation or transcription until it ends.
+type Session struct {
+	done chan error
+}
+
+// NewSession creates a session.
+func NewSession() *Session {
+	return &Session{done: make(chan error, 1)}
+}
+
+// Finish ends the session, with the error of a cancellation if any. Only the first call counts. It is meant to be
+// called from the SessionStopped and Canceled handlers of the recognizer.
+func (s *Session) Finish(err error) {
+	select {
+	case s.done <- err:
+	default:
+	}
+}
+
+// Run pumps the input once the session is started, then waits until the session ends or the context is done, and
+// stops it. Live input ends when interrupted, which is not an error.
+func (s *Session) Run(ctx context.Context, input *Input, start func() chan error, stop func() chan error) error {
+	if err := <-start(); err != nil {
+		return err
+	}
+	pumped := input.Start(ctx)
+	var err error
+	select {
+	case err = <-s.done:
+	case <-ctx.Done():
+		if !input.Live {
+			err = ctx.Err()
+		}
+	}
+	<-stop()
+	select {
+	case pumpErr := <-pumped:
+		if err == nil && pumpErr != nil && pumpErr != context.Canceled {
+			err = pumpErr
+		}
+	default:
+	}
+	return err
+}
+
+// Recognize runs a continuous recognition of the input with recognizer until the end of its audio, calling recognized
+// with each recognized phrase. An error returned by recognized ends the recognition. The recognizer must have been
+// created with the config of the input; its Recognized, Canceled and SessionStopped handlers are replaced.
+func Recognize(ctx context.Context, recognizer *speech.SpeechRecognizer, input *Input, recognized func(*speech.SpeechRecognitionResult) error) error {
+	session := NewSession()
+	recognizer.Recognized(func(event speech.SpeechRecognitionEventArgs) {
+		defer event.Close()
+		result := event.Result
+		if result.Reason != common.RecognizedSpeech || result.Text == "" {
+			return
+		}
+		if err := recognized(&result); err != nil {
+			session.Finish(err)
+		}
+	})
+	recognizer.Canceled(func(event speech.SpeechRecognitionCanceledEventArgs) {
+		defer event.Close()
+		if event.Reason == common.Error {
+			session.Finish(event.Err())
+		}
+	})
+	recognizer.SessionStopped(func(event speech.SessionEventArgs) {
+		defer event.Close()
+		session.Finish(nil)
+	})
+	return session.Run(ctx, input, func() chan error {
+		return recognizer.StartContinuousRecognitionWithContextAsync(ctx)
+	}, recognizer.StopContinuousRecognitionAsync)
+}
@@ -0,0 +1,202 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package recorder reports the metrics of the SDK to the hook of the metrics package. It is called by the speech, pool
+// and limit packages, and does nothing when no hook is set.
+package recorder
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
+)
+
+// Results are reported at most once per result ID, since the same result can reach the SDK both as an event and as
+// the outcome of an asynchronous call.
+
+const recentResultCount = 4096
+
+type recorder struct {
+	mu      sync.Mutex
+	started map[uintptr]time.Time
+	recent  map[string]struct{}
+	order   []string
+}
+
+var state = recorder{
+	started: make(map[uintptr]time.Time),
+	recent:  make(map[string]struct{}),
+}
+
+// firstReport checks whether the result is reported for the first time, remembering it if so.
+func (r *recorder) firstReport(kind string, resultID string) bool {
+	if resultID == "" {
+		return true
+	}
+	key := kind + "/" + resultID
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.recent[key]; ok {
+		return false
+	}
+	if len(r.order) >= recentResultCount {
+		delete(r.recent, r.order[0])
+		r.order = r.order[1:]
+	}
+	r.recent[key] = struct{}{}
+	r.order = append(r.order, key)
+	return true
+}
+
+// RecognitionStarted records the start of a recognition by the recognizer with the given handle.
+func RecognitionStarted(recognizer string, handle uintptr) {
+	h := metrics.CurrentHook()
+	if h == nil {
+		return
+	}
+	state.mu.Lock()
+	state.started[handle] = time.Now()
+	state.mu.Unlock()
+	h.Add(metrics.Sessions, 1, recognizer)
+}
+
+// RecognitionStopped records the end of a recognition by the recognizer with the given handle.
+func RecognitionStopped(handle uintptr) {
+	state.mu.Lock()
+	delete(state.started, handle)
+	state.mu.Unlock()
+}
+
+// PartialReceived records an intermediate result of the recognizer with the given handle. Only the first one after
+// the start of the recognition is measured.
+func PartialReceived(recognizer string, handle uintptr) {
+	h := metrics.CurrentHook()
+	if h == nil {
+		return
+	}
+	state.mu.Lock()
+	start, ok := state.started[handle]
+	delete(state.started, handle)
+	state.mu.Unlock()
+	if ok {
+		h.Observe(metrics.TimeToFirstPartial, time.Since(start).Seconds(), recognizer)
+	}
+}
+
+// Recognized records a final recognition result, with the value of its SpeechServiceResponseRecognitionLatencyMs
+// property and the duration of the audio it covers.
+func Recognized(recognizer string, resultID string, latencyMs string, audio time.Duration) {
+	h := metrics.CurrentHook()
+	if h == nil || !state.firstReport(recognizer, resultID) {
+		return
+	}
+	if latency, ok := parseMilliseconds(latencyMs); ok {
+		h.Observe(metrics.RecognitionLatency, latency, recognizer)
+	}
+	if audio > 0 {
+		h.Add(metrics.AudioProcessed, audio.Seconds(), recognizer)
+	}
+}
+
+// Canceled records a recognition or synthesis canceled with an error.
+func Canceled(recognizer string, resultID string, errorCode string) {
+	h := metrics.CurrentHook()
+	if h == nil || !state.firstReport(recognizer+"/canceled", resultID) {
+		return
+	}
+	h.Add(metrics.Cancellations, 1, recognizer, errorCode)
+}
+
+// SynthesisStarted records a synthesis request of the given plain text or SSML.
+func SynthesisStarted(text string, isSSML bool) {
+	h := metrics.CurrentHook()
+	if h == nil {
+		return
+	}
+	h.Add(metrics.Sessions, 1, metrics.SpeechSynthesizer)
+	characters := utf8.RuneCountInString(text)
+	if isSSML {
+		characters = metrics.SSMLCharacterCount(text)
+	}
+	h.Add(metrics.CharactersSynthesized, float64(characters), metrics.SpeechSynthesizer)
+}
+
+// PoolAcquired records the time waited to acquire an instance of the named pool.
+func PoolAcquired(pool string, wait time.Duration) {
+	if h := metrics.CurrentHook(); h != nil {
+		h.Observe(metrics.PoolWaitTime, wait.Seconds(), pool)
+	}
+}
+
+// PoolReleased records the time an instance of the named pool was acquired.
+func PoolReleased(pool string, busy time.Duration) {
+	if h := metrics.CurrentHook(); h != nil {
+		h.Add(metrics.PoolBusyTime, busy.Seconds(), pool)
+	}
+}
+
+// PoolInstanceClosed records an instance of the named pool closed for the given reason, PoolUnhealthy or
+// PoolExpired.
+func PoolInstanceClosed(pool string, reason string) {
+	if h := metrics.CurrentHook(); h != nil {
+		h.Add(metrics.PoolClosed, 1, pool, reason)
+	}
+}
+
+// LimiterWaited records the time a session of the key waited in the queue of a limiter.
+func LimiterWaited(key string, wait time.Duration) {
+	if h := metrics.CurrentHook(); h != nil {
+		h.Observe(metrics.LimiterWaitTime, wait.Seconds(), key)
+	}
+}
+
+// SessionThrottled records a session of the key throttled by the service.
+func SessionThrottled(key string) {
+	if h := metrics.CurrentHook(); h != nil {
+		h.Add(metrics.LimiterThrottled, 1, key)
+	}
+}
+
+// SynthesisLatencies are the latency properties of a synthesis result, in milliseconds as reported by the service.
+type SynthesisLatencies struct {
+	FirstByteMs string
+	FinishMs    string
+	NetworkMs   string
+	ServiceMs   string
+	UnderrunMs  string
+}
+
+// Synthesized records the latencies of a completed synthesis.
+func Synthesized(resultID string, latencies SynthesisLatencies) {
+	h := metrics.CurrentHook()
+	if h == nil || !state.firstReport(metrics.SpeechSynthesizer, resultID) {
+		return
+	}
+	for _, sample := range []struct {
+		metric *metrics.Metric
+		value  string
+	}{
+		{metrics.SynthesisFirstByteLatency, latencies.FirstByteMs},
+		{metrics.SynthesisFinishLatency, latencies.FinishMs},
+		{metrics.SynthesisNetworkLatency, latencies.NetworkMs},
+		{metrics.SynthesisServiceLatency, latencies.ServiceMs},
+		{metrics.SynthesisUnderrunTime, latencies.UnderrunMs},
+	} {
+		if value, ok := parseMilliseconds(sample.value); ok {
+			h.Observe(sample.metric, value, metrics.SpeechSynthesizer)
+		}
+	}
+}
+
+// parseMilliseconds converts a millisecond property value to seconds.
+func parseMilliseconds(value string) (float64, bool) {
+	ms, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || ms < 0 {
+		return 0, false
+	}
+	return ms / 1000, true
+}
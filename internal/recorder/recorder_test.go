@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package recorder
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
+)
+
+type sample struct {
+	metric *metrics.Metric
+	value  float64
+	labels string
+}
+
+type fakeHook struct {
+	mu      sync.Mutex
+	samples []sample
+}
+
+func (h *fakeHook) Observe(metric *metrics.Metric, value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.samples = append(h.samples, sample{metric, value, strings.Join(labelValues, ",")})
+}
+
+func (h *fakeHook) Add(metric *metrics.Metric, value float64, labelValues ...string) {
+	h.Observe(metric, value, labelValues...)
+}
+
+func (h *fakeHook) find(metric *metrics.Metric) []sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var found []sample
+	for _, s := range h.samples {
+		if s.metric == metric {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+func useFakeHook() (*fakeHook, func()) {
+	h := &fakeHook{}
+	metrics.SetHook(h)
+	return h, func() { metrics.SetHook(nil) }
+}
+
+func TestRecognitionMetrics(t *testing.T) {
+	h, restore := useFakeHook()
+	defer restore()
+
+	RecognitionStarted(metrics.SpeechRecognizer, 1)
+	PartialReceived(metrics.SpeechRecognizer, 1)
+	PartialReceived(metrics.SpeechRecognizer, 1)
+	Recognized(metrics.SpeechRecognizer, "result-1", "250", 2*time.Second)
+	Recognized(metrics.SpeechRecognizer, "result-1", "250", 2*time.Second)
+	Recognized(metrics.SpeechRecognizer, "result-2", "", 0)
+	Canceled(metrics.SpeechRecognizer, "result-3", "ConnectionFailure")
+	RecognitionStopped(1)
+
+	if got := h.find(metrics.Sessions); len(got) != 1 || got[0].labels != metrics.SpeechRecognizer {
+		t.Errorf("sessions = %v, want one for %s", got, metrics.SpeechRecognizer)
+	}
+	if got := h.find(metrics.TimeToFirstPartial); len(got) != 1 {
+		t.Errorf("time to first partial = %v, want one sample", got)
+	}
+	if got := h.find(metrics.RecognitionLatency); len(got) != 1 || got[0].value != 0.25 {
+		t.Errorf("latency = %v, want one sample of 0.25", got)
+	}
+	if got := h.find(metrics.AudioProcessed); len(got) != 1 || got[0].value != 2 {
+		t.Errorf("audio = %v, want one sample of 2", got)
+	}
+	if got := h.find(metrics.Cancellations); len(got) != 1 || got[0].labels != "speech,ConnectionFailure" {
+		t.Errorf("cancellations = %v, want one for speech,ConnectionFailure", got)
+	}
+}
+
+func TestSynthesisMetrics(t *testing.T) {
+	h, restore := useFakeHook()
+	defer restore()
+
+	SynthesisStarted("héllo", false)
+	SynthesisStarted(`<speak version="1.0"><voice name="x">hi &amp; bye</voice></speak>`, true)
+	Synthesized("synth-1", SynthesisLatencies{FirstByteMs: "120", FinishMs: "900", UnderrunMs: "x"})
+
+	characters := h.find(metrics.CharactersSynthesized)
+	if len(characters) != 2 || characters[0].value != 5 || characters[1].value != 8 {
+		t.Errorf("characters = %v, want 5 and 8", characters)
+	}
+	if got := h.find(metrics.SynthesisFirstByteLatency); len(got) != 1 || got[0].value != 0.12 {
+		t.Errorf("first byte latency = %v, want 0.12", got)
+	}
+	if got := h.find(metrics.SynthesisUnderrunTime); len(got) != 0 {
+		t.Errorf("underrun = %v, want no sample for an invalid value", got)
+	}
+}
+
+func TestNoHook(t *testing.T) {
+	RecognitionStarted(metrics.SpeechRecognizer, 2)
+	PartialReceived(metrics.SpeechRecognizer, 2)
+	Recognized(metrics.SpeechRecognizer, "result-4", "100", time.Second)
+	h, restore := useFakeHook()
+	defer restore()
+	Recognized(metrics.SpeechRecognizer, "result-4", "100", time.Second)
+	if got := h.find(metrics.RecognitionLatency); len(got) != 1 {
+		t.Errorf("latency = %v, want results reported without a hook to be ignored", got)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package spans creates the trace spans of the SDK with the tracer of the tracing package. It is called by the speech
+// package to trace the sessions of a recognizer, identified by its handle, and synthesis requests. The functions do
+// nothing for recognizers on which Begin was not called while a tracer was set.
+package spans
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/tracing"
+)
+
+type sessionTrace struct {
+	tracer     tracing.Tracer
+	ctx        context.Context
+	attributes []tracing.Attribute
+	sessionCtx context.Context
+	session    tracing.Span
+	utterance  tracing.Span
+}
+
+var (
+	sessionsMu sync.Mutex
+	sessions   = make(map[uintptr]*sessionTrace)
+)
+
+// Begin starts tracing the recognizer with the given handle, with ctx as the parent of its spans and attributes added
+// to all of them. Spans still open from a previous recognition are ended. It returns false when tracing is disabled.
+func Begin(ctx context.Context, handle uintptr, attributes ...tracing.Attribute) bool {
+	End(handle)
+	t := tracing.CurrentTracer()
+	if t == nil {
+		return false
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	sessions[handle] = &sessionTrace{
+		tracer:     t,
+		ctx:        ctx,
+		attributes: append([]tracing.Attribute(nil), attributes...),
+		sessionCtx: ctx,
+	}
+	return true
+}
+
+// Active checks whether the recognizer with the given handle is traced.
+func Active(handle uintptr) bool {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	_, ok := sessions[handle]
+	return ok
+}
+
+// SessionStarted starts the session span.
+func SessionStarted(handle uintptr, sessionID string) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	trace, ok := sessions[handle]
+	if !ok {
+		return
+	}
+	trace.endSession()
+	attributes := append(append([]tracing.Attribute(nil), trace.attributes...), tracing.String(tracing.AttrSessionID, sessionID))
+	trace.sessionCtx, trace.session = trace.tracer.Start(trace.ctx, tracing.SessionSpan, attributes...)
+}
+
+// SessionStopped ends the session span, and the utterance span if still open.
+func SessionStopped(handle uintptr) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	if trace, ok := sessions[handle]; ok {
+		trace.endSession()
+	}
+}
+
+// UtteranceStarted starts an utterance span, as a child of the session span.
+func UtteranceStarted(handle uintptr) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	trace, ok := sessions[handle]
+	if !ok {
+		return
+	}
+	trace.endUtterance()
+	trace.startUtterance()
+}
+
+// UtteranceEnded ends the utterance span with the attributes of its final result. Without a preceding
+// UtteranceStarted, e.g. for a NoMatch result, a span covering only the result is created.
+func UtteranceEnded(handle uintptr, attributes ...tracing.Attribute) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	trace, ok := sessions[handle]
+	if !ok {
+		return
+	}
+	if trace.utterance == nil {
+		trace.startUtterance()
+	}
+	trace.utterance.SetAttributes(attributes...)
+	trace.endUtterance()
+}
+
+// Canceled marks the utterance span, if open, and the session span as failed with err, if not nil, and adds the
+// cancellation attributes to them. The utterance span is ended.
+func Canceled(handle uintptr, err error, attributes ...tracing.Attribute) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	trace, ok := sessions[handle]
+	if !ok {
+		return
+	}
+	for _, span := range []tracing.Span{trace.utterance, trace.session} {
+		if span == nil {
+			continue
+		}
+		span.SetAttributes(attributes...)
+		if err != nil {
+			span.SetError(err)
+		}
+	}
+	trace.endUtterance()
+}
+
+// End ends the open spans of the recognizer with the given handle and stops tracing it.
+func End(handle uintptr) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	if trace, ok := sessions[handle]; ok {
+		trace.endSession()
+		delete(sessions, handle)
+	}
+}
+
+func (trace *sessionTrace) startUtterance() {
+	_, trace.utterance = trace.tracer.Start(trace.sessionCtx, tracing.UtteranceSpan, trace.attributes...)
+}
+
+func (trace *sessionTrace) endUtterance() {
+	if trace.utterance != nil {
+		trace.utterance.End()
+		trace.utterance = nil
+	}
+}
+
+func (trace *sessionTrace) endSession() {
+	trace.endUtterance()
+	if trace.session != nil {
+		trace.session.End()
+		trace.session = nil
+	}
+	trace.sessionCtx = trace.ctx
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attributes ...tracing.Attribute) {}
+func (noopSpan) SetError(err error)                            {}
+func (noopSpan) End()                                          {}
+
+// StartSpan starts a span with the current tracer, or returns a span doing nothing when tracing is disabled.
+func StartSpan(ctx context.Context, name string, attributes ...tracing.Attribute) (context.Context, tracing.Span) {
+	t := tracing.CurrentTracer()
+	if t == nil {
+		return ctx, noopSpan{}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return t.Start(ctx, name, attributes...)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package spans
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/tracing"
+)
+
+type parentKey struct{}
+
+type fakeSpan struct {
+	name       string
+	parent     *fakeSpan
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (span *fakeSpan) SetAttributes(attributes ...tracing.Attribute) {
+	for _, attribute := range attributes {
+		span.attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (span *fakeSpan) SetError(err error) {
+	span.err = err
+}
+
+func (span *fakeSpan) End() {
+	span.ended = true
+}
+
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+func (tracer *fakeTracer) Start(ctx context.Context, name string, attributes ...tracing.Attribute) (context.Context, tracing.Span) {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	parent, _ := ctx.Value(parentKey{}).(*fakeSpan)
+	span := &fakeSpan{name: name, parent: parent, attributes: make(map[string]interface{})}
+	span.SetAttributes(attributes...)
+	tracer.spans = append(tracer.spans, span)
+	return context.WithValue(ctx, parentKey{}, span), span
+}
+
+func useFakeTracer() (*fakeTracer, func()) {
+	tracer := &fakeTracer{}
+	tracing.SetTracer(tracer)
+	return tracer, func() { tracing.SetTracer(nil) }
+}
+
+func TestSessionSpans(t *testing.T) {
+	tracer, restore := useFakeTracer()
+	defer restore()
+
+	root := &fakeSpan{name: "request", attributes: make(map[string]interface{})}
+	ctx := context.WithValue(context.Background(), parentKey{}, root)
+	if !Begin(ctx, 1, tracing.String(tracing.AttrRecognizer, "speech")) {
+		t.Fatal("Begin = false, want true with a tracer")
+	}
+	SessionStarted(1, "session-1")
+	UtteranceStarted(1)
+	UtteranceEnded(1, tracing.String(tracing.AttrResultID, "result-1"))
+	UtteranceStarted(1)
+	Canceled(1, errors.New("connection failed"), tracing.String(tracing.AttrCancellationErrorCode, "ConnectionFailure"))
+	SessionStopped(1)
+	End(1)
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("spans = %d, want 3", len(tracer.spans))
+	}
+	session, first, second := tracer.spans[0], tracer.spans[1], tracer.spans[2]
+	if session.name != tracing.SessionSpan || session.parent != root || session.attributes[tracing.AttrSessionID] != "session-1" {
+		t.Errorf("session span = %+v", session)
+	}
+	if first.name != tracing.UtteranceSpan || first.parent != session || first.attributes[tracing.AttrResultID] != "result-1" ||
+		first.attributes[tracing.AttrRecognizer] != "speech" {
+		t.Errorf("first utterance span = %+v", first)
+	}
+	if second.err == nil || session.err == nil || second.attributes[tracing.AttrCancellationErrorCode] != "ConnectionFailure" {
+		t.Errorf("canceled spans = %+v / %+v", second, session)
+	}
+	for _, span := range tracer.spans {
+		if !span.ended {
+			t.Errorf("span %s not ended", span.name)
+		}
+	}
+}
+
+func TestUtteranceWithoutStart(t *testing.T) {
+	tracer, restore := useFakeTracer()
+	defer restore()
+
+	Begin(context.Background(), 2)
+	UtteranceEnded(2, tracing.String(tracing.AttrReason, "NoMatch"))
+	End(2)
+	if len(tracer.spans) != 1 || tracer.spans[0].parent != nil || !tracer.spans[0].ended {
+		t.Errorf("spans = %+v, want one ended root utterance span", tracer.spans)
+	}
+}
+
+func TestTracingDisabled(t *testing.T) {
+	if Begin(context.Background(), 3) {
+		t.Error("Begin = true, want false without a tracer")
+	}
+	SessionStarted(3, "session")
+	if Active(3) {
+		t.Error("Active = true, want false without a tracer")
+	}
+	_, span := StartSpan(context.Background(), tracing.SynthesisSpan)
+	span.SetAttributes(tracing.String(tracing.AttrVoice, "voice"))
+	span.End()
+}
+
+func TestBeginEndsPreviousSpans(t *testing.T) {
+	tracer, restore := useFakeTracer()
+	defer restore()
+
+	Begin(context.Background(), 4)
+	SessionStarted(4, "session-1")
+	UtteranceStarted(4)
+	Begin(context.Background(), 4)
+	if len(tracer.spans) != 2 || !tracer.spans[0].ended || !tracer.spans[1].ended {
+		t.Errorf("spans = %+v, want the previous spans ended", tracer.spans)
+	}
+	End(4)
+	if Active(4) {
+		t.Error("Active = true after End")
+	}
+}
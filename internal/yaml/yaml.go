@@ -0,0 +1,429 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package yaml decodes the subset of YAML used by configuration files: block mappings and sequences, flow sequences
+// and mappings of scalars, plain and quoted scalars, and comments. Anchors, tags, multi-line scalars and multiple
+// documents are not supported.
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal decodes data into v following the JSON field tags of v, rejecting unknown fields. Plain scalars that
+// look like numbers or booleans are decoded as strings when the field they decode into is a string, so that e.g. an
+// all-digit key is not rejected.
+func Unmarshal(data []byte, v interface{}) error {
+	value, err := parse(data)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+	converted, err := json.Marshal(coerce(value, reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(converted))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func parse(data []byte) (interface{}, error) {
+	lines, err := splitLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	p := &parser{lines: lines}
+	value, err := p.parseBlock(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(lines) {
+		return nil, p.errorf("unexpected indentation")
+	}
+	return value, nil
+}
+
+// coerce converts the number and boolean scalars of value that decode into a string of type t to strings.
+func coerce(value interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			switch t.Kind() {
+			case reflect.Struct:
+				if field, ok := fieldType(t, key); ok {
+					v[key] = coerce(item, field)
+				}
+			case reflect.Map:
+				v[key] = coerce(item, t.Elem())
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				v[i] = coerce(item, t.Elem())
+			}
+		}
+	case json.Number:
+		if t.Kind() == reflect.String {
+			return v.String()
+		}
+	case bool:
+		if t.Kind() == reflect.String {
+			return strconv.FormatBool(v)
+		}
+	}
+	return value
+}
+
+// fieldType returns the type of the struct field that encoding/json decodes the key into.
+func fieldType(t reflect.Type, key string) (reflect.Type, bool) {
+	var folded reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if name == key {
+			return field.Type, true
+		}
+		if folded == nil && strings.EqualFold(name, key) {
+			folded = field.Type
+		}
+	}
+	return folded, folded != nil
+}
+
+type sourceLine struct {
+	number int
+	indent int
+	text   string
+}
+
+func splitLines(data string) ([]sourceLine, error) {
+	var lines []sourceLine
+	for i, raw := range strings.Split(strings.Replace(data, "\r\n", "\n", -1), "\n") {
+		text := strings.TrimRight(stripComment(raw), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || (len(lines) == 0 && trimmed == "---") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("yaml: line %d: tabs are not allowed in indentation", i+1)
+		}
+		if trimmed == "---" || trimmed == "..." {
+			return nil, fmt.Errorf("yaml: line %d: multiple documents are not supported", i+1)
+		}
+		lines = append(lines, sourceLine{number: i + 1, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	return lines, nil
+}
+
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+type parser struct {
+	lines []sourceLine
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	number := 0
+	if p.pos < len(p.lines) {
+		number = p.lines[p.pos].number
+	} else if len(p.lines) > 0 {
+		number = p.lines[len(p.lines)-1].number
+	}
+	return fmt.Errorf("yaml: line %d: %s", number, fmt.Sprintf(format, args...))
+}
+
+func isListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *parser) parseBlock(indent int) (interface{}, error) {
+	if isListItem(p.lines[p.pos].text) {
+		return p.parseList(indent)
+	}
+	return p.parseMap(indent)
+}
+
+func (p *parser) parseMap(indent int) (interface{}, error) {
+	result := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+		if isListItem(line.text) {
+			return nil, p.errorf("unexpected list item in a mapping")
+		}
+		colon := findColon(line.text)
+		if colon < 0 {
+			return nil, p.errorf("expected a key followed by a colon")
+		}
+		key, err := parseKey(strings.TrimSpace(line.text[:colon]))
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		if _, ok := result[key]; ok {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+		rest := strings.TrimSpace(line.text[colon+1:])
+		p.pos++
+		if rest != "" {
+			if result[key], err = parseValue(rest); err != nil {
+				p.pos--
+				return nil, p.errorf("%v", err)
+			}
+			continue
+		}
+		result[key] = nil
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && isListItem(next.text)) {
+				if result[key], err = p.parseBlock(next.indent); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func (p *parser) parseList(indent int) (interface{}, error) {
+	result := []interface{}{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && !isListItem(line.text)) {
+			break
+		}
+		if line.indent > indent || !isListItem(line.text) {
+			return nil, p.errorf("unexpected indentation")
+		}
+		item := strings.TrimLeft(line.text[1:], " ")
+		switch {
+		case item == "":
+			p.pos++
+			var value interface{}
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				var err error
+				if value, err = p.parseBlock(p.lines[p.pos].indent); err != nil {
+					return nil, err
+				}
+			}
+			result = append(result, value)
+		case findColon(item) >= 0 || isListItem(item):
+			// The item is a nested block starting on the same line, e.g. "- name: value".
+			p.lines[p.pos] = sourceLine{number: line.number, indent: line.indent + len(line.text) - len(item), text: item}
+			value, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		default:
+			value, err := parseValue(item)
+			if err != nil {
+				return nil, p.errorf("%v", err)
+			}
+			result = append(result, value)
+			p.pos++
+		}
+	}
+	return result, nil
+}
+
+// findColon returns the index of the colon separating a key from its value, or -1.
+func findColon(text string) int {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return -1
+	}
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i == len(text)-1 || text[i+1] == ' '):
+			return i
+		}
+	}
+	return -1
+}
+
+func parseKey(text string) (string, error) {
+	if text == "" {
+		return "", fmt.Errorf("empty key")
+	}
+	value, err := parseScalar(text)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return text, nil
+}
+
+func parseValue(text string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("unterminated flow sequence")
+		}
+		items, err := splitFlow(text[1 : len(text)-1])
+		if err != nil {
+			return nil, err
+		}
+		result := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			value, err := parseScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		return result, nil
+	case strings.HasPrefix(text, "{"):
+		if !strings.HasSuffix(text, "}") {
+			return nil, fmt.Errorf("unterminated flow mapping")
+		}
+		items, err := splitFlow(text[1 : len(text)-1])
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]interface{}, len(items))
+		for _, item := range items {
+			colon := findColon(item)
+			if colon < 0 {
+				return nil, fmt.Errorf("expected a key followed by a colon in %q", item)
+			}
+			key, err := parseKey(strings.TrimSpace(item[:colon]))
+			if err != nil {
+				return nil, err
+			}
+			if result[key], err = parseScalar(strings.TrimSpace(item[colon+1:])); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case strings.HasPrefix(text, "|") || strings.HasPrefix(text, ">"):
+		return nil, fmt.Errorf("block scalars are not supported")
+	case strings.HasPrefix(text, "&") || strings.HasPrefix(text, "*") || strings.HasPrefix(text, "!"):
+		return nil, fmt.Errorf("anchors, aliases and tags are not supported")
+	}
+	return parseScalar(text)
+}
+
+func splitFlow(text string) ([]string, error) {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			return nil, fmt.Errorf("nested flow collections are not supported")
+		case c == ',':
+			items = append(items, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quoted scalar")
+	}
+	if last := strings.TrimSpace(text[start:]); last != "" {
+		items = append(items, last)
+	} else if len(items) > 0 {
+		return nil, fmt.Errorf("empty item in flow collection")
+	}
+	return items, nil
+}
+
+var numberPattern = regexp.MustCompile(`^[-+]?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+func parseScalar(text string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(text, `"`):
+		if len(text) < 2 || !strings.HasSuffix(text, `"`) {
+			return nil, fmt.Errorf("unterminated quoted scalar %s", text)
+		}
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted scalar %s", text)
+		}
+		return value, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, fmt.Errorf("unterminated quoted scalar %s", text)
+		}
+		return strings.Replace(text[1:len(text)-1], "''", "'", -1), nil
+	}
+	switch text {
+	case "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if numberPattern.MatchString(text) {
+		return json.Number(strings.TrimPrefix(text, "+")), nil
+	}
+	return text, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package yaml
+
+import (
+	"testing"
+)
+
+type testSettings struct {
+	Key     string            `json:"key"`
+	Port    int               `json:"port"`
+	Enabled bool              `json:"enabled"`
+	Names   []string          `json:"names"`
+	Values  map[string]string `json:"values"`
+	Nested  *struct {
+		ID string `json:"id"`
+	} `json:"nested"`
+}
+
+func TestUnmarshalCoercesStrings(t *testing.T) {
+	var settings testSettings
+	data := "key: 12345\nport: 80\nenabled: true\nnames: [1, yes]\nvalues:\n  a: 1.5\n  b: false\nnested: {id: 42}\n"
+	if err := Unmarshal([]byte(data), &settings); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if settings.Key != "12345" || settings.Port != 80 || !settings.Enabled {
+		t.Error("Unexpected settings: ", settings)
+	}
+	if len(settings.Names) != 2 || settings.Names[0] != "1" || settings.Values["a"] != "1.5" || settings.Values["b"] != "false" {
+		t.Error("Unexpected collections: ", settings.Names, settings.Values)
+	}
+	if settings.Nested == nil || settings.Nested.ID != "42" {
+		t.Error("Unexpected nested settings: ", settings.Nested)
+	}
+}
+
+func TestUnmarshalRejectsInvalidDocuments(t *testing.T) {
+	invalid := []string{
+		"key: a\nkey: b\n",
+		"key: a\n  port: 1\n",
+		"key: |\n  multi-line\n",
+		"unknown: value\n",
+		"port: eighty\n",
+		"names: [a, [b]]\n",
+	}
+	for _, data := range invalid {
+		var settings testSettings
+		if err := Unmarshal([]byte(data), &settings); err == nil {
+			t.Error("Expected an error for: ", data)
+		}
+	}
+}
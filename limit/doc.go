@@ -0,0 +1,25 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package limit keeps the recognizers and synthesizers sharing a Speech resource within its quotas.
+//
+// A Limiter is shared by the users of a resource, identified by a key such as the one KeyOf returns for a config.
+// For each key, it limits the concurrent sessions and, with token buckets, the rate of new sessions, each opening a
+// connection, and the rate of synthesized characters. When the service throttles a session nonetheless, i.e. cancels
+// it with the TooManyRequests error code, the new sessions of the key are paused with an exponential backoff.
+//
+//	limiter := limit.New(limit.Options{MaxSessions: 20, ConnectionRate: 10, CharacterRate: 2000})
+//	key := limit.KeyOf(config)
+//
+//	permit, err := limiter.Acquire(ctx, key, limit.SynthesisRequest(ssml, true))
+//	if err != nil {
+//		return err
+//	}
+//	outcome := <-synthesizer.SpeakSsmlAsync(ssml)
+//	defer outcome.Close()
+//	permit.Release(outcome.Error)
+//
+// SetLimiter sets a limiter on a speech config, for the recognizers and synthesizers created from it. The batch
+// package and the server package take a limiter in their options. The time sessions wait in the queue of a
+// limiter and the throttled sessions are reported to the hook of the metrics package, labeled by key.
+package limit
@@ -0,0 +1,313 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package limit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recorder"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
+)
+
+// Default options.
+const (
+	DefaultMinBackoff = time.Second
+	DefaultMaxBackoff = time.Minute
+)
+
+// Options configures a Limiter. The limits apply to each key separately; zero values disable them.
+type Options struct {
+	// MaxSessions is the maximum number of concurrent sessions, i.e. recognitions and syntheses.
+	MaxSessions int
+
+	// ConnectionRate is the maximum number of new sessions per second, each opening a connection, and ConnectionBurst
+	// the number that can start at once, the rate rounded up by default.
+	ConnectionRate  float64
+	ConnectionBurst int
+
+	// CharacterRate is the maximum number of synthesized characters per second, and CharacterBurst the number that
+	// can be sent at once, the rate rounded up by default. Longer texts wait for a full burst.
+	CharacterRate  float64
+	CharacterBurst int
+
+	// MinBackoff is the pause of new sessions after a session is throttled, DefaultMinBackoff by default. It doubles
+	// for each following throttled session, up to MaxBackoff, DefaultMaxBackoff by default, and halves for each
+	// successful one.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Request is the cost of a session.
+type Request struct {
+	// Characters is the number of characters of a synthesis, counted against the character rate.
+	Characters int
+}
+
+// SynthesisRequest returns the request of the synthesis of a plain text or SSML, whose markup is not counted.
+func SynthesisRequest(text string, ssml bool) Request {
+	if ssml {
+		return Request{Characters: metrics.SSMLCharacterCount(text)}
+	}
+	return Request{Characters: utf8.RuneCountInString(text)}
+}
+
+// Stats are the statistics of the sessions of a key.
+type Stats struct {
+	// Active is the number of sessions started and not released, and Waiting the number of Acquire calls waiting.
+	Active  int
+	Waiting int
+
+	// Acquired is the number of sessions started, and WaitTime the total time their Acquire calls waited.
+	Acquired int64
+	WaitTime time.Duration
+
+	// Throttled is the number of sessions released with a TooManyRequests cancellation.
+	Throttled int64
+
+	// Backoff is the current backoff, and PausedUntil the end of the pause of new sessions, if any.
+	Backoff     time.Duration
+	PausedUntil time.Time
+}
+
+// bucket is a token bucket. Tokens can go negative, so that a cost larger than the burst waits for a full bucket.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64, burst int, now time.Time) bucket {
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return bucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+// wait returns the time until n tokens can be taken.
+func (b *bucket) wait(n float64, now time.Time) time.Duration {
+	if b.rate <= 0 || n <= 0 {
+		return 0
+	}
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if need := math.Min(n, b.burst); b.tokens < need {
+		return time.Duration((need - b.tokens) / b.rate * float64(time.Second))
+	}
+	return 0
+}
+
+// full checks whether the bucket has refilled to its burst.
+func (b *bucket) full(now time.Time) bool {
+	return b.rate <= 0 || b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst
+}
+
+func (b *bucket) take(n float64) {
+	if b.rate > 0 {
+		b.tokens -= n
+	}
+}
+
+type keyState struct {
+	connections bucket
+	characters  bucket
+	changed     chan struct{}
+	stats       Stats
+}
+
+// idle checks whether the state is back to the one of a new key, so that it can be evicted: no session is active or
+// waiting, the backoff is over and the buckets are full.
+func (state *keyState) idle(now time.Time) bool {
+	stats := state.stats
+	return stats.Active == 0 && stats.Waiting == 0 && stats.Backoff == 0 && !now.Before(stats.PausedUntil) &&
+		state.connections.full(now) && state.characters.full(now)
+}
+
+// notify wakes up the Acquire calls waiting for a change of the state.
+func (state *keyState) notify() {
+	close(state.changed)
+	state.changed = make(chan struct{})
+}
+
+// Limiter limits the sessions of recognizers and synthesizers sharing a resource, so that they stay within its
+// quotas, e.g. with a key per subscription key and region (see KeyOf). It is safe for concurrent use.
+// The keys without active or waiting sessions, whose limits are back to their initial state, are evicted as new keys
+// are used, which resets their statistics.
+type Limiter struct {
+	options Options
+
+	mu   sync.Mutex
+	keys map[string]*keyState
+}
+
+// New creates a limiter.
+func New(options Options) *Limiter {
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = DefaultMinBackoff
+	}
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = DefaultMaxBackoff
+		if options.MaxBackoff < options.MinBackoff {
+			options.MaxBackoff = options.MinBackoff
+		}
+	}
+	return &Limiter{options: options, keys: make(map[string]*keyState)}
+}
+
+func (l *Limiter) stateLocked(key string, now time.Time) *keyState {
+	state, ok := l.keys[key]
+	if !ok {
+		// The idle keys are evicted as new keys are added, so that the keys do not accumulate.
+		for other, otherState := range l.keys {
+			if otherState.idle(now) {
+				delete(l.keys, other)
+			}
+		}
+		state = &keyState{
+			connections: newBucket(l.options.ConnectionRate, l.options.ConnectionBurst, now),
+			characters:  newBucket(l.options.CharacterRate, l.options.CharacterBurst, now),
+			changed:     make(chan struct{}),
+		}
+		l.keys[key] = state
+	}
+	return state
+}
+
+// Acquire waits until a session of the key can start within the limits, or until the context is done. The session
+// must be released with Permit.Release once it ends.
+func (l *Limiter) Acquire(ctx context.Context, key string, request Request) (*Permit, error) {
+	start := time.Now()
+	l.mu.Lock()
+	state := l.stateLocked(key, start)
+	state.stats.Waiting++
+	for {
+		now := time.Now()
+		var wait time.Duration
+		switch {
+		case now.Before(state.stats.PausedUntil):
+			wait = state.stats.PausedUntil.Sub(now)
+		case l.options.MaxSessions > 0 && state.stats.Active >= l.options.MaxSessions:
+			// Waits for a release.
+			wait = -1
+		default:
+			wait = state.connections.wait(1, now)
+			if characters := state.characters.wait(float64(request.Characters), now); characters > wait {
+				wait = characters
+			}
+		}
+		if wait == 0 {
+			state.connections.take(1)
+			state.characters.take(float64(request.Characters))
+			waited := time.Since(start)
+			state.stats.Waiting--
+			state.stats.Active++
+			state.stats.Acquired++
+			state.stats.WaitTime += waited
+			l.mu.Unlock()
+			recorder.LimiterWaited(key, waited)
+			return &Permit{limiter: l, key: key}, nil
+		}
+		changed := state.changed
+		l.mu.Unlock()
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			expired = timer.C
+		}
+		select {
+		case <-changed:
+		case <-expired:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		l.mu.Lock()
+		if err := ctx.Err(); err != nil {
+			state.stats.Waiting--
+			l.mu.Unlock()
+			return nil, err
+		}
+	}
+}
+
+// Stats returns the statistics of a key.
+func (l *Limiter) Stats(key string) Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if state, ok := l.keys[key]; ok {
+		return state.stats
+	}
+	return Stats{}
+}
+
+// Permit is a session started by Acquire.
+type Permit struct {
+	limiter *Limiter
+	key     string
+	once    sync.Once
+}
+
+// Release ends the session, given its error. A cancellation with the TooManyRequests error code pauses the new
+// sessions of the key with an exponential backoff; a success shortens the backoff. Releasing a permit again does
+// nothing.
+func (permit *Permit) Release(err error) {
+	permit.once.Do(func() {
+		l := permit.limiter
+		throttled := IsThrottled(err)
+		l.mu.Lock()
+		state := l.keys[permit.key]
+		state.stats.Active--
+		switch {
+		case throttled:
+			state.stats.Throttled++
+			backoff := state.stats.Backoff * 2
+			if backoff < l.options.MinBackoff {
+				backoff = l.options.MinBackoff
+			}
+			if backoff > l.options.MaxBackoff {
+				backoff = l.options.MaxBackoff
+			}
+			state.stats.Backoff = backoff
+			if until := time.Now().Add(backoff); until.After(state.stats.PausedUntil) {
+				state.stats.PausedUntil = until
+			}
+		case err == nil:
+			state.stats.Backoff /= 2
+			if state.stats.Backoff < l.options.MinBackoff {
+				state.stats.Backoff = 0
+			}
+		}
+		state.notify()
+		l.mu.Unlock()
+		if throttled {
+			recorder.SessionThrottled(permit.key)
+		}
+	})
+}
+
+// IsThrottled reports whether an error is a cancellation with the TooManyRequests error code.
+func IsThrottled(err error) bool {
+	var canceled *common.CancellationError
+	return errors.As(err, &canceled) && canceled.ErrorCode == common.TooManyRequests
+}
+
+// ResourceKey returns the key of the resource of a region, an endpoint or a host, and a subscription key, which is
+// hashed so that it does not appear in the key, e.g. in metrics.
+func ResourceKey(target string, subscriptionKey string) string {
+	if subscriptionKey == "" {
+		return target
+	}
+	sum := sha256.Sum256([]byte(subscriptionKey))
+	return target + "/" + hex.EncodeToString(sum[:4])
+}
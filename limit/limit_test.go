@@ -0,0 +1,236 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package limit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
+)
+
+var throttled = fmt.Errorf("synthesis: %w", &common.CancellationError{Reason: common.Error, ErrorCode: common.TooManyRequests})
+
+// waitFor polls a condition on the statistics of a key.
+func waitFor(t *testing.T, l *Limiter, key string, condition func(Stats) bool) Stats {
+	for i := 0; i < 500; i++ {
+		if stats := l.Stats(key); condition(stats) {
+			return stats
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stats := l.Stats(key)
+	t.Fatalf("unexpected stats %+v", stats)
+	return stats
+}
+
+func TestLimiterMaxSessions(t *testing.T) {
+	l := New(Options{MaxSessions: 2})
+	ctx := context.Background()
+	a, _ := l.Acquire(ctx, "westus", Request{})
+	b, _ := l.Acquire(ctx, "westus", Request{})
+
+	// The limits are per key.
+	other, err := l.Acquire(ctx, "eastus", Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Release(nil)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(timeout, "westus", Request{}); err != context.DeadlineExceeded {
+		t.Errorf("Acquire() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	acquired := make(chan *Permit)
+	go func() {
+		permit, _ := l.Acquire(ctx, "westus", Request{})
+		acquired <- permit
+	}()
+	waitFor(t, l, "westus", func(stats Stats) bool { return stats.Waiting == 1 })
+	a.Release(nil)
+	a.Release(nil)
+	c := <-acquired
+	stats := l.Stats("westus")
+	if stats.Active != 2 || stats.Acquired != 3 || stats.Waiting != 0 || stats.WaitTime <= 0 {
+		t.Errorf("stats = %+v, want 2 active of 3 acquired", stats)
+	}
+	b.Release(nil)
+	c.Release(nil)
+	if stats := l.Stats("westus"); stats.Active != 0 {
+		t.Errorf("active = %d after the releases, want 0", stats.Active)
+	}
+}
+
+func TestLimiterConnectionRate(t *testing.T) {
+	l := New(Options{ConnectionRate: 100, ConnectionBurst: 2})
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		permit, err := l.Acquire(ctx, "westus", Request{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		permit.Release(nil)
+	}
+	// 2 sessions start at once, the next ones every 10ms.
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 20ms", elapsed)
+	}
+}
+
+func TestLimiterCharacterRate(t *testing.T) {
+	l := New(Options{CharacterRate: 1000, CharacterBurst: 10})
+	ctx := context.Background()
+	start := time.Now()
+	// A text longer than the burst waits for a full bucket, then overdraws it.
+	for _, text := range []string{strings.Repeat("a", 30), "<speak>abcdefghij</speak>"} {
+		permit, err := l.Acquire(ctx, "westus", SynthesisRequest(text, strings.HasPrefix(text, "<")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		permit.Release(nil)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 30ms", elapsed)
+	}
+}
+
+func TestLimiterBackoff(t *testing.T) {
+	l := New(Options{MinBackoff: 20 * time.Millisecond, MaxBackoff: 40 * time.Millisecond})
+	ctx := context.Background()
+	for i, want := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond} {
+		permit, err := l.Acquire(ctx, "westus", Request{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		permit.Release(throttled)
+		if stats := l.Stats("westus"); stats.Backoff != want || stats.Throttled != int64(i+1) {
+			t.Errorf("stats = %+v, want backoff %v", stats, want)
+		}
+	}
+
+	// New sessions wait for the end of the pause.
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(timeout, "westus", Request{}); err != context.DeadlineExceeded {
+		t.Errorf("Acquire() during the pause error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	start := time.Now()
+	permit, err := l.Acquire(ctx, "westus", Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("elapsed = %v, want the rest of the 40ms pause", elapsed)
+	}
+
+	// Successes shorten the backoff, other errors keep it.
+	permit.Release(nil)
+	if stats := l.Stats("westus"); stats.Backoff != 20*time.Millisecond {
+		t.Errorf("backoff = %v after a success, want 20ms", stats.Backoff)
+	}
+	permit, _ = l.Acquire(ctx, "westus", Request{})
+	permit.Release(context.Canceled)
+	if stats := l.Stats("westus"); stats.Backoff != 20*time.Millisecond {
+		t.Errorf("backoff = %v after an error, want 20ms", stats.Backoff)
+	}
+	permit, _ = l.Acquire(ctx, "westus", Request{})
+	permit.Release(nil)
+	if stats := l.Stats("westus"); stats.Backoff != 0 {
+		t.Errorf("backoff = %v after successes, want 0", stats.Backoff)
+	}
+}
+
+func TestLimiterEvictsIdleKeys(t *testing.T) {
+	l := New(Options{ConnectionRate: 1000, ConnectionBurst: 1, MinBackoff: time.Hour})
+	ctx := context.Background()
+	active, _ := l.Acquire(ctx, "active", Request{})
+	throttledPermit, _ := l.Acquire(ctx, "throttled", Request{})
+	throttledPermit.Release(throttled)
+	idle, _ := l.Acquire(ctx, "idle", Request{})
+	idle.Release(nil)
+	time.Sleep(5 * time.Millisecond)
+
+	// Adding a key evicts the idle ones, once their buckets are full.
+	permit, _ := l.Acquire(ctx, "new", Request{})
+	permit.Release(nil)
+	l.mu.Lock()
+	_, activeKept := l.keys["active"]
+	_, throttledKept := l.keys["throttled"]
+	_, idleKept := l.keys["idle"]
+	l.mu.Unlock()
+	if !activeKept || !throttledKept || idleKept {
+		t.Errorf("kept active %v, throttled %v, idle %v, want the keys in use only", activeKept, throttledKept, idleKept)
+	}
+	active.Release(nil)
+}
+
+func TestIsThrottled(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{throttled, true},
+		{&common.CancellationError{Reason: common.Error, ErrorCode: common.ConnectionFailure}, false},
+		{context.DeadlineExceeded, false},
+	}
+	for _, test := range tests {
+		if got := IsThrottled(test.err); got != test.want {
+			t.Errorf("IsThrottled(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
+
+func TestResourceKey(t *testing.T) {
+	if key := ResourceKey("westus", ""); key != "westus" {
+		t.Errorf("ResourceKey() = %q, want the region", key)
+	}
+	a, b := ResourceKey("westus", "secret-a"), ResourceKey("westus", "secret-b")
+	if a == b || !strings.HasPrefix(a, "westus/") || strings.Contains(a, "secret") {
+		t.Errorf("ResourceKey() = %q and %q, want distinct keys without the subscription keys", a, b)
+	}
+	if again := ResourceKey("westus", "secret-a"); again != a {
+		t.Errorf("ResourceKey() = %q, then %q", a, again)
+	}
+}
+
+type recordingHook struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (hook *recordingHook) Observe(metric *metrics.Metric, value float64, labelValues ...string) {
+	hook.Add(metric, value, labelValues...)
+}
+
+func (hook *recordingHook) Add(metric *metrics.Metric, value float64, labelValues ...string) {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	hook.values[fmt.Sprintf("%s %v", metric.Name, labelValues)] += value
+}
+
+func TestLimiterMetrics(t *testing.T) {
+	hook := &recordingHook{values: make(map[string]float64)}
+	metrics.SetHook(hook)
+	defer metrics.SetHook(nil)
+	l := New(Options{})
+	permit, _ := l.Acquire(context.Background(), "westus", Request{})
+	permit.Release(throttled)
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if _, ok := hook.values["speech_limiter_wait_seconds [westus]"]; !ok {
+		t.Errorf("wait time not observed: %v", hook.values)
+	}
+	if count := hook.values["speech_limiter_throttled_total [westus]"]; count != 1 {
+		t.Errorf("throttled = %v, want 1", count)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package limit
+
+import (
+	"context"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// KeyOf returns the key of the resource a config connects to, from its region, endpoint or host and its subscription
+// key.
+func KeyOf(config *speech.SpeechConfig) string {
+	target := config.Region()
+	if endpoint := config.GetProperty(common.SpeechServiceConnectionEndpoint); endpoint != "" {
+		target = endpoint
+	} else if host := config.GetProperty(common.SpeechServiceConnectionHost); host != "" {
+		target = host
+	}
+	return ResourceKey(target, config.SubscriptionKey())
+}
+
+// KeyOfSettings returns the key of the resource of configuration settings, as KeyOf the config they create.
+func KeyOfSettings(settings *speech.ConfigSettings) string {
+	target := settings.Region
+	if settings.Endpoint != "" {
+		target = settings.Endpoint
+	} else if settings.Host != "" {
+		target = settings.Host
+	}
+	return ResourceKey(target, settings.Key)
+}
+
+// SetLimiter sets limiter on config, so that the recognizers and synthesizers created from it acquire their sessions
+// from it with the key of the config, see KeyOf and speech.SpeechConfig.SetLimiter.
+func SetLimiter(config *speech.SpeechConfig, limiter *Limiter) {
+	config.SetLimiter(limiter.SessionLimiter(KeyOf(config)))
+}
+
+// SessionLimiter returns the speech.SessionLimiter acquiring the sessions of a key.
+func (l *Limiter) SessionLimiter(key string) speech.SessionLimiter {
+	return sessionLimiter{limiter: l, key: key}
+}
+
+type sessionLimiter struct {
+	limiter *Limiter
+	key     string
+}
+
+func (l sessionLimiter) AcquireSession(ctx context.Context, text string, ssml bool) (func(err error), error) {
+	permit, err := l.limiter.Acquire(ctx, l.key, SynthesisRequest(text, ssml))
+	if err != nil {
+		return nil, err
+	}
+	return permit.Release, nil
+}
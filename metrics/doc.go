@@ -0,0 +1,17 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package metrics records latency and usage metrics of the speech recognizers and synthesizers.
+// Register a Hook with SetHook, e.g. a PrometheusHook or one created with NewOpenTelemetryHook, and the speech package
+// reports recognition and synthesis latencies, processed audio, synthesized characters, sessions and cancellations
+// to it, labeled by recognizer type.
+//
+// Results of continuous recognitions are delivered through events. The Recognized and Canceled events are recorded
+// whether or not a handler is connected, as long as the hook is set when the recognition starts. Partial results are
+// only seen, and so the time to the first partial result only recorded, when a Recognizing handler is connected. The
+// results of RecognizeOnceAsync and SpeakTextAsync are always recorded.
+//
+// The pools of the pool package report their wait times, the time their instances are in use and the instances they
+// close, labeled by pool name. The limiters of the limit package report the time sessions wait in their queues and
+// the sessions throttled by the service, labeled by limit key.
+package metrics
@@ -0,0 +1,239 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package metrics
+
+import (
+	"sync"
+)
+
+// Kind is the kind of a metric.
+type Kind int
+
+const (
+	// Histogram is a distribution of observed values.
+	Histogram Kind = iota
+
+	// Counter is a monotonically increasing sum.
+	Counter
+)
+
+// Label names.
+const (
+	// LabelRecognizer is the type of recognizer or synthesizer, e.g. speech or translation.
+	LabelRecognizer = "recognizer"
+
+	// LabelErrorCode is the common.CancellationErrorCode of a cancellation, e.g. ConnectionFailure.
+	LabelErrorCode = "error_code"
+
+	// LabelPool is the name of a pool of recognizers or synthesizers (see the pool package).
+	LabelPool = "pool"
+
+	// LabelReason is the reason why a pooled instance is closed: PoolUnhealthy or PoolExpired.
+	LabelReason = "reason"
+
+	// LabelLimitKey is the key of the resource whose sessions a limiter limits (see the limit package).
+	LabelLimitKey = "limit_key"
+)
+
+// Values of the LabelReason label.
+const (
+	// PoolUnhealthy is the reason of instances released after a cancellation with an error.
+	PoolUnhealthy = "unhealthy"
+
+	// PoolExpired is the reason of instances idle for longer than the idle timeout of their pool.
+	PoolExpired = "expired"
+)
+
+// Values of the LabelRecognizer label.
+const (
+	SpeechRecognizer        = "speech"
+	TranslationRecognizer   = "translation"
+	ConversationTranscriber = "conversation_transcriber"
+	SpeechSynthesizer       = "synthesizer"
+)
+
+// Metric describes a metric reported to a Hook.
+type Metric struct {
+	// Name is the metric name following the Prometheus conventions, e.g. speech_recognition_latency_seconds.
+	Name string
+
+	// OTelName is the metric name following the OpenTelemetry conventions, e.g. speech.recognition.latency.
+	OTelName string
+
+	// Unit is the UCUM unit of the values, e.g. s for seconds.
+	Unit string
+
+	// Help describes the metric.
+	Help string
+
+	// Kind is the kind of the metric.
+	Kind Kind
+
+	// Labels are the names of the labels, whose values are passed to the Hook in the same order.
+	Labels []string
+}
+
+// The metrics reported by the SDK. Durations are reported in seconds.
+var (
+	RecognitionLatency = &Metric{
+		Name: "speech_recognition_latency_seconds", OTelName: "speech.recognition.latency", Unit: "s",
+		Help: "Latency of final recognition results, from the SpeechServiceResponseRecognitionLatencyMs property.",
+		Kind: Histogram, Labels: []string{LabelRecognizer},
+	}
+	TimeToFirstPartial = &Metric{
+		Name: "speech_recognition_first_partial_seconds", OTelName: "speech.recognition.first_partial", Unit: "s",
+		Help: "Time from the start of a recognition to its first intermediate result.",
+		Kind: Histogram, Labels: []string{LabelRecognizer},
+	}
+	SynthesisFirstByteLatency = &Metric{
+		Name: "speech_synthesis_first_byte_latency_seconds", OTelName: "speech.synthesis.first_byte_latency", Unit: "s",
+		Help: "Time from the start of a synthesis to the first audio byte.",
+		Kind: Histogram, Labels: []string{LabelRecognizer},
+	}
+	SynthesisFinishLatency = &Metric{
+		Name: "speech_synthesis_finish_latency_seconds", OTelName: "speech.synthesis.finish_latency", Unit: "s",
+		Help: "Time from the start of a synthesis to the last audio byte.",
+		Kind: Histogram, Labels: []string{LabelRecognizer},
+	}
+	SynthesisNetworkLatency = &Metric{
+		Name: "speech_synthesis_network_latency_seconds", OTelName: "speech.synthesis.network_latency", Unit: "s",
+		Help: "Network latency of syntheses.",
+		Kind: Histogram, Labels: []string{LabelRecognizer},
+	}
+	SynthesisServiceLatency = &Metric{
+		Name: "speech_synthesis_service_latency_seconds", OTelName: "speech.synthesis.service_latency", Unit: "s",
+		Help: "Service processing latency of syntheses.",
+		Kind: Histogram, Labels: []string{LabelRecognizer},
+	}
+	SynthesisUnderrunTime = &Metric{
+		Name: "speech_synthesis_underrun_seconds", OTelName: "speech.synthesis.underrun", Unit: "s",
+		Help: "Playback underrun time of syntheses.",
+		Kind: Histogram, Labels: []string{LabelRecognizer},
+	}
+	AudioProcessed = &Metric{
+		Name: "speech_audio_processed_seconds_total", OTelName: "speech.audio.processed", Unit: "s",
+		Help: "Seconds of audio covered by final recognition results.",
+		Kind: Counter, Labels: []string{LabelRecognizer},
+	}
+	CharactersSynthesized = &Metric{
+		Name: "speech_synthesis_characters_total", OTelName: "speech.synthesis.characters", Unit: "{character}",
+		Help: "Characters sent for synthesis, excluding SSML markup.",
+		Kind: Counter, Labels: []string{LabelRecognizer},
+	}
+	Sessions = &Metric{
+		Name: "speech_sessions_total", OTelName: "speech.sessions", Unit: "{session}",
+		Help: "Recognitions and syntheses started.",
+		Kind: Counter, Labels: []string{LabelRecognizer},
+	}
+	Cancellations = &Metric{
+		Name: "speech_cancellations_total", OTelName: "speech.cancellations", Unit: "{cancellation}",
+		Help: "Recognitions and syntheses canceled with an error, by error code.",
+		Kind: Counter, Labels: []string{LabelRecognizer, LabelErrorCode},
+	}
+	PoolWaitTime = &Metric{
+		Name: "speech_pool_wait_seconds", OTelName: "speech.pool.wait", Unit: "s",
+		Help: "Time waited to acquire a pooled recognizer or synthesizer, including its creation if none is idle.",
+		Kind: Histogram, Labels: []string{LabelPool},
+	}
+	PoolBusyTime = &Metric{
+		Name: "speech_pool_busy_seconds_total", OTelName: "speech.pool.busy", Unit: "s",
+		Help: "Time pooled instances spent acquired; its rate divided by the pool size is the utilization.",
+		Kind: Counter, Labels: []string{LabelPool},
+	}
+	PoolClosed = &Metric{
+		Name: "speech_pool_closed_total", OTelName: "speech.pool.closed", Unit: "{instance}",
+		Help: "Pooled instances closed because unhealthy or idle for too long.",
+		Kind: Counter, Labels: []string{LabelPool, LabelReason},
+	}
+	LimiterWaitTime = &Metric{
+		Name: "speech_limiter_wait_seconds", OTelName: "speech.limiter.wait", Unit: "s",
+		Help: "Time sessions waited in the queue of a limiter before starting.",
+		Kind: Histogram, Labels: []string{LabelLimitKey},
+	}
+	LimiterThrottled = &Metric{
+		Name: "speech_limiter_throttled_total", OTelName: "speech.limiter.throttled", Unit: "{session}",
+		Help: "Sessions canceled with TooManyRequests, each backing off the new sessions of a limiter.",
+		Kind: Counter, Labels: []string{LabelLimitKey},
+	}
+)
+
+// All lists the metrics reported by the SDK.
+var All = []*Metric{
+	RecognitionLatency, TimeToFirstPartial,
+	SynthesisFirstByteLatency, SynthesisFinishLatency, SynthesisNetworkLatency, SynthesisServiceLatency, SynthesisUnderrunTime,
+	AudioProcessed, CharactersSynthesized, Sessions, Cancellations,
+	PoolWaitTime, PoolBusyTime, PoolClosed,
+	LimiterWaitTime, LimiterThrottled,
+}
+
+// Hook receives the metrics of the SDK. Label values are given in the order of Metric.Labels.
+// Implementations must be safe for concurrent use, as they are called from the SDK callback threads.
+type Hook interface {
+	// Observe records a value of a Histogram metric.
+	Observe(metric *Metric, value float64, labelValues ...string)
+
+	// Add adds a value to a Counter metric.
+	Add(metric *Metric, value float64, labelValues ...string)
+}
+
+var (
+	hookMu sync.RWMutex
+	hook   Hook
+)
+
+// SetHook sets the hook receiving the metrics of the SDK. Pass nil to stop recording.
+func SetHook(h Hook) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	hook = h
+}
+
+// CurrentHook returns the hook set with SetHook, or nil.
+func CurrentHook() Hook {
+	hookMu.RLock()
+	defer hookMu.RUnlock()
+	return hook
+}
+
+type multiHook []Hook
+
+func (hooks multiHook) Observe(metric *Metric, value float64, labelValues ...string) {
+	for _, h := range hooks {
+		h.Observe(metric, value, labelValues...)
+	}
+}
+
+func (hooks multiHook) Add(metric *Metric, value float64, labelValues ...string) {
+	for _, h := range hooks {
+		h.Add(metric, value, labelValues...)
+	}
+}
+
+// MultiHook returns a hook forwarding the metrics to all the given hooks.
+func MultiHook(hooks ...Hook) Hook {
+	return multiHook(append([]Hook(nil), hooks...))
+}
+
+// SSMLCharacterCount counts the characters of the text content of an SSML document, ignoring the markup and
+// counting each entity reference, e.g. &amp;, as one character.
+func SSMLCharacterCount(ssml string) int {
+	count := 0
+	inTag, inEntity := false, false
+	for _, r := range ssml {
+		switch {
+		case r == '<':
+			inTag, inEntity = true, false
+		case inTag:
+			inTag = r != '>'
+		case inEntity:
+			inEntity = r != ';'
+		case r == '&':
+			inEntity = true
+			count++
+		default:
+			count++
+		}
+	}
+	return count
+}
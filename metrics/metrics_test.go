@@ -0,0 +1,75 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type sample struct {
+	metric *Metric
+	value  float64
+	labels string
+}
+
+type fakeHook struct {
+	mu      sync.Mutex
+	samples []sample
+}
+
+func (h *fakeHook) Observe(metric *Metric, value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.samples = append(h.samples, sample{metric, value, strings.Join(labelValues, ",")})
+}
+
+func (h *fakeHook) Add(metric *Metric, value float64, labelValues ...string) {
+	h.Observe(metric, value, labelValues...)
+}
+
+func (h *fakeHook) find(metric *Metric) []sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var found []sample
+	for _, s := range h.samples {
+		if s.metric == metric {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+type fakeMeter struct {
+	name       string
+	attributes map[string]string
+}
+
+func (m *fakeMeter) RecordFloat64Histogram(ctx context.Context, name, unit, description string, value float64, attributes map[string]string) {
+	m.name, m.attributes = name, attributes
+}
+
+func (m *fakeMeter) AddFloat64Counter(ctx context.Context, name, unit, description string, value float64, attributes map[string]string) {
+	m.name, m.attributes = name, attributes
+}
+
+func TestOpenTelemetryHook(t *testing.T) {
+	meter := &fakeMeter{}
+	hook := NewOpenTelemetryHook(meter)
+	hook.Add(Cancellations, 1, TranslationRecognizer, "BadRequest")
+	if meter.name != "speech.cancellations" || meter.attributes["speech.recognizer"] != TranslationRecognizer ||
+		meter.attributes["speech.cancellation.error_code"] != "BadRequest" {
+		t.Errorf("name/attributes = %v/%v", meter.name, meter.attributes)
+	}
+}
+
+func TestMultiHook(t *testing.T) {
+	first, second := &fakeHook{}, &fakeHook{}
+	MultiHook(first, second).Observe(RecognitionLatency, 1, SpeechRecognizer)
+	if len(first.samples) != 1 || len(second.samples) != 1 {
+		t.Errorf("samples = %d/%d, want 1/1", len(first.samples), len(second.samples))
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package metrics
+
+import (
+	"context"
+)
+
+// OpenTelemetry attribute keys used for the labels of the metrics.
+var openTelemetryAttributes = map[string]string{
+	LabelRecognizer: "speech.recognizer",
+	LabelErrorCode:  "speech.cancellation.error_code",
+	LabelPool:       "speech.pool",
+	LabelReason:     "speech.pool.reason",
+	LabelLimitKey:   "speech.limiter.key",
+}
+
+// OTelMeter is the subset of an OpenTelemetry meter used by the hook returned by NewOpenTelemetryHook.
+// The SDK does not depend on the OpenTelemetry module; applications bridge their meter with a small adapter, e.g.:
+//
+//	type meter struct{ metric.Meter }
+//
+//	func (m meter) RecordFloat64Histogram(ctx context.Context, name, unit, description string, value float64, attributes map[string]string) {
+//		histogram, _ := m.Float64Histogram(name, metric.WithUnit(unit), metric.WithDescription(description))
+//		histogram.Record(ctx, value, metric.WithAttributes(toKeyValues(attributes)...))
+//	}
+//
+//	func (m meter) AddFloat64Counter(ctx context.Context, name, unit, description string, value float64, attributes map[string]string) {
+//		counter, _ := m.Float64Counter(name, metric.WithUnit(unit), metric.WithDescription(description))
+//		counter.Add(ctx, value, metric.WithAttributes(toKeyValues(attributes)...))
+//	}
+//
+// where metric is go.opentelemetry.io/otel/metric. Instruments are cached by the OpenTelemetry SDK, so creating them
+// on each call is cheap.
+type OTelMeter interface {
+	// RecordFloat64Histogram records a value of a histogram instrument.
+	RecordFloat64Histogram(ctx context.Context, name, unit, description string, value float64, attributes map[string]string)
+
+	// AddFloat64Counter adds a value to a counter instrument.
+	AddFloat64Counter(ctx context.Context, name, unit, description string, value float64, attributes map[string]string)
+}
+
+type openTelemetryHook struct {
+	meter OTelMeter
+}
+
+// NewOpenTelemetryHook creates a hook reporting the metrics to an OpenTelemetry meter, using Metric.OTelName as the
+// instrument name and speech.recognizer, speech.cancellation.error_code, speech.pool and speech.pool.reason as the
+// attribute keys.
+func NewOpenTelemetryHook(meter OTelMeter) Hook {
+	return &openTelemetryHook{meter: meter}
+}
+
+func openTelemetryAttributeSet(metric *Metric, labelValues []string) map[string]string {
+	attributes := make(map[string]string, len(metric.Labels))
+	for i, label := range metric.Labels {
+		if i >= len(labelValues) {
+			break
+		}
+		key, ok := openTelemetryAttributes[label]
+		if !ok {
+			key = label
+		}
+		attributes[key] = labelValues[i]
+	}
+	return attributes
+}
+
+func (hook *openTelemetryHook) Observe(metric *Metric, value float64, labelValues ...string) {
+	hook.meter.RecordFloat64Histogram(context.Background(), metric.OTelName, metric.Unit, metric.Help, value,
+		openTelemetryAttributeSet(metric, labelValues))
+}
+
+func (hook *openTelemetryHook) Add(metric *Metric, value float64, labelValues ...string) {
+	hook.meter.AddFloat64Counter(context.Background(), metric.OTelName, metric.Unit, metric.Help, value,
+		openTelemetryAttributeSet(metric, labelValues))
+}
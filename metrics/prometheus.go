@@ -0,0 +1,222 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the histogram buckets, in seconds, used by NewPrometheusHook when none are given.
+var DefaultLatencyBuckets = []float64{0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type promSeries struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// PrometheusHook is a Hook keeping the metrics in memory and exposing them in the Prometheus text format.
+// It is an http.Handler, so it can be mounted as the /metrics endpoint scraped by Prometheus:
+//
+//	hook := metrics.NewPrometheusHook("myapp", nil)
+//	metrics.SetHook(hook)
+//	http.Handle("/metrics", hook)
+//
+// Applications already using the Prometheus client library can instead implement Hook on top of their own
+// HistogramVec and CounterVec, using Metric.Name, Metric.Help and Metric.Labels.
+type PrometheusHook struct {
+	namespace string
+	buckets   []float64
+
+	mu     sync.Mutex
+	series map[*Metric]map[string]*promSeries
+}
+
+// NewPrometheusHook creates a hook whose metric names are prefixed with namespace, if not empty, and whose
+// histograms use the given buckets, in seconds.
+func NewPrometheusHook(namespace string, buckets []float64) *PrometheusHook {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &PrometheusHook{
+		namespace: namespace,
+		buckets:   sorted,
+		series:    make(map[*Metric]map[string]*promSeries),
+	}
+}
+
+func (hook *PrometheusHook) seriesFor(metric *Metric, labelValues []string) *promSeries {
+	byLabels, ok := hook.series[metric]
+	if !ok {
+		byLabels = make(map[string]*promSeries)
+		hook.series[metric] = byLabels
+	}
+	key := strings.Join(labelValues, "\xff")
+	series, ok := byLabels[key]
+	if !ok {
+		series = &promSeries{labelValues: append([]string(nil), labelValues...)}
+		if metric.Kind == Histogram {
+			series.counts = make([]uint64, len(hook.buckets))
+		}
+		byLabels[key] = series
+	}
+	return series
+}
+
+// Observe implements Hook.
+func (hook *PrometheusHook) Observe(metric *Metric, value float64, labelValues ...string) {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	series := hook.seriesFor(metric, labelValues)
+	for i, bound := range hook.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+// Add implements Hook.
+func (hook *PrometheusHook) Add(metric *Metric, value float64, labelValues ...string) {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	series := hook.seriesFor(metric, labelValues)
+	series.count++
+	series.sum += value
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (hook *PrometheusHook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	hook.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format, implementing io.WriterTo.
+func (hook *PrometheusHook) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{writer: bufio.NewWriter(w)}
+	hook.mu.Lock()
+	for _, metric := range All {
+		hook.writeMetric(counter, metric)
+	}
+	for metric := range hook.series {
+		if !isBuiltin(metric) {
+			hook.writeMetric(counter, metric)
+		}
+	}
+	hook.mu.Unlock()
+	if err := counter.writer.Flush(); err != nil && counter.err == nil {
+		counter.err = err
+	}
+	return counter.n, counter.err
+}
+
+func isBuiltin(metric *Metric) bool {
+	for _, builtin := range All {
+		if builtin == metric {
+			return true
+		}
+	}
+	return false
+}
+
+func (hook *PrometheusHook) writeMetric(w *countingWriter, metric *Metric) {
+	byLabels := hook.series[metric]
+	if len(byLabels) == 0 {
+		return
+	}
+	name := metric.Name
+	if hook.namespace != "" {
+		name = hook.namespace + "_" + name
+	}
+	kind := "counter"
+	if metric.Kind == Histogram {
+		kind = "histogram"
+	}
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(metric.Help), name, kind)
+	keys := make([]string, 0, len(byLabels))
+	for key := range byLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		series := byLabels[key]
+		labels := formatLabels(metric.Labels, series.labelValues)
+		if metric.Kind == Counter {
+			fmt.Fprintf(w, "%s%s %s\n", name, wrapLabels(labels), formatFloat(series.sum))
+			continue
+		}
+		for i, bound := range hook.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, wrapLabels(appendLabel(labels, "le", formatFloat(bound))), series.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, wrapLabels(appendLabel(labels, "le", "+Inf")), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", name, wrapLabels(labels), formatFloat(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", name, wrapLabels(labels), series.count)
+	}
+}
+
+func formatLabels(names []string, values []string) string {
+	pairs := make([]string, 0, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs = append(pairs, name+"="+strconv.Quote(value))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func appendLabel(labels string, name string, value string) string {
+	pair := name + "=" + strconv.Quote(value)
+	if labels == "" {
+		return pair
+	}
+	return labels + "," + pair
+}
+
+func wrapLabels(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeHelp(help string) string {
+	return strings.Replace(strings.Replace(help, `\`, `\\`, -1), "\n", `\n`, -1)
+}
+
+type countingWriter struct {
+	writer *bufio.Writer
+	n      int64
+	err    error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.writer.Write(p)
+	w.n += int64(n)
+	w.err = err
+	return n, err
+}
@@ -0,0 +1,39 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusHook(t *testing.T) {
+	hook := NewPrometheusHook("app", []float64{1, 0.5})
+	hook.Observe(RecognitionLatency, 0.3, SpeechRecognizer)
+	hook.Observe(RecognitionLatency, 0.7, SpeechRecognizer)
+	hook.Add(Cancellations, 1, SpeechRecognizer, "ServiceTimeout")
+	hook.Add(Cancellations, 1, SpeechRecognizer, "ServiceTimeout")
+
+	recorder := httptest.NewRecorder()
+	hook.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	for _, want := range []string{
+		"# TYPE app_speech_recognition_latency_seconds histogram\n",
+		`app_speech_recognition_latency_seconds_bucket{recognizer="speech",le="0.5"} 1` + "\n",
+		`app_speech_recognition_latency_seconds_bucket{recognizer="speech",le="1"} 2` + "\n",
+		`app_speech_recognition_latency_seconds_bucket{recognizer="speech",le="+Inf"} 2` + "\n",
+		`app_speech_recognition_latency_seconds_sum{recognizer="speech"} 1` + "\n",
+		`app_speech_recognition_latency_seconds_count{recognizer="speech"} 2` + "\n",
+		"# TYPE app_speech_cancellations_total counter\n",
+		`app_speech_cancellations_total{recognizer="speech",error_code="ServiceTimeout"} 2` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "synthesis") {
+		t.Errorf("unexpected metrics without samples in\n%s", body)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package pool keeps speech synthesizers and recognizers ready for high-throughput servers, which would otherwise pay
+// a native allocation and a connection to the service for each request.
+//
+// A SynthesizerPool reuses synthesizers across syntheses. A RecognizerPool prepares recognizers reading push streams
+// ahead of their use; since a push stream cannot be reopened once closed, recognizers are used once. Both pools keep
+// Min idle instances, optionally connected ahead with PreConnect, and create at most Max instances: Acquire then waits
+// for a release until its context is done. Instances whose last operation was canceled with an error, e.g. a
+// connection failure, are closed rather than reused, and idle instances are closed after IdleTimeout.
+//
+//	synthesizers := pool.NewSynthesizerPool(config, pool.SpeechOptions{
+//		Options:    pool.Options{Min: 2, Max: 16, IdleTimeout: 5 * time.Minute},
+//		PreConnect: true,
+//	})
+//	defer synthesizers.Close()
+//
+//	synthesizer, err := synthesizers.Acquire(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	outcome := <-synthesizer.SpeakSsmlAsync(ssml)
+//	defer outcome.Close()
+//	synthesizers.Release(synthesizer, outcome.Error)
+//
+// Stats returns the current use and the waits of a pool, and its wait times, busy time and closed instances are
+// reported to the hook of the metrics package, labeled by Options.Name. Pool is the underlying pool of any Resource.
+package pool
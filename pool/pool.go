@@ -0,0 +1,371 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recorder"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
+)
+
+// DefaultMax is the maximum number of instances of a pool whose Options.Max is 0.
+const DefaultMax = 8
+
+// ErrClosed is returned by Acquire once the pool is closed.
+var ErrClosed = errors.New("pool: closed")
+
+// Resource is a pooled instance, such as a synthesizer or a recognizer.
+type Resource interface {
+	Close()
+}
+
+// Options configures a pool. Zero values select the defaults.
+type Options struct {
+	// Name labels the metrics of the pool, e.g. "synthesizer".
+	Name string
+
+	// Min is the number of idle instances kept ready, created in the background so that Acquire does not wait for
+	// their creation.
+	Min int
+
+	// Max is the maximum number of instances, idle or acquired. Acquire waits for a release once Max are acquired.
+	Max int
+
+	// IdleTimeout, if not 0, closes the instances idle for longer, except the Min most recently released.
+	IdleTimeout time.Duration
+}
+
+// Stats are the statistics of a pool.
+type Stats struct {
+	// Max is the maximum number of instances of the pool.
+	Max int
+
+	// Size is the number of instances, idle, acquired or being created.
+	Size int
+
+	// Idle is the number of idle instances.
+	Idle int
+
+	// InUse is the number of acquired instances.
+	InUse int
+
+	// Waiting is the number of Acquire calls waiting for a release.
+	Waiting int
+
+	// Acquired is the number of successful Acquire calls.
+	Acquired int64
+
+	// Waited is the number of Acquire calls that waited for a release or a creation, and WaitTime their total wait.
+	Waited   int64
+	WaitTime time.Duration
+
+	// Created is the number of instances created, Unhealthy the number closed after a cancellation with an error, and
+	// Expired the number closed after IdleTimeout.
+	Created   int64
+	Unhealthy int64
+	Expired   int64
+}
+
+// Utilization is the fraction of the maximum number of instances in use.
+func (stats Stats) Utilization() float64 {
+	if stats.Max == 0 {
+		return 0
+	}
+	return float64(stats.InUse) / float64(stats.Max)
+}
+
+type idleResource struct {
+	resource Resource
+	since    time.Time
+}
+
+// Pool keeps instances of a resource that are costly to create. It is safe for concurrent use.
+type Pool struct {
+	create  func(ctx context.Context) (Resource, error)
+	options Options
+
+	mu       sync.Mutex
+	idle     []idleResource
+	acquired map[Resource]time.Time
+	size     int
+	waiters  []chan struct{}
+	filling  bool
+	closed   bool
+	done     chan struct{}
+	stats    Stats
+}
+
+// New creates a pool of the instances returned by create, and starts creating Min of them. The caller must close it.
+func New(create func(ctx context.Context) (Resource, error), options Options) *Pool {
+	if options.Max <= 0 {
+		options.Max = DefaultMax
+	}
+	if options.Min > options.Max {
+		options.Min = options.Max
+	}
+	p := &Pool{
+		create:   create,
+		options:  options,
+		acquired: make(map[Resource]time.Time),
+		done:     make(chan struct{}),
+	}
+	if options.IdleTimeout > 0 {
+		go p.expire()
+	}
+	p.fill()
+	return p
+}
+
+// Acquire returns an idle instance, or creates one if there are less than Max. Otherwise it waits for a release until
+// the context is done. The instance must be released with Release or Discard.
+func (p *Pool) Acquire(ctx context.Context) (Resource, error) {
+	start := time.Now()
+	waited := false
+	p.mu.Lock()
+	for {
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrClosed
+		}
+		if n := len(p.idle); n > 0 {
+			r := p.idle[n-1].resource
+			p.idle = p.idle[:n-1]
+			p.acquiredLocked(r, start, waited)
+			p.mu.Unlock()
+			p.fill()
+			return r, nil
+		}
+		if p.size < p.options.Max {
+			p.size++
+			p.mu.Unlock()
+			r, err := p.create(ctx)
+			p.mu.Lock()
+			if err != nil {
+				p.size--
+				p.notifyLocked()
+				p.mu.Unlock()
+				return nil, err
+			}
+			p.stats.Created++
+			if p.closed {
+				p.size--
+				p.mu.Unlock()
+				r.Close()
+				return nil, ErrClosed
+			}
+			p.acquiredLocked(r, start, true)
+			p.mu.Unlock()
+			p.fill()
+			return r, nil
+		}
+		wait := make(chan struct{})
+		p.waiters = append(p.waiters, wait)
+		p.mu.Unlock()
+		waited = true
+		select {
+		case <-wait:
+			p.mu.Lock()
+		case <-ctx.Done():
+			p.mu.Lock()
+			if !p.removeWaiterLocked(wait) {
+				// The release notified this call, so that the next waiter is notified instead.
+				p.notifyLocked()
+			}
+			p.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (p *Pool) acquiredLocked(r Resource, start time.Time, waited bool) {
+	wait := time.Since(start)
+	p.acquired[r] = time.Now()
+	p.stats.Acquired++
+	if waited {
+		p.stats.Waited++
+		p.stats.WaitTime += wait
+	}
+	recorder.PoolAcquired(p.options.Name, wait)
+}
+
+// notifyLocked wakes up the first waiting Acquire call.
+func (p *Pool) notifyLocked() {
+	if len(p.waiters) > 0 {
+		close(p.waiters[0])
+		p.waiters = p.waiters[1:]
+	}
+}
+
+func (p *Pool) removeWaiterLocked(wait chan struct{}) bool {
+	for i, w := range p.waiters {
+		if w == wait {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Release returns an acquired instance to the pool, given the error of its last operation. Instances whose operation
+// was canceled with an error, or failed otherwise, are closed rather than reused (see Reusable).
+func (p *Pool) Release(r Resource, err error) {
+	reusable := Reusable(err)
+	p.release(r, reusable, !reusable)
+}
+
+// Discard closes an acquired instance rather than returning it to the pool, e.g. a recognizer whose audio stream is
+// closed.
+func (p *Pool) Discard(r Resource) {
+	p.release(r, false, false)
+}
+
+func (p *Pool) release(r Resource, reuse bool, unhealthy bool) {
+	p.mu.Lock()
+	start, ok := p.acquired[r]
+	if !ok {
+		p.mu.Unlock()
+		panic("pool: release of an instance not acquired from the pool")
+	}
+	delete(p.acquired, r)
+	recorder.PoolReleased(p.options.Name, time.Since(start))
+	if reuse && !p.closed {
+		p.idle = append(p.idle, idleResource{resource: r, since: time.Now()})
+		p.notifyLocked()
+		p.mu.Unlock()
+		return
+	}
+	p.size--
+	if unhealthy {
+		p.stats.Unhealthy++
+	}
+	p.notifyLocked()
+	p.mu.Unlock()
+	if unhealthy {
+		recorder.PoolInstanceClosed(p.options.Name, metrics.PoolUnhealthy)
+	}
+	r.Close()
+	p.fill()
+}
+
+// Reusable reports whether an instance can be reused after an operation returning err: it can unless the operation
+// failed, or was canceled with an error, e.g. a connection failure. Operations canceled at the end of their audio or
+// by a call to a Stop method succeed.
+func Reusable(err error) bool {
+	if err == nil {
+		return true
+	}
+	var canceled *common.CancellationError
+	return errors.As(err, &canceled) && canceled.Reason != common.Error
+}
+
+// fill creates instances in the background until Min are idle. Creation errors are left to the next Acquire.
+func (p *Pool) fill() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.filling || p.closed || len(p.idle) >= p.options.Min || p.size >= p.options.Max {
+		return
+	}
+	p.filling = true
+	p.size++
+	go func() {
+		for {
+			r, err := p.create(context.Background())
+			p.mu.Lock()
+			if err == nil {
+				p.stats.Created++
+			}
+			if err != nil || p.closed {
+				p.size--
+				p.filling = false
+				p.notifyLocked()
+				p.mu.Unlock()
+				if err == nil {
+					r.Close()
+				}
+				return
+			}
+			p.idle = append(p.idle, idleResource{resource: r, since: time.Now()})
+			p.notifyLocked()
+			if len(p.idle) >= p.options.Min || p.size >= p.options.Max {
+				p.filling = false
+				p.mu.Unlock()
+				return
+			}
+			p.size++
+			p.mu.Unlock()
+		}
+	}()
+}
+
+// expire closes the instances idle for longer than IdleTimeout, until the pool is closed.
+func (p *Pool) expire() {
+	interval := p.options.IdleTimeout / 2
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.done:
+			return
+		}
+		var expired []Resource
+		deadline := time.Now().Add(-p.options.IdleTimeout)
+		p.mu.Lock()
+		// The idle instances are ordered by release, so that the oldest are first.
+		for len(p.idle) > p.options.Min && p.idle[0].since.Before(deadline) {
+			expired = append(expired, p.idle[0].resource)
+			p.idle = p.idle[1:]
+			p.size--
+			p.stats.Expired++
+		}
+		p.mu.Unlock()
+		for _, r := range expired {
+			recorder.PoolInstanceClosed(p.options.Name, metrics.PoolExpired)
+			r.Close()
+		}
+	}
+}
+
+// Stats returns the statistics of the pool.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Max = p.options.Max
+	stats.Size = p.size
+	stats.Idle = len(p.idle)
+	stats.InUse = len(p.acquired)
+	stats.Waiting = len(p.waiters)
+	return stats
+}
+
+// Close closes the idle instances and fails the waiting Acquire calls. Instances released afterwards are closed.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.done)
+	idle := p.idle
+	p.idle = nil
+	p.size -= len(idle)
+	for _, wait := range p.waiters {
+		close(wait)
+	}
+	p.waiters = nil
+	p.mu.Unlock()
+	for _, r := range idle {
+		r.resource.Close()
+	}
+}
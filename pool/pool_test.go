@@ -0,0 +1,224 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
+)
+
+type counter struct {
+	mu      sync.Mutex
+	created int
+	closed  int
+	fail    error
+}
+
+func (c *counter) create(ctx context.Context) (Resource, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fail != nil {
+		return nil, c.fail
+	}
+	c.created++
+	return &testResource{counter: c, id: c.created}, nil
+}
+
+func (c *counter) counts() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.created, c.closed
+}
+
+type testResource struct {
+	counter *counter
+	id      int
+}
+
+func (r *testResource) Close() {
+	r.counter.mu.Lock()
+	r.counter.closed++
+	r.counter.mu.Unlock()
+}
+
+// waitFor polls a condition on the statistics of the pool.
+func waitFor(t *testing.T, p *Pool, condition func(Stats) bool) Stats {
+	for i := 0; i < 500; i++ {
+		p.mu.Lock()
+		filling := p.filling
+		p.mu.Unlock()
+		if stats := p.Stats(); !filling && condition(stats) {
+			return stats
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stats := p.Stats()
+	t.Fatalf("unexpected stats %+v", stats)
+	return stats
+}
+
+func TestPoolMinMax(t *testing.T) {
+	c := new(counter)
+	p := New(c.create, Options{Min: 2, Max: 3})
+	waitFor(t, p, func(stats Stats) bool { return stats.Idle == 2 })
+
+	ctx := context.Background()
+	a, _ := p.Acquire(ctx)
+	b, _ := p.Acquire(ctx)
+	stats := waitFor(t, p, func(stats Stats) bool { return stats.Idle == 1 && stats.Size == 3 })
+	if stats.InUse != 2 || stats.Utilization() != 2.0/3 {
+		t.Errorf("stats = %+v, want 2 in use", stats)
+	}
+	d, _ := p.Acquire(ctx)
+
+	// The pool is full: Acquire waits for a release.
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.Acquire(timeout); err != context.DeadlineExceeded {
+		t.Errorf("Acquire() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	acquired := make(chan Resource)
+	go func() {
+		r, _ := p.Acquire(ctx)
+		acquired <- r
+	}()
+	waitFor(t, p, func(stats Stats) bool { return stats.Waiting == 1 })
+	p.Release(a, nil)
+	if r := <-acquired; r != a {
+		t.Errorf("Acquire() = %v, want the released instance %v", r, a)
+	}
+
+	// Unhealthy instances are closed and replaced.
+	p.Release(b, &common.CancellationError{Reason: common.Error, ErrorCode: common.ConnectionFailure})
+	p.Release(d, &common.CancellationError{Reason: common.EndOfStream})
+	p.Release(a, nil)
+	stats = waitFor(t, p, func(stats Stats) bool { return stats.Idle == 3 })
+	if stats.Acquired != 4 || stats.Unhealthy != 1 || stats.Waited != 1 || stats.InUse != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+	p.Close()
+	if _, err := p.Acquire(ctx); err != ErrClosed {
+		t.Errorf("Acquire() after Close error = %v, want ErrClosed", err)
+	}
+	if created, closed := c.counts(); created != 4 || closed != 4 {
+		t.Errorf("created = %d, closed = %d, want 4 and 4", created, closed)
+	}
+}
+
+func TestPoolIdleTimeout(t *testing.T) {
+	c := new(counter)
+	p := New(c.create, Options{Min: 1, Max: 4, IdleTimeout: 20 * time.Millisecond})
+	defer p.Close()
+	ctx := context.Background()
+	var acquired []Resource
+	for i := 0; i < 3; i++ {
+		r, err := p.Acquire(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		acquired = append(acquired, r)
+	}
+	for _, r := range acquired {
+		p.Release(r, nil)
+	}
+	stats := waitFor(t, p, func(stats Stats) bool { return stats.Idle == 1 })
+	if stats.Expired < 2 || stats.Size != 1 {
+		t.Errorf("stats = %+v, want the idle instances expired but one", stats)
+	}
+}
+
+func TestPoolCreateError(t *testing.T) {
+	c := &counter{fail: errors.New("no handle")}
+	p := New(c.create, Options{Max: 1})
+	defer p.Close()
+	if _, err := p.Acquire(context.Background()); err != c.fail {
+		t.Errorf("Acquire() error = %v, want %v", err, c.fail)
+	}
+	if stats := p.Stats(); stats.Size != 0 {
+		t.Errorf("size = %d after a creation error, want 0", stats.Size)
+	}
+}
+
+func TestPoolWaitingClose(t *testing.T) {
+	c := new(counter)
+	p := New(c.create, Options{Max: 1})
+	r, _ := p.Acquire(context.Background())
+	failed := make(chan error)
+	go func() {
+		_, err := p.Acquire(context.Background())
+		failed <- err
+	}()
+	waitFor(t, p, func(stats Stats) bool { return stats.Waiting == 1 })
+	p.Close()
+	if err := <-failed; err != ErrClosed {
+		t.Errorf("waiting Acquire() error = %v, want ErrClosed", err)
+	}
+	p.Release(r, nil)
+	if _, closed := c.counts(); closed != 1 {
+		t.Errorf("closed = %d, want the instance released after Close closed", closed)
+	}
+}
+
+func TestReusable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, true},
+		{&common.CancellationError{Reason: common.EndOfStream}, true},
+		{fmt.Errorf("synthesis: %w", &common.CancellationError{Reason: common.Error, ErrorCode: common.ServiceTimeout}), false},
+		{context.Canceled, false},
+	}
+	for _, test := range tests {
+		if got := Reusable(test.err); got != test.want {
+			t.Errorf("Reusable(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
+
+type recordingHook struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (hook *recordingHook) Observe(metric *metrics.Metric, value float64, labelValues ...string) {
+	hook.Add(metric, value, labelValues...)
+}
+
+func (hook *recordingHook) Add(metric *metrics.Metric, value float64, labelValues ...string) {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	hook.values[fmt.Sprintf("%s %v", metric.Name, labelValues)] += value
+}
+
+func TestPoolMetrics(t *testing.T) {
+	hook := &recordingHook{values: make(map[string]float64)}
+	metrics.SetHook(hook)
+	defer metrics.SetHook(nil)
+	c := new(counter)
+	p := New(c.create, Options{Name: "test", Max: 1})
+	defer p.Close()
+	r, _ := p.Acquire(context.Background())
+	time.Sleep(5 * time.Millisecond)
+	p.Release(r, &common.CancellationError{Reason: common.Error, ErrorCode: common.TooManyRequests})
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if busy := hook.values["speech_pool_busy_seconds_total [test]"]; busy < 0.005 {
+		t.Errorf("busy = %v, want at least 5ms", busy)
+	}
+	if closed := hook.values["speech_pool_closed_total [test unhealthy]"]; closed != 1 {
+		t.Errorf("closed = %v, want 1", closed)
+	}
+	if _, ok := hook.values["speech_pool_wait_seconds [test]"]; !ok {
+		t.Errorf("wait time not observed: %v", hook.values)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package pool
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// SpeechOptions configures a SynthesizerPool or a RecognizerPool.
+type SpeechOptions struct {
+	Options
+
+	// PreConnect opens the connection of the instances when they are created, so that their first request does not
+	// wait for it.
+	PreConnect bool
+}
+
+// pooledSynthesizer is a synthesizer and its connection.
+type pooledSynthesizer struct {
+	synthesizer *speech.SpeechSynthesizer
+	connection  *speech.Connection
+}
+
+func (s *pooledSynthesizer) Close() {
+	if s.connection != nil {
+		s.connection.Close()
+	}
+	s.synthesizer.Close()
+}
+
+// SynthesizerPool is a pool of speech synthesizers without audio output, whose audio is returned in their results.
+// A synthesizer is reused across syntheses, e.g. SpeakSsmlAsync calls, unless one is canceled with an error.
+type SynthesizerPool struct {
+	pool *Pool
+
+	mu     sync.Mutex
+	pooled map[*speech.SpeechSynthesizer]*pooledSynthesizer
+}
+
+// NewSynthesizerPool creates a pool of synthesizers of a config, named "synthesizer" by default. The config must be
+// closed after the pool.
+func NewSynthesizerPool(config *speech.SpeechConfig, options SpeechOptions) *SynthesizerPool {
+	if options.Name == "" {
+		options.Name = "synthesizer"
+	}
+	p := &SynthesizerPool{pooled: make(map[*speech.SpeechSynthesizer]*pooledSynthesizer)}
+	p.pool = New(func(ctx context.Context) (Resource, error) {
+		synthesizer, err := speech.NewSpeechSynthesizerFromConfig(config, nil)
+		if err != nil {
+			return nil, err
+		}
+		s := &pooledSynthesizer{synthesizer: synthesizer}
+		if options.PreConnect {
+			if s.connection, err = speech.NewConnectionFromSpeechSynthesizer(synthesizer); err == nil {
+				err = s.connection.Open(false)
+			}
+			if err != nil {
+				s.Close()
+				return nil, err
+			}
+		}
+		return s, nil
+	}, options.Options)
+	return p
+}
+
+// Acquire returns an idle synthesizer, or a new one, waiting for a release until the context is done if the pool is
+// full.
+func (p *SynthesizerPool) Acquire(ctx context.Context) (*speech.SpeechSynthesizer, error) {
+	r, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := r.(*pooledSynthesizer)
+	p.mu.Lock()
+	p.pooled[s.synthesizer] = s
+	p.mu.Unlock()
+	return s.synthesizer, nil
+}
+
+// Release returns a synthesizer to the pool, given the error of its last synthesis, e.g. SpeechSynthesisOutcome.Error.
+// The event handlers connected to it must be disconnected before.
+func (p *SynthesizerPool) Release(synthesizer *speech.SpeechSynthesizer, err error) {
+	p.pool.Release(p.take(synthesizer), err)
+}
+
+// Discard closes an acquired synthesizer rather than returning it to the pool.
+func (p *SynthesizerPool) Discard(synthesizer *speech.SpeechSynthesizer) {
+	p.pool.Discard(p.take(synthesizer))
+}
+
+func (p *SynthesizerPool) take(synthesizer *speech.SpeechSynthesizer) *pooledSynthesizer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.pooled[synthesizer]
+	if !ok {
+		panic("pool: release of a synthesizer not acquired from the pool")
+	}
+	delete(p.pooled, synthesizer)
+	return s
+}
+
+// Stats returns the statistics of the pool.
+func (p *SynthesizerPool) Stats() Stats {
+	return p.pool.Stats()
+}
+
+// Close closes the idle synthesizers. Synthesizers released afterwards are closed.
+func (p *SynthesizerPool) Close() {
+	p.pool.Close()
+}
+
+// Recognizer is a pooled speech recognizer and the push stream of its audio.
+type Recognizer struct {
+	*speech.SpeechRecognizer
+
+	// Stream is the audio input of the recognizer.
+	Stream *audio.PushAudioInputStream
+
+	audioConfig *audio.AudioConfig
+	connection  *speech.Connection
+}
+
+// Close releases the recognizer, its connection and its stream.
+func (r *Recognizer) Close() {
+	if r.connection != nil {
+		r.connection.Close()
+	}
+	if r.SpeechRecognizer != nil {
+		r.SpeechRecognizer.Close()
+	}
+	if r.audioConfig != nil {
+		r.audioConfig.Close()
+	}
+	if r.Stream != nil {
+		r.Stream.Close()
+	}
+}
+
+// RecognizerPool is a pool of speech recognizers reading push streams. A recognizer recognizes a single stream, whose
+// end ends its recognitions, so that recognizers are prepared ahead of their use rather than reused: Release always
+// closes them, and the pool creates new ones to keep Min idle.
+type RecognizerPool struct {
+	pool *Pool
+}
+
+// NewRecognizerPool creates a pool of recognizers of a config reading push streams of the given format, or of the
+// default format, 16 kHz 16-bit mono PCM, if nil. The pool is named "recognizer" by default. The config and the format
+// must be closed after the pool.
+func NewRecognizerPool(config *speech.SpeechConfig, format *audio.AudioStreamFormat, options SpeechOptions) *RecognizerPool {
+	if options.Name == "" {
+		options.Name = "recognizer"
+	}
+	return &RecognizerPool{pool: New(func(ctx context.Context) (Resource, error) {
+		r := new(Recognizer)
+		var err error
+		if format != nil {
+			r.Stream, err = audio.CreatePushAudioInputStreamFromFormat(format)
+		} else {
+			r.Stream, err = audio.CreatePushAudioInputStream()
+		}
+		if err == nil {
+			r.audioConfig, err = audio.NewAudioConfigFromStreamInput(r.Stream)
+		}
+		if err == nil {
+			r.SpeechRecognizer, err = speech.NewSpeechRecognizerFromConfig(config, r.audioConfig)
+		}
+		if err == nil && options.PreConnect {
+			if r.connection, err = speech.NewConnectionFromRecognizer(r.SpeechRecognizer); err == nil {
+				err = r.connection.Open(true)
+			}
+		}
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		return r, nil
+	}, options.Options)}
+}
+
+// Acquire returns a prepared recognizer, or a new one, waiting for a release until the context is done if the pool is
+// full.
+func (p *RecognizerPool) Acquire(ctx context.Context) (*Recognizer, error) {
+	r, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.(*Recognizer), nil
+}
+
+// Release closes a recognizer once its recognitions are stopped, given the error of the last one. Recognizers canceled
+// with an error are counted as unhealthy.
+func (p *RecognizerPool) Release(r *Recognizer, err error) {
+	if Reusable(err) {
+		p.pool.Discard(r)
+	} else {
+		p.pool.release(r, false, true)
+	}
+}
+
+// Stats returns the statistics of the pool.
+func (p *RecognizerPool) Stats() Stats {
+	return p.pool.Stats()
+}
+
+// Close closes the prepared recognizers. Recognizers released afterwards are closed.
+func (p *RecognizerPool) Close() {
+	p.pool.Close()
+}
@@ -38,6 +38,7 @@ func main() {
 		"speech_recognizer:ContinuousFromMicrophone":                    recognizer.ContinuousFromMicrophone,
 		"speech_recognizer:RecognizeContinuousUsingWrapper":             recognizer.RecognizeContinuousUsingWrapper,
 		"speech_recognizer:RecognizeOnceWithPhraseList":                 recognizer.RecognizeOnceWithPhraseList,
+		"speech_recognizer:RecognizeWithPhraseListProfiles":             recognizer.RecognizeWithPhraseListProfiles,
 		"conversation_transcriber:ContinuousFromMicrophone":             conversation_transcriber.ContinuousFromMicrophone,
 		"conversation_transcriber:TranscribeFromFile":                   conversation_transcriber.TranscribeFromFile,
 		"dialog_service_connector:ListenOnce":                           dialog_service_connector.ListenOnce,
@@ -77,3 +77,61 @@ func RecognizeOnceWithPhraseList(subscription string, region string, file string
 	fmt.Println("Got a recognition!")
 	fmt.Println(outcome.Result.Text)
 }
+
+func RecognizeWithPhraseListProfiles(subscription string, region string, file string) {
+	audioConfig, err := audio.NewAudioConfigFromWavFileInput(file)
+	if err != nil {
+		fmt.Println("Got an error: ", err)
+		return
+	}
+	defer audioConfig.Close()
+	config, err := speech.NewSpeechConfigFromSubscription(subscription, region)
+	if err != nil {
+		fmt.Println("Got an error: ", err)
+		return
+	}
+	defer config.Close()
+	speechRecognizer, err := speech.NewSpeechRecognizerFromConfig(config, audioConfig)
+	if err != nil {
+		fmt.Println("Got an error: ", err)
+		return
+	}
+	defer speechRecognizer.Close()
+
+	// Profiles are usually loaded from a directory of phrase list files with speech.LoadPhraseListProfiles.
+	profiles := speech.NewPhraseListProfiles()
+	if err = profiles.Set("product-names", speech.NewPhraseList("Contoso", "Cognito").WithWeight(1.5)); err != nil {
+		fmt.Println("Got an error: ", err)
+		return
+	}
+	if err = profiles.Set("medical", speech.NewPhraseList("peloozoid", "ibuprofen")); err != nil {
+		fmt.Println("Got an error: ", err)
+		return
+	}
+	if err = profiles.Attach(speechRecognizer); err != nil {
+		fmt.Println("Got an error: ", err)
+		return
+	}
+	defer profiles.Detach(speechRecognizer)
+
+	// Each utterance is recognized with the profile active when it starts.
+	for _, profile := range []string{"product-names", "medical"} {
+		if err = profiles.Activate(profile); err != nil {
+			fmt.Println("Got an error activating the profile: ", err)
+			return
+		}
+		var outcome speech.SpeechRecognitionOutcome
+		select {
+		case outcome = <-speechRecognizer.RecognizeOnceAsync():
+		case <-time.After(5 * time.Second):
+			fmt.Println("Timed out")
+			return
+		}
+		if outcome.Error != nil {
+			fmt.Println("Got an error: ", outcome.Error)
+		} else {
+			fmt.Println("Recognized with profile", profile, ":", outcome.Result.Text)
+		}
+		outcome.Close()
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+)
+
+// audioFormat is the format of the audio of a request.
+type audioFormat struct {
+	SampleRate    uint32
+	BitsPerSample uint8
+	Channels      uint8
+	Encoding      audio.AudioStreamWaveFormat
+
+	// Container is the format of compressed audio, such as Ogg Opus, whose other fields are unset. It is 0 for wave
+	// audio.
+	Container audio.AudioStreamContainerFormat
+}
+
+// defaultFormat is the format of raw PCM without parameters, and that of the prepared recognizers.
+var defaultFormat = audioFormat{SampleRate: 16000, BitsPerSample: 16, Channels: 1, Encoding: audio.WavePCM}
+
+var wavMediaTypes = map[string]bool{
+	"audio/wav":      true,
+	"audio/wave":     true,
+	"audio/x-wav":    true,
+	"audio/vnd.wave": true,
+}
+
+// readAudio returns the format of the audio body of a request and a reader of its samples. WAV bodies are recognized
+// by their media type or their RIFF header; raw PCM is sent as audio/pcm, or without media type.
+func readAudio(r *http.Request) (audioFormat, io.Reader, error) {
+	mediaType, params := "", map[string]string(nil)
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		var err error
+		if mediaType, params, err = mime.ParseMediaType(contentType); err != nil {
+			return audioFormat{}, nil, newError(http.StatusBadRequest, "BadRequest", "invalid Content-Type: %v", err)
+		}
+	}
+	body := bufio.NewReaderSize(r.Body, 32*1024)
+	magic, _ := body.Peek(4)
+	switch {
+	case wavMediaTypes[mediaType] || string(magic) == "RIFF":
+		format, size, err := readWAVHeader(body)
+		if err != nil {
+			return audioFormat{}, nil, newError(http.StatusBadRequest, "BadRequest", "invalid WAV audio: %v", err)
+		}
+		// Streamed WAV files have no known size, written as 0 or as the maximum size.
+		if size != 0 && size != math.MaxUint32 {
+			return format, io.LimitReader(body, int64(size)), nil
+		}
+		return format, body, nil
+	case mediaType == "" || mediaType == "audio/pcm" || mediaType == "application/octet-stream":
+		format, err := pcmFormat(params)
+		if err != nil {
+			return audioFormat{}, nil, newError(http.StatusBadRequest, "BadRequest", "invalid PCM parameters: %v", err)
+		}
+		return format, body, nil
+	}
+	return audioFormat{}, nil, newError(http.StatusUnsupportedMediaType, "UnsupportedMediaType",
+		"unsupported audio type %q, expected WAV or audio/pcm", mediaType)
+}
+
+// pcmFormat returns the format of raw PCM from the rate, bits and channels parameters of its media type.
+func pcmFormat(params map[string]string) (audioFormat, error) {
+	format := defaultFormat
+	for name, value := range params {
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || n == 0 {
+			return audioFormat{}, fmt.Errorf("invalid %s %q", name, value)
+		}
+		switch name {
+		case "rate":
+			format.SampleRate = uint32(n)
+		case "bits":
+			if n != 8 && n != 16 && n != 24 && n != 32 {
+				return audioFormat{}, fmt.Errorf("invalid bits %q", value)
+			}
+			format.BitsPerSample = uint8(n)
+		case "channels":
+			if n > 255 {
+				return audioFormat{}, fmt.Errorf("invalid channels %q", value)
+			}
+			format.Channels = uint8(n)
+		}
+	}
+	return format, nil
+}
+
+// waveFormatExtensible is the tag of WAVE_FORMAT_EXTENSIBLE, whose actual format is that of its sub-format.
+const waveFormatExtensible = 0xFFFE
+
+// readWAVHeader reads the header of a WAV file up to its samples, and returns their format and the size of their data
+// chunk.
+func readWAVHeader(r *bufio.Reader) (audioFormat, uint32, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return audioFormat{}, 0, err
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return audioFormat{}, 0, fmt.Errorf("missing RIFF WAVE header")
+	}
+	var format *audioFormat
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return audioFormat{}, 0, fmt.Errorf("missing data chunk: %v", err)
+		}
+		id, size := string(chunk[0:4]), binary.LittleEndian.Uint32(chunk[4:8])
+		switch id {
+		case "data":
+			if format == nil {
+				return audioFormat{}, 0, fmt.Errorf("missing fmt chunk before the data chunk")
+			}
+			return *format, size, nil
+		case "fmt ":
+			if size < 16 || size > 1024 {
+				return audioFormat{}, 0, fmt.Errorf("invalid fmt chunk size %d", size)
+			}
+			data := make([]byte, size+size%2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return audioFormat{}, 0, err
+			}
+			tag := binary.LittleEndian.Uint16(data[0:2])
+			if tag == waveFormatExtensible && size >= 40 {
+				tag = binary.LittleEndian.Uint16(data[24:26])
+			}
+			channels, bits := binary.LittleEndian.Uint16(data[2:4]), binary.LittleEndian.Uint16(data[14:16])
+			format = &audioFormat{
+				SampleRate:    binary.LittleEndian.Uint32(data[4:8]),
+				BitsPerSample: uint8(bits),
+				Channels:      uint8(channels),
+				Encoding:      audio.AudioStreamWaveFormat(tag),
+			}
+			switch {
+			case format.Encoding != audio.WavePCM && format.Encoding != audio.WaveALAW && format.Encoding != audio.WaveMULAW:
+				return audioFormat{}, 0, fmt.Errorf("unsupported encoding 0x%04x, expected PCM, A-law or mu-law", tag)
+			case format.SampleRate == 0, channels == 0 || channels > 255, bits == 0 || bits > 32:
+				return audioFormat{}, 0, fmt.Errorf("invalid format: %d Hz, %d bits, %d channels", format.SampleRate, bits, channels)
+			}
+		default:
+			if _, err := r.Discard(int(size + size%2)); err != nil {
+				return audioFormat{}, 0, err
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package server exposes speech recognition, translation and synthesis over HTTP, for clients that cannot use the
+// SDK directly.
+//
+// The handler serves the following endpoints:
+//
+//	POST /recognize   WAV or raw PCM audio → JSON recognition
+//	POST /translate   WAV or raw PCM audio → JSON recognition with translations, e.g. ?to=de,fr
+//	POST /synthesize  SSML or plain text → audio in the synthesis output format of the config
+//	GET  /stream      WebSocket of audio frames → JSON phrases while they are recognized
+//
+// Raw PCM is sent as audio/pcm, with optional rate, bits and channels parameters, e.g.
+// audio/pcm;rate=8000;bits=16;channels=1; the default is 16 kHz 16-bit mono. The language of a recognition is set with
+// the language query parameter. Audio bodies are streamed into the recognizer as they are received, so that chunked
+// uploads are recognized while they are sent.
+//
+// A stream starts with a JSON StreamControl message, e.g. {"type":"start","language":"en-US","format":"float32",
+// "sampleRate":48000}, followed by binary audio frames, 16-bit or float PCM converted to 16-bit mono, or Ogg Opus.
+// Intermediate and final phrases are sent as JSON StreamEvent messages, translated with targetLanguages or attributed
+// to speakers with diarization. A {"type":"stop"} message ends the audio: the remaining phrases are sent, then an end
+// event, and the server closes the connection. When the client disconnects first, the recognition is stopped.
+//
+// Recognizers are prepared ahead of the requests for the default language and format, and synthesizers are reused
+// across requests, up to a maximum number of concurrent sessions. When the service cancels a request, the
+// cancellation is returned as an HTTP error with a JSON Error body, e.g. 429 for TooManyRequests. A limit.Limiter in
+// the options further limits the rates of sessions and synthesized characters, and pauses new sessions after throttled
+// ones.
+//
+//	settings, err := speech.LoadConfig("")
+//	server, err := server.New(settings, server.Options{MaxSessions: 16})
+//	defer server.Close()
+//	http.ListenAndServe(":8080", server)
+package server
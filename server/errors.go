@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// Error is the JSON body of error responses, as {"error": {...}}.
+type Error struct {
+	// Status is the HTTP status of the response.
+	Status int `json:"-"`
+
+	// Code identifies the error: the CancellationErrorCode of a cancellation, e.g. TooManyRequests, or an HTTP
+	// error such as BadRequest or Timeout.
+	Code string `json:"code"`
+
+	// Message describes the error.
+	Message string `json:"message"`
+
+	// Reason is the CancellationReason of a cancellation.
+	Reason string `json:"reason,omitempty"`
+
+	// SessionID is the ID of the canceled session, when known.
+	SessionID string `json:"sessionId,omitempty"`
+
+	// Retryable is set when the same request may succeed later.
+	Retryable bool `json:"retryable,omitempty"`
+}
+
+func newError(status int, code string, format string, args ...interface{}) *Error {
+	return &Error{Status: status, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// cancellationStatuses are the HTTP statuses of the cancellation error codes. Failures of the gateway to reach or to
+// authenticate with the service are not the fault of the client, and are reported as 502 Bad Gateway.
+var cancellationStatuses = map[common.CancellationErrorCode]int{
+	common.AuthenticationFailure: http.StatusBadGateway,
+	common.BadRequest:            http.StatusBadRequest,
+	common.TooManyRequests:       http.StatusTooManyRequests,
+	common.Forbidden:             http.StatusForbidden,
+	common.ConnectionFailure:     http.StatusBadGateway,
+	common.ServiceTimeout:        http.StatusGatewayTimeout,
+	common.ServiceError:          http.StatusBadGateway,
+	common.ServiceUnavailable:    http.StatusServiceUnavailable,
+	common.RuntimeError:          http.StatusInternalServerError,
+}
+
+// toError converts the error of a request into the error returned to the client.
+func toError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	var canceled *common.CancellationError
+	if errors.As(err, &canceled) {
+		status, ok := cancellationStatuses[canceled.ErrorCode]
+		if !ok || canceled.Reason != common.Error {
+			status = http.StatusInternalServerError
+		}
+		return &Error{
+			Status:    status,
+			Code:      canceled.ErrorCode.String(),
+			Message:   canceled.Error(),
+			Reason:    canceled.Reason.String(),
+			SessionID: canceled.SessionID,
+			Retryable: canceled.Retryable(),
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, common.ErrTimeout) {
+		return &Error{Status: http.StatusGatewayTimeout, Code: "Timeout", Message: err.Error(), Retryable: true}
+	}
+	if errors.Is(err, context.Canceled) {
+		// The client is gone, and will not read the response.
+		return &Error{Status: http.StatusServiceUnavailable, Code: "Canceled", Message: err.Error()}
+	}
+	return &Error{Status: http.StatusInternalServerError, Code: "InternalError", Message: err.Error()}
+}
+
+// writeError writes the error of a request. Retryable errors have a Retry-After header.
+func writeError(w http.ResponseWriter, err error) {
+	e := toError(err)
+	if e.Retryable {
+		w.Header().Set("Retry-After", strconv.Itoa(1))
+	}
+	writeJSON(w, e.Status, struct {
+		Error *Error `json:"error"`
+	}{e})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,360 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/limit"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// Default options.
+const (
+	DefaultMaxSessions         = 8
+	DefaultPreparedRecognizers = 1
+	DefaultTimeout             = 5 * time.Minute
+	DefaultMaxBodySize         = 100 << 20
+)
+
+// Options configures a Server. Zero values select the defaults.
+type Options struct {
+	// MaxSessions is the maximum number of concurrent recognitions, translations, syntheses and streams. Further
+	// requests wait for a session to end.
+	MaxSessions int
+
+	// PreparedRecognizers is the number of recognizers prepared for requests in the default language and format,
+	// i.e. 16 kHz 16-bit mono PCM. A negative number disables them.
+	PreparedRecognizers int
+
+	// Timeout is the maximum duration of a request, including the wait for a session. Streams are not limited, but
+	// their wait for a session is.
+	Timeout time.Duration
+
+	// MaxBodySize is the maximum size of a request body, in bytes.
+	MaxBodySize int64
+
+	// CheckOrigin reports whether the origin of a WebSocket handshake is allowed. By default, browsers may only
+	// connect from pages of the same host.
+	CheckOrigin func(r *http.Request) bool
+
+	// Limiter, if not nil, limits the sessions with the limits of the resource of the settings (see limit.KeyOf),
+	// shared with the other users of the limiter. The wait for the limiter counts in the timeout of the requests.
+	Limiter *limit.Limiter
+}
+
+func (options Options) withDefaults() Options {
+	if options.MaxSessions <= 0 {
+		options.MaxSessions = DefaultMaxSessions
+	}
+	if options.PreparedRecognizers == 0 {
+		options.PreparedRecognizers = DefaultPreparedRecognizers
+	} else if options.PreparedRecognizers < 0 {
+		options.PreparedRecognizers = 0
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = DefaultMaxBodySize
+	}
+	return options
+}
+
+// Phrase is a recognized phrase.
+type Phrase struct {
+	Text       string  `json:"text"`
+	OffsetMs   float64 `json:"offsetMs"`
+	DurationMs float64 `json:"durationMs"`
+
+	// SpeakerID identifies the speaker of the phrase, when transcribed with diarization.
+	SpeakerID string `json:"speakerId,omitempty"`
+
+	// Translations are the translations of the phrase, by target language.
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// Recognition is the JSON body of the responses of /recognize and /translate.
+type Recognition struct {
+	// Text is the text of all the phrases.
+	Text string `json:"text"`
+
+	// Translations are the translations of all the phrases, by target language.
+	Translations map[string]string `json:"translations,omitempty"`
+
+	// Phrases are the recognized phrases, empty if no speech was recognized.
+	Phrases []Phrase `json:"phrases"`
+}
+
+func newRecognition() *Recognition {
+	return &Recognition{Phrases: []Phrase{}}
+}
+
+func (recognition *Recognition) add(phrase Phrase) {
+	recognition.Phrases = append(recognition.Phrases, phrase)
+	recognition.Text = joinText(recognition.Text, phrase.Text)
+	for language, translation := range phrase.Translations {
+		if recognition.Translations == nil {
+			recognition.Translations = make(map[string]string)
+		}
+		recognition.Translations[language] = joinText(recognition.Translations[language], translation)
+	}
+}
+
+func joinText(text string, phrase string) string {
+	if text == "" {
+		return phrase
+	}
+	return text + " " + phrase
+}
+
+// recognitionRequest is a request of /recognize or /translate.
+type recognitionRequest struct {
+	format   audioFormat
+	audio    io.Reader
+	language string
+	targets  []string
+}
+
+// backend runs the requests with the SDK.
+type backend interface {
+	recognize(ctx context.Context, request *recognitionRequest) (*Recognition, error)
+	synthesize(ctx context.Context, text string, ssml bool) ([]byte, error)
+	// stream starts the recognition of a stream, whose phrases are sent as events.
+	stream(ctx context.Context, control *StreamControl, format audioFormat, send func(*StreamEvent)) (streamSession, error)
+	Close()
+}
+
+// Server is an http.Handler serving recognitions, translations, syntheses and streams.
+type Server struct {
+	backend  backend
+	options  Options
+	mimeType string
+	sessions chan struct{}
+	limitKey string
+	mux      *http.ServeMux
+}
+
+// New creates a server with the service, the language and the synthesis voice and output format of the settings.
+// The caller must close it.
+func New(settings *speech.ConfigSettings, options Options) (*Server, error) {
+	options = options.withDefaults()
+	format := common.Riff16Khz16BitMonoPcm
+	if settings.SynthesisOutputFormat != "" {
+		var err error
+		if format, err = common.ParseSpeechSynthesisOutputFormat(settings.SynthesisOutputFormat); err != nil {
+			return nil, err
+		}
+	}
+	backend, err := newSpeechBackend(settings, options)
+	if err != nil {
+		return nil, err
+	}
+	mimeType := format.Describe().MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	server := newServer(backend, mimeType, options)
+	server.limitKey = limit.KeyOfSettings(settings)
+	return server, nil
+}
+
+func newServer(backend backend, mimeType string, options Options) *Server {
+	options = options.withDefaults()
+	server := &Server{
+		backend:  backend,
+		options:  options,
+		mimeType: mimeType,
+		sessions: make(chan struct{}, options.MaxSessions),
+		mux:      http.NewServeMux(),
+	}
+	server.mux.HandleFunc("/recognize", server.handleRecognition(false))
+	server.mux.HandleFunc("/translate", server.handleRecognition(true))
+	server.mux.HandleFunc("/synthesize", server.handleSynthesis)
+	server.mux.HandleFunc("/stream", server.handleStream)
+	return server
+}
+
+// ServeHTTP serves a request.
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.ServeHTTP(w, r)
+}
+
+// Close releases the prepared recognizers and the idle synthesizers.
+func (server *Server) Close() {
+	server.backend.Close()
+}
+
+// start checks the method of a request, limits the size of its body and waits for a session. The returned function
+// ends the session.
+func (server *Server) start(w http.ResponseWriter, r *http.Request) (context.Context, func(), error) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		return nil, nil, newError(http.StatusMethodNotAllowed, "MethodNotAllowed", "%s is not allowed, use POST", r.Method)
+	}
+	r.Body = &limitedBody{ReadCloser: r.Body, remaining: server.options.MaxBodySize}
+	ctx, cancel := context.WithTimeout(r.Context(), server.options.Timeout)
+	select {
+	case server.sessions <- struct{}{}:
+	case <-ctx.Done():
+		cancel()
+		return nil, nil, ctx.Err()
+	}
+	return ctx, func() {
+		<-server.sessions
+		cancel()
+	}, nil
+}
+
+// acquireLimit waits for the limiter of the options, if any, to allow a session of the request. The returned function
+// ends the session, given its error.
+func (server *Server) acquireLimit(ctx context.Context, request limit.Request) (func(err error), error) {
+	if server.options.Limiter == nil {
+		return func(error) {}, nil
+	}
+	permit, err := server.options.Limiter.Acquire(ctx, server.limitKey, request)
+	if err != nil {
+		return nil, err
+	}
+	return permit.Release, nil
+}
+
+func (server *Server) handleRecognition(translate bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		request := &recognitionRequest{language: query.Get("language")}
+		if translate {
+			if from := query.Get("from"); from != "" {
+				request.language = from
+			}
+			for _, value := range query["to"] {
+				for _, target := range strings.Split(value, ",") {
+					if target = strings.TrimSpace(target); target != "" {
+						request.targets = append(request.targets, target)
+					}
+				}
+			}
+			if len(request.targets) == 0 && r.Method == http.MethodPost {
+				writeError(w, newError(http.StatusBadRequest, "BadRequest", "the to query parameter is required"))
+				return
+			}
+		}
+		ctx, end, err := server.start(w, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		defer end()
+		if request.format, request.audio, err = readAudio(r); err != nil {
+			writeError(w, err)
+			return
+		}
+		release, err := server.acquireLimit(ctx, limit.Request{})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		recognition, err := server.backend.recognize(ctx, request)
+		release(err)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, recognition)
+	}
+}
+
+var ssmlMediaTypes = map[string]bool{
+	"application/ssml+xml": true,
+	"application/xml":      true,
+	"text/xml":             true,
+}
+
+func (server *Server) handleSynthesis(w http.ResponseWriter, r *http.Request) {
+	ctx, end, err := server.start(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer end()
+	var ssml bool
+	switch mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); {
+	case ssmlMediaTypes[mediaType]:
+		ssml = true
+	case mediaType == "text/plain":
+	case mediaType == "":
+		// Without media type, SSML is recognized by its root element.
+	default:
+		writeError(w, newError(http.StatusUnsupportedMediaType, "UnsupportedMediaType",
+			"unsupported text type %q, expected application/ssml+xml or text/plain", mediaType))
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, toBodyError(err))
+		return
+	}
+	text := string(bytes.TrimSpace(body))
+	if text == "" {
+		writeError(w, newError(http.StatusBadRequest, "BadRequest", "the text to synthesize is empty"))
+		return
+	}
+	if r.Header.Get("Content-Type") == "" {
+		ssml = strings.HasPrefix(text, "<speak") || strings.HasPrefix(text, "<?xml")
+	}
+	release, err := server.acquireLimit(ctx, limit.SynthesisRequest(text, ssml))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	data, err := server.backend.synthesize(ctx, text, ssml)
+	release(err)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", server.mimeType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// limitedBody is a request body returning a 413 error once more than remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (body *limitedBody) Read(p []byte) (int, error) {
+	if body.remaining < 0 {
+		return 0, newError(http.StatusRequestEntityTooLarge, "RequestEntityTooLarge", "the request body is too large")
+	}
+	if int64(len(p)) > body.remaining+1 {
+		p = p[:body.remaining+1]
+	}
+	n, err := body.ReadCloser.Read(p)
+	body.remaining -= int64(n)
+	if body.remaining < 0 {
+		n += int(body.remaining)
+		return n, newError(http.StatusRequestEntityTooLarge, "RequestEntityTooLarge", "the request body is too large")
+	}
+	return n, err
+}
+
+// toBodyError converts an error reading a request body, other than a 413 error, into a 400 error.
+func toBodyError(err error) error {
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+	return newError(http.StatusBadRequest, "BadRequest", "reading the request body: %v", err)
+}
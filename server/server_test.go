@@ -0,0 +1,259 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/limit"
+)
+
+type fakeBackend struct {
+	mu       sync.Mutex
+	requests []*recognitionRequest
+	audio    [][]byte
+	err      error
+	ssml     bool
+	streams  []*fakeStream
+}
+
+func (backend *fakeBackend) recognize(ctx context.Context, request *recognitionRequest) (*Recognition, error) {
+	data, err := ioutil.ReadAll(request.audio)
+	if err != nil {
+		return nil, toBodyError(err)
+	}
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	backend.requests = append(backend.requests, request)
+	backend.audio = append(backend.audio, data)
+	if backend.err != nil {
+		return nil, backend.err
+	}
+	recognition := newRecognition()
+	var translations map[string]string
+	if len(request.targets) > 0 {
+		translations = map[string]string{request.targets[0]: "Hallo."}
+	}
+	recognition.add(Phrase{Text: "Hello.", OffsetMs: 1000, DurationMs: 500, Translations: translations})
+	return recognition, nil
+}
+
+func (backend *fakeBackend) synthesize(ctx context.Context, text string, ssml bool) ([]byte, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	backend.ssml = ssml
+	if backend.err != nil {
+		return nil, backend.err
+	}
+	return []byte("audio:" + text), nil
+}
+
+func (backend *fakeBackend) Close() {}
+
+func wavHeader(rate uint32, bits uint16, channels uint16, tag uint16, dataSize uint32) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(36+dataSize))
+	b.WriteString("WAVEfmt ")
+	for _, value := range []interface{}{uint32(16), tag, channels, rate, rate * uint32(bits/8*channels), bits / 8 * channels, bits} {
+		binary.Write(&b, binary.LittleEndian, value)
+	}
+	b.WriteString("LIST")
+	binary.Write(&b, binary.LittleEndian, uint32(3))
+	b.WriteString("abc\x00")
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, dataSize)
+	return b.Bytes()
+}
+
+func TestRecognize(t *testing.T) {
+	backend := new(fakeBackend)
+	server := newServer(backend, "audio/wav", Options{MaxBodySize: 100})
+	samples := []byte("0123456789")
+
+	wav := append(wavHeader(8000, 16, 1, 1, uint32(len(samples))), samples...)
+	wav = append(wav, "trailer"...)
+	tests := []struct {
+		path        string
+		contentType string
+		body        []byte
+		status      int
+		format      audioFormat
+	}{
+		{"/recognize?language=de-DE", "audio/wav", wav, http.StatusOK, audioFormat{SampleRate: 8000, BitsPerSample: 16, Channels: 1, Encoding: audio.WavePCM}},
+		{"/recognize", "", samples, http.StatusOK, defaultFormat},
+		{"/recognize", "audio/pcm; rate=44100; channels=2", samples, http.StatusOK, audioFormat{SampleRate: 44100, BitsPerSample: 16, Channels: 2, Encoding: audio.WavePCM}},
+		{"/translate?to=de,fr&to=ja&from=en-US", "audio/pcm", samples, http.StatusOK, defaultFormat},
+		{"/translate", "audio/pcm", samples, http.StatusBadRequest, audioFormat{}},
+		{"/recognize", "audio/pcm;rate=fast", samples, http.StatusBadRequest, audioFormat{}},
+		{"/recognize", "audio/mpeg", samples, http.StatusUnsupportedMediaType, audioFormat{}},
+		{"/recognize", "audio/wav", append(wavHeader(8000, 32, 1, 3, 0), samples...), http.StatusBadRequest, audioFormat{}},
+		{"/recognize", "audio/pcm", bytes.Repeat(samples, 11), http.StatusRequestEntityTooLarge, audioFormat{}},
+	}
+	for _, test := range tests {
+		backend.requests, backend.audio = nil, nil
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, test.path, bytes.NewReader(test.body))
+		if test.contentType != "" {
+			request.Header.Set("Content-Type", test.contentType)
+		}
+		server.ServeHTTP(recorder, request)
+		if recorder.Code != test.status {
+			t.Errorf("%s %s: status = %d, want %d: %s", test.path, test.contentType, recorder.Code, test.status, recorder.Body)
+			continue
+		}
+		if test.status != http.StatusOK {
+			var body struct{ Error Error }
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil || body.Error.Code == "" {
+				t.Errorf("%s %s: error body = %s", test.path, test.contentType, recorder.Body)
+			}
+			continue
+		}
+		if got := backend.requests[0].format; got != test.format {
+			t.Errorf("%s %s: format = %+v, want %+v", test.path, test.contentType, got, test.format)
+		}
+		if got := backend.audio[0]; !bytes.Equal(got, samples) {
+			t.Errorf("%s %s: audio = %q, want %q", test.path, test.contentType, got, samples)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/translate?to=de,fr&to=ja&from=en-US", bytes.NewReader(samples))
+	server.ServeHTTP(recorder, request)
+	var recognition Recognition
+	if err := json.Unmarshal(recorder.Body.Bytes(), &recognition); err != nil {
+		t.Fatal(err)
+	}
+	want := Recognition{
+		Text:         "Hello.",
+		Translations: map[string]string{"de": "Hallo."},
+		Phrases:      []Phrase{{Text: "Hello.", OffsetMs: 1000, DurationMs: 500, Translations: map[string]string{"de": "Hallo."}}},
+	}
+	if !reflect.DeepEqual(recognition, want) {
+		t.Errorf("recognition = %+v, want %+v", recognition, want)
+	}
+	if request := backend.requests[0]; request.language != "en-US" || !reflect.DeepEqual(request.targets, []string{"de", "fr", "ja"}) {
+		t.Errorf("request = %+v", request)
+	}
+
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/recognize", nil))
+	if recorder.Code != http.StatusMethodNotAllowed || recorder.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("GET status = %d, Allow = %q", recorder.Code, recorder.Header().Get("Allow"))
+	}
+}
+
+func TestCancellationErrors(t *testing.T) {
+	tests := []struct {
+		err       error
+		status    int
+		code      string
+		retryable bool
+	}{
+		{&common.CancellationError{Reason: common.Error, ErrorCode: common.TooManyRequests, SessionID: "s1"}, http.StatusTooManyRequests, "TooManyRequests", true},
+		{&common.CancellationError{Reason: common.Error, ErrorCode: common.BadRequest}, http.StatusBadRequest, "BadRequest", false},
+		{&common.CancellationError{Reason: common.Error, ErrorCode: common.AuthenticationFailure}, http.StatusBadGateway, "AuthenticationFailure", false},
+		{&common.CancellationError{Reason: common.Error, ErrorCode: common.ServiceTimeout}, http.StatusGatewayTimeout, "ServiceTimeout", true},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, "Timeout", true},
+	}
+	backend := new(fakeBackend)
+	server := newServer(backend, "audio/mpeg", Options{})
+	for _, test := range tests {
+		backend.err = test.err
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/synthesize", strings.NewReader("Hello."))
+		server.ServeHTTP(recorder, request)
+		var body struct{ Error Error }
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if recorder.Code != test.status || body.Error.Code != test.code || body.Error.Retryable != test.retryable {
+			t.Errorf("%v: status = %d, error = %+v, want %d %s", test.err, recorder.Code, body.Error, test.status, test.code)
+		}
+		if retryAfter := recorder.Header().Get("Retry-After"); (retryAfter != "") != test.retryable {
+			t.Errorf("%v: Retry-After = %q", test.err, retryAfter)
+		}
+	}
+	if e := toError(tests[0].err); e.SessionID != "s1" || e.Reason != "Error" {
+		t.Errorf("toError() = %+v, want the session and the reason", e)
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	backend := new(fakeBackend)
+	server := newServer(backend, "audio/mpeg", Options{})
+	tests := []struct {
+		contentType string
+		body        string
+		ssml        bool
+		status      int
+	}{
+		{"application/ssml+xml", "<speak>Hi</speak>", true, http.StatusOK},
+		{"", "  <speak>Hi</speak>", true, http.StatusOK},
+		{"text/plain; charset=utf-8", "<speak>", false, http.StatusOK},
+		{"", "Hi", false, http.StatusOK},
+		{"", " ", false, http.StatusBadRequest},
+		{"image/png", "Hi", false, http.StatusUnsupportedMediaType},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/synthesize", strings.NewReader(test.body))
+		if test.contentType != "" {
+			request.Header.Set("Content-Type", test.contentType)
+		}
+		server.ServeHTTP(recorder, request)
+		if recorder.Code != test.status {
+			t.Errorf("%q %q: status = %d, want %d", test.contentType, test.body, recorder.Code, test.status)
+			continue
+		}
+		if test.status != http.StatusOK {
+			continue
+		}
+		if backend.ssml != test.ssml {
+			t.Errorf("%q %q: ssml = %v, want %v", test.contentType, test.body, backend.ssml, test.ssml)
+		}
+		if contentType := recorder.Header().Get("Content-Type"); contentType != "audio/mpeg" {
+			t.Errorf("Content-Type = %q, want audio/mpeg", contentType)
+		}
+		if got, want := recorder.Body.String(), "audio:"+strings.TrimSpace(test.body); got != want {
+			t.Errorf("body = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	backend := &fakeBackend{err: &common.CancellationError{Reason: common.Error, ErrorCode: common.TooManyRequests}}
+	limiter := limit.New(limit.Options{CharacterRate: 1000, MinBackoff: time.Hour})
+	server := newServer(backend, "audio/mpeg", Options{Timeout: 20 * time.Millisecond, Limiter: limiter})
+	synthesize := func() int {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/synthesize", strings.NewReader("<speak>Hello.</speak>")))
+		return recorder.Code
+	}
+	if status := synthesize(); status != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+	// The throttled synthesis pauses the following sessions, which time out.
+	backend.err = nil
+	if status := synthesize(); status != http.StatusGatewayTimeout {
+		t.Errorf("status during the backoff = %d, want %d", status, http.StatusGatewayTimeout)
+	}
+	stats := limiter.Stats("")
+	if stats.Acquired != 1 || stats.Throttled != 1 || stats.Active != 0 || stats.Waiting != 0 {
+		t.Errorf("limiter stats = %+v", stats)
+	}
+}
@@ -0,0 +1,476 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/pool"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
+)
+
+// speechBackend runs the requests with recognizers fed by push streams and pooled synthesizers.
+type speechBackend struct {
+	settings     speech.ConfigSettings
+	config       *speech.SpeechConfig
+	recognizers  *pool.Pool
+	synthesizers *pool.SynthesizerPool
+}
+
+func newSpeechBackend(settings *speech.ConfigSettings, options Options) (*speechBackend, error) {
+	config, err := settings.SpeechConfig()
+	if err != nil {
+		return nil, err
+	}
+	backend := &speechBackend{settings: *settings, config: config}
+	// A recognizer recognizes a single stream, so that prepared recognizers are never reused, while synthesizers are.
+	backend.recognizers = pool.New(func(ctx context.Context) (pool.Resource, error) {
+		session, err := backend.newRecognitionSession(defaultFormat, recognitionOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return session, nil
+	}, pool.Options{Name: "server_recognizer", Min: options.PreparedRecognizers, Max: options.MaxSessions})
+	backend.synthesizers = pool.NewSynthesizerPool(config, pool.SpeechOptions{
+		Options: pool.Options{Name: "server_synthesizer", Max: options.MaxSessions},
+	})
+	return backend, nil
+}
+
+func (backend *speechBackend) Close() {
+	backend.recognizers.Close()
+	backend.synthesizers.Close()
+	backend.config.Close()
+}
+
+// recognitionSession is a recognizer, a translation recognizer or a conversation transcriber, and the push stream of
+// its audio.
+type recognitionSession struct {
+	format      *audio.AudioStreamFormat
+	stream      *audio.PushAudioInputStream
+	audioConfig *audio.AudioConfig
+	recognizer  *speech.SpeechRecognizer
+	translator  *speech.TranslationRecognizer
+	transcriber *speech.ConversationTranscriber
+
+	// pooled is set for the sessions of backend.recognizers.
+	pooled bool
+}
+
+// recognitionOptions select the recognizer of a session.
+type recognitionOptions struct {
+	language    string
+	targets     []string
+	diarization bool
+}
+
+func (backend *speechBackend) newRecognitionSession(format audioFormat, options recognitionOptions) (*recognitionSession, error) {
+	session := new(recognitionSession)
+	var err error
+	switch {
+	case format.Container != 0:
+		session.format, err = audio.GetCompressedFormat(format.Container)
+	case format.Encoding == audio.WavePCM:
+		session.format, err = audio.GetWaveFormatPCM(format.SampleRate, format.BitsPerSample, format.Channels)
+	default:
+		session.format, err = audio.GetWaveFormat(format.SampleRate, format.BitsPerSample, format.Channels, format.Encoding)
+	}
+	if err == nil {
+		session.stream, err = audio.CreatePushAudioInputStreamFromFormat(session.format)
+	}
+	if err == nil {
+		session.audioConfig, err = audio.NewAudioConfigFromStreamInput(session.stream)
+	}
+	if err == nil {
+		switch {
+		case len(options.targets) > 0:
+			session.translator, err = backend.newTranslationRecognizer(session.audioConfig, options.language, options.targets)
+		case options.diarization:
+			session.transcriber, err = backend.newConversationTranscriber(session.audioConfig, options.language)
+		case options.language != "":
+			session.recognizer, err = speech.NewSpeechRecognizerFromSourceLanguage(backend.config, options.language, session.audioConfig)
+		default:
+			session.recognizer, err = speech.NewSpeechRecognizerFromConfig(backend.config, session.audioConfig)
+		}
+	}
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	return session, nil
+}
+
+func (backend *speechBackend) newTranslationRecognizer(audioConfig *audio.AudioConfig, language string, targets []string) (*speech.TranslationRecognizer, error) {
+	settings := backend.settings
+	settings.TargetLanguages = targets
+	if language != "" {
+		settings.Language = language
+	}
+	config, err := settings.TranslationConfig()
+	if err != nil {
+		// The settings of the server are valid, so that the languages of the request are not.
+		var configErr *speech.ConfigError
+		if errors.As(err, &configErr) {
+			return nil, newError(http.StatusBadRequest, "BadRequest", "%v", err)
+		}
+		return nil, err
+	}
+	defer config.Close()
+	return speech.NewTranslationRecognizerFromConfig(config, audioConfig)
+}
+
+func (backend *speechBackend) newConversationTranscriber(audioConfig *audio.AudioConfig, language string) (*speech.ConversationTranscriber, error) {
+	if language == "" {
+		return speech.NewConversationTranscriberFromConfig(backend.config, audioConfig)
+	}
+	languageConfig, err := speech.NewSourceLanguageConfigFromLanguage(language)
+	if err != nil {
+		return nil, err
+	}
+	defer languageConfig.Close()
+	return speech.NewConversationTranscriberFromSourceLanguageConfig(backend.config, languageConfig, audioConfig)
+}
+
+func (session *recognitionSession) Close() {
+	if session.recognizer != nil {
+		session.recognizer.Close()
+	}
+	if session.translator != nil {
+		session.translator.Close()
+	}
+	if session.transcriber != nil {
+		session.transcriber.Close()
+	}
+	if session.audioConfig != nil {
+		session.audioConfig.Close()
+	}
+	if session.stream != nil {
+		session.stream.Close()
+	}
+	if session.format != nil {
+		session.format.Close()
+	}
+}
+
+// sessionHandlers are the handlers of the events of a session, whichever its recognizer.
+type sessionHandlers struct {
+	// recognizing, if set, receives the intermediate results.
+	recognizing func(phrase *Phrase)
+	recognized  func(phrase *Phrase)
+	// canceled receives the cancellations with an error.
+	canceled func(err error)
+	stopped  func()
+}
+
+func newPhrase(result *speech.SpeechRecognitionResult) *Phrase {
+	return &Phrase{
+		Text:       result.Text,
+		OffsetMs:   float64(result.Offset) / float64(time.Millisecond),
+		DurationMs: float64(result.Duration) / float64(time.Millisecond),
+	}
+}
+
+// connect connects the handlers to the events of the recognizer of the session. Results without text are ignored.
+func (session *recognitionSession) connect(handlers sessionHandlers) {
+	stopped := func(event speech.SessionEventArgs) {
+		defer event.Close()
+		handlers.stopped()
+	}
+	switch {
+	case session.translator != nil:
+		translated := func(handler func(*Phrase)) speech.TranslationRecognitionEventHandler {
+			return func(event speech.TranslationRecognitionEventArgs) {
+				defer event.Close()
+				if event.Result.Text != "" {
+					phrase := newPhrase(&event.Result.SpeechRecognitionResult)
+					phrase.Translations = event.Result.GetTranslations()
+					handler(phrase)
+				}
+			}
+		}
+		if handlers.recognizing != nil {
+			session.translator.Recognizing(translated(handlers.recognizing))
+		}
+		session.translator.Recognized(translated(handlers.recognized))
+		session.translator.Canceled(func(event speech.TranslationRecognitionCanceledEventArgs) {
+			defer event.Close()
+			if event.Reason == common.Error {
+				handlers.canceled(event.Err())
+			}
+		})
+		session.translator.SessionStopped(stopped)
+	case session.transcriber != nil:
+		transcribed := func(handler func(*Phrase)) speech.ConversationTranscriptionEventHandler {
+			return func(event speech.ConversationTranscriptionEventArgs) {
+				defer event.Close()
+				if event.Result.Text != "" {
+					phrase := newPhrase(&event.Result.SpeechRecognitionResult)
+					phrase.SpeakerID = event.Result.SpeakerID
+					handler(phrase)
+				}
+			}
+		}
+		if handlers.recognizing != nil {
+			session.transcriber.Transcribing(transcribed(handlers.recognizing))
+		}
+		session.transcriber.Transcribed(transcribed(handlers.recognized))
+		session.transcriber.Canceled(func(event speech.ConversationTranscriptionCanceledEventArgs) {
+			defer event.Close()
+			if event.Reason == common.Error {
+				handlers.canceled(event.Err())
+			}
+		})
+		session.transcriber.SessionStopped(stopped)
+	default:
+		recognized := func(handler func(*Phrase)) speech.SpeechRecognitionEventHandler {
+			return func(event speech.SpeechRecognitionEventArgs) {
+				defer event.Close()
+				if event.Result.Text != "" {
+					handler(newPhrase(&event.Result))
+				}
+			}
+		}
+		if handlers.recognizing != nil {
+			session.recognizer.Recognizing(recognized(handlers.recognizing))
+		}
+		session.recognizer.Recognized(recognized(handlers.recognized))
+		session.recognizer.Canceled(func(event speech.SpeechRecognitionCanceledEventArgs) {
+			defer event.Close()
+			if event.Reason == common.Error {
+				handlers.canceled(event.Err())
+			}
+		})
+		session.recognizer.SessionStopped(stopped)
+	}
+}
+
+// start starts the continuous recognition or transcription of the session.
+func (session *recognitionSession) start(ctx context.Context) chan error {
+	switch {
+	case session.translator != nil:
+		return session.translator.StartContinuousRecognitionWithContextAsync(ctx)
+	case session.transcriber != nil:
+		return session.transcriber.StartTranscribingWithContextAsync(ctx)
+	}
+	return session.recognizer.StartContinuousRecognitionWithContextAsync(ctx)
+}
+
+// stop stops the continuous recognition or transcription of the session.
+func (session *recognitionSession) stop() chan error {
+	switch {
+	case session.translator != nil:
+		return session.translator.StopContinuousRecognitionAsync()
+	case session.transcriber != nil:
+		return session.transcriber.StopTranscribingAsync()
+	}
+	return session.recognizer.StopContinuousRecognitionAsync()
+}
+
+// session returns a prepared session for the default format and options, or a new one. It is released with
+// releaseSession.
+func (backend *speechBackend) session(ctx context.Context, format audioFormat, options recognitionOptions) (*recognitionSession, error) {
+	if format == defaultFormat && options.language == "" && len(options.targets) == 0 && !options.diarization {
+		r, err := backend.recognizers.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		session := r.(*recognitionSession)
+		session.pooled = true
+		return session, nil
+	}
+	return backend.newRecognitionSession(format, options)
+}
+
+// releaseSession closes a session, which is not reused.
+func (backend *speechBackend) releaseSession(session *recognitionSession) {
+	if session.pooled {
+		backend.recognizers.Discard(session)
+	} else {
+		session.Close()
+	}
+}
+
+// pump writes the audio into the stream until its end or until the context is done, then closes the stream, which
+// ends the recognition.
+func (session *recognitionSession) pump(ctx context.Context, r io.Reader) error {
+	defer session.stream.CloseStream()
+	buffer := make([]byte, 32*1024)
+	for ctx.Err() == nil {
+		n, err := r.Read(buffer)
+		if n > 0 {
+			if writeErr := session.stream.Write(buffer[:n]); writeErr != nil {
+				return writeErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return toBodyError(err)
+		}
+	}
+	return ctx.Err()
+}
+
+// recognize runs a continuous recognition of the audio of the request until its end, while it is received.
+func (backend *speechBackend) recognize(ctx context.Context, request *recognitionRequest) (*Recognition, error) {
+	session, err := backend.session(ctx, request.format, recognitionOptions{language: request.language, targets: request.targets})
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	recognition := newRecognition()
+	done := make(chan error, 1)
+	finish := func(err error) {
+		select {
+		case done <- err:
+		default:
+		}
+	}
+	session.connect(sessionHandlers{
+		recognized: func(phrase *Phrase) {
+			mu.Lock()
+			defer mu.Unlock()
+			recognition.add(*phrase)
+		},
+		canceled: finish,
+		stopped:  func() { finish(nil) },
+	})
+	if err := <-session.start(ctx); err != nil {
+		backend.releaseSession(session)
+		return nil, err
+	}
+
+	// The audio is pumped while it is received. Reading the body of a slow client may block past the end of the
+	// request, so that the session is released by the pump once it returns.
+	pumpCtx, stopPump := context.WithCancel(ctx)
+	defer stopPump()
+	pumped := make(chan error, 1)
+	go func() {
+		err := session.pump(pumpCtx, request.audio)
+		pumped <- err
+	}()
+	for finished := false; !finished && err == nil; {
+		select {
+		case err = <-done:
+			finished = true
+		case err = <-pumped:
+			// The end of the audio ends the recognition, once recognized.
+			pumped = nil
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	<-session.stop()
+	stopPump()
+	if pumped == nil {
+		backend.releaseSession(session)
+	} else {
+		go func() {
+			<-pumped
+			backend.releaseSession(session)
+		}()
+	}
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return recognition, nil
+}
+
+// speechStream is the continuous recognition of a stream.
+type speechStream struct {
+	backend    *speechBackend
+	session    *recognitionSession
+	done       chan error
+	closeAudio sync.Once
+}
+
+func (backend *speechBackend) stream(ctx context.Context, control *StreamControl, format audioFormat, send func(*StreamEvent)) (streamSession, error) {
+	options := recognitionOptions{
+		language:    control.Language,
+		targets:     control.TargetLanguages,
+		diarization: control.Diarization,
+	}
+	session, err := backend.session(ctx, format, options)
+	if err != nil {
+		return nil, err
+	}
+	stream := &speechStream{backend: backend, session: session, done: make(chan error, 1)}
+	finish := func(err error) {
+		select {
+		case stream.done <- err:
+		default:
+		}
+	}
+	session.connect(sessionHandlers{
+		recognizing: func(phrase *Phrase) { send(&StreamEvent{Type: "recognizing", Phrase: phrase}) },
+		recognized:  func(phrase *Phrase) { send(&StreamEvent{Type: "recognized", Phrase: phrase}) },
+		canceled:    finish,
+		stopped:     func() { finish(nil) },
+	})
+	if err := <-session.start(ctx); err != nil {
+		backend.releaseSession(session)
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (stream *speechStream) Write(data []byte) error {
+	return stream.session.stream.Write(data)
+}
+
+func (stream *speechStream) CloseAudio() {
+	stream.closeAudio.Do(stream.session.stream.CloseStream)
+}
+
+func (stream *speechStream) Done() <-chan error {
+	return stream.done
+}
+
+func (stream *speechStream) Close() {
+	<-stream.session.stop()
+	stream.backend.releaseSession(stream.session)
+}
+
+// synthesize synthesizes the text or SSML with a pooled synthesizer. Synthesizers canceled with an error are closed
+// rather than reused.
+func (backend *speechBackend) synthesize(ctx context.Context, text string, ssml bool) ([]byte, error) {
+	synthesizer, err := backend.synthesizers.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var outcomes chan speech.SpeechSynthesisOutcome
+	if ssml {
+		outcomes = synthesizer.SpeakSsmlWithContextAsync(ctx, text)
+	} else {
+		outcomes = synthesizer.SpeakTextWithContextAsync(ctx, text)
+	}
+	select {
+	case outcome := <-outcomes:
+		defer outcome.Close()
+		backend.synthesizers.Release(synthesizer, outcome.Error)
+		if outcome.Error != nil {
+			return nil, outcome.Error
+		}
+		return outcome.Result.AudioData, nil
+	case <-ctx.Done():
+		<-synthesizer.StopSpeakingAsync()
+		go func() {
+			outcome := <-outcomes
+			outcome.Close()
+			backend.synthesizers.Discard(synthesizer)
+		}()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/limit"
+)
+
+// StreamControl is a JSON control message sent to /stream. The first message of a stream is a start message; a stop
+// message ends the audio, whose remaining phrases are then sent before the end event.
+type StreamControl struct {
+	// Type is "start" or "stop".
+	Type string `json:"type"`
+
+	// Language is the recognition language, the language of the server if empty.
+	Language string `json:"language,omitempty"`
+
+	// TargetLanguages, if any, translate the recognized phrases.
+	TargetLanguages []string `json:"targetLanguages,omitempty"`
+
+	// Diarization transcribes the audio with the speaker of each phrase. It cannot be combined with translation.
+	Diarization bool `json:"diarization,omitempty"`
+
+	// Format is the format of the binary frames: "pcm" for 16-bit little-endian PCM, the default, "float32" for
+	// 32-bit float little-endian PCM, as captured by the Web Audio API, or "opus" for Ogg Opus.
+	Format string `json:"format,omitempty"`
+
+	// SampleRate and Channels are those of PCM frames, 16000 and 1 by default. Multichannel audio is mixed to mono.
+	SampleRate uint32 `json:"sampleRate,omitempty"`
+	Channels   uint8  `json:"channels,omitempty"`
+}
+
+// StreamEvent is a JSON message sent by /stream.
+type StreamEvent struct {
+	// Type is "recognizing" for an intermediate phrase, "recognized" for a final one, "canceled" when the service
+	// cancels the recognition, "error" when it cannot start, or "end" once the audio is entirely recognized after a
+	// stop message. The connection is closed after a canceled, error or end event.
+	Type string `json:"type"`
+
+	// Phrase is the phrase of recognizing and recognized events.
+	*Phrase
+
+	// Error is the error of canceled and error events.
+	Error *Error `json:"error,omitempty"`
+}
+
+// streamSampleRates are the sample rates accepted for PCM frames.
+var streamSampleRates = map[uint32]bool{8000: true, 16000: true, 22050: true, 24000: true, 32000: true, 44100: true,
+	48000: true}
+
+// streamFormat returns the format of the audio given to the recognizer of a stream, and the converter of its frames.
+func streamFormat(control *StreamControl) (audioFormat, *pcmConverter, error) {
+	if control.Diarization && len(control.TargetLanguages) > 0 {
+		return audioFormat{}, nil, newError(http.StatusBadRequest, "BadRequest",
+			"diarization cannot be combined with translation")
+	}
+	converter := &pcmConverter{channels: int(control.Channels)}
+	switch control.Format {
+	case "opus":
+		return audioFormat{Container: audio.OGGOPUS}, &pcmConverter{channels: 1}, nil
+	case "float32":
+		converter.float = true
+	case "", "pcm":
+	default:
+		return audioFormat{}, nil, newError(http.StatusBadRequest, "BadRequest",
+			"unsupported format %q, expected pcm, float32 or opus", control.Format)
+	}
+	format := defaultFormat
+	if control.SampleRate != 0 {
+		if !streamSampleRates[control.SampleRate] {
+			return audioFormat{}, nil, newError(http.StatusBadRequest, "BadRequest", "unsupported sample rate %d",
+				control.SampleRate)
+		}
+		format.SampleRate = control.SampleRate
+	}
+	if converter.channels == 0 {
+		converter.channels = 1
+	}
+	return format, converter, nil
+}
+
+// pcmConverter converts PCM frames into 16-bit mono PCM. Samples split across frames are converted with the next one.
+type pcmConverter struct {
+	float    bool
+	channels int
+	pending  []byte
+}
+
+func (converter *pcmConverter) convert(frame []byte) []byte {
+	if !converter.float && converter.channels == 1 {
+		return frame
+	}
+	sampleSize := 2
+	if converter.float {
+		sampleSize = 4
+	}
+	blockSize := sampleSize * converter.channels
+	data := frame
+	if len(converter.pending) > 0 {
+		data = append(append([]byte(nil), converter.pending...), frame...)
+	}
+	n := len(data) / blockSize * blockSize
+	converter.pending = append(converter.pending[:0], data[n:]...)
+	converted := make([]byte, n/blockSize*2)
+	for i := 0; i < n/blockSize; i++ {
+		var sum float64
+		for channel := 0; channel < converter.channels; channel++ {
+			sample := data[i*blockSize+channel*sampleSize:]
+			if converter.float {
+				sum += float64(math.Float32frombits(binary.LittleEndian.Uint32(sample))) * 32767
+			} else {
+				sum += float64(int16(binary.LittleEndian.Uint16(sample)))
+			}
+		}
+		mixed := math.Round(sum / float64(converter.channels))
+		switch {
+		case mixed != mixed:
+			mixed = 0
+		case mixed > math.MaxInt16:
+			mixed = math.MaxInt16
+		case mixed < math.MinInt16:
+			mixed = math.MinInt16
+		}
+		binary.LittleEndian.PutUint16(converted[i*2:], uint16(int16(mixed)))
+	}
+	return converted
+}
+
+// streamSession is the recognition of the audio of a stream.
+type streamSession interface {
+	// Write writes audio in the format of the session.
+	Write(data []byte) error
+
+	// CloseAudio ends the audio, whose remaining phrases are then recognized.
+	CloseAudio()
+
+	// Done receives nil once the audio is entirely recognized, or the error canceling the recognition.
+	Done() <-chan error
+
+	// Close stops the recognition, if not done, and releases the session.
+	Close()
+}
+
+// closeTimeout is the time left to a client to answer the close frame of the server.
+const closeTimeout = 5 * time.Second
+
+// handleStream serves a WebSocket stream. Options.Timeout bounds the wait for its session, not its duration.
+func (server *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	if err := checkUpgrade(w, r, server.options.CheckOrigin); err != nil {
+		writeError(w, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), server.options.Timeout)
+	select {
+	case server.sessions <- struct{}{}:
+	case <-ctx.Done():
+		cancel()
+		writeError(w, ctx.Err())
+		return
+	}
+	defer func() { <-server.sessions }()
+	release, err := server.acquireLimit(ctx, limit.Request{})
+	cancel()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ws, err := upgrade(w, r)
+	if err != nil {
+		release(nil)
+		writeError(w, err)
+		return
+	}
+	defer ws.Close()
+	// The context of a hijacked connection is not canceled by its disconnection, which ends the reads instead.
+	release(server.serveStream(r.Context(), ws))
+}
+
+// serveStream serves a stream until its end or the disconnection of the client, and returns the error of its
+// session, if any.
+func (server *Server) serveStream(ctx context.Context, ws *wsConn) error {
+	control, err := readStart(ws)
+	if err != nil {
+		if _, closed := err.(*closeError); !closed {
+			abortStream(ws, "error", err)
+		}
+		return nil
+	}
+	format, converter, err := streamFormat(control)
+	if err != nil {
+		abortStream(ws, "error", err)
+		return nil
+	}
+	session, err := server.backend.stream(ctx, control, format, func(event *StreamEvent) {
+		ws.writeJSON(event)
+	})
+	if err != nil {
+		abortStream(ws, "error", err)
+		return err
+	}
+	defer session.Close()
+
+	received := make(chan error, 1)
+	go func() {
+		received <- receiveAudio(ws, session, converter)
+	}()
+	select {
+	case err := <-session.Done():
+		if err != nil {
+			abortStream(ws, "canceled", err)
+		} else {
+			ws.writeJSON(&StreamEvent{Type: "end"})
+			ws.writeClose(closeNormal, "")
+		}
+		// The client answers the close frame, which ends the reads.
+		ws.conn.SetReadDeadline(time.Now().Add(closeTimeout))
+		<-received
+		return err
+	case <-received:
+		// The client disconnected before the end of the recognition, which is stopped by closing the session.
+		return nil
+	}
+}
+
+// readStart reads the start message of a stream.
+func readStart(ws *wsConn) (*StreamControl, error) {
+	opcode, data, err := ws.readMessage()
+	if err != nil {
+		return nil, err
+	}
+	control := new(StreamControl)
+	if opcode != opText || json.Unmarshal(data, control) != nil || control.Type != "start" {
+		return nil, newError(http.StatusBadRequest, "BadRequest", "the first message must be a start message")
+	}
+	return control, nil
+}
+
+// receiveAudio writes the audio frames of a stream into its session until the connection is closed.
+func receiveAudio(ws *wsConn, session streamSession, converter *pcmConverter) error {
+	stopped := false
+	for {
+		opcode, data, err := ws.readMessage()
+		if err != nil {
+			return err
+		}
+		if opcode == opBinary {
+			if stopped {
+				return ws.fail(closePolicyViolation, "audio after the stop message")
+			}
+			if err := session.Write(converter.convert(data)); err != nil {
+				return ws.fail(closeInternalError, err.Error())
+			}
+			continue
+		}
+		var control StreamControl
+		if json.Unmarshal(data, &control) != nil || control.Type != "stop" {
+			return ws.fail(closePolicyViolation, "expected audio or a stop message")
+		}
+		if !stopped {
+			session.CloseAudio()
+			stopped = true
+		}
+	}
+}
+
+// abortStream sends an error as a canceled or error event, then closes the connection with a status depending on
+// whether the stream is at fault.
+func abortStream(ws *wsConn, eventType string, err error) {
+	e := toError(err)
+	ws.writeJSON(&StreamEvent{Type: eventType, Error: e})
+	code := closeInternalError
+	if e.Status < http.StatusInternalServerError && e.Status != http.StatusTooManyRequests {
+		code = closePolicyViolation
+	}
+	ws.writeClose(code, e.Code)
+}
@@ -0,0 +1,346 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+)
+
+type fakeStream struct {
+	control *StreamControl
+	format  audioFormat
+	send    func(*StreamEvent)
+	done    chan error
+	closed  chan struct{}
+
+	mu    sync.Mutex
+	audio []byte
+}
+
+func (backend *fakeBackend) stream(ctx context.Context, control *StreamControl, format audioFormat, send func(*StreamEvent)) (streamSession, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if backend.err != nil {
+		return nil, backend.err
+	}
+	stream := &fakeStream{
+		control: control,
+		format:  format,
+		send:    send,
+		done:    make(chan error, 1),
+		closed:  make(chan struct{}),
+	}
+	backend.streams = append(backend.streams, stream)
+	return stream, nil
+}
+
+func (stream *fakeStream) Write(data []byte) error {
+	stream.mu.Lock()
+	stream.audio = append(stream.audio, data...)
+	n := len(stream.audio)
+	stream.mu.Unlock()
+	stream.send(&StreamEvent{Type: "recognizing", Phrase: &Phrase{Text: fmt.Sprint(n)}})
+	return nil
+}
+
+func (stream *fakeStream) CloseAudio() {
+	stream.send(&StreamEvent{Type: "recognized", Phrase: &Phrase{Text: "Hello.", SpeakerID: "Guest-1"}})
+	stream.done <- nil
+}
+
+func (stream *fakeStream) Done() <-chan error {
+	return stream.done
+}
+
+func (stream *fakeStream) Close() {
+	close(stream.closed)
+}
+
+// testClient is the client side of a WebSocket connection.
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialStream(t *testing.T, server *httptest.Server, header http.Header) (*testClient, *http.Response) {
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	request, _ := http.NewRequest(http.MethodGet, server.URL+"/stream", nil)
+	request.Header.Set("Connection", "keep-alive, Upgrade")
+	request.Header.Set("Upgrade", "websocket")
+	request.Header.Set("Sec-WebSocket-Version", "13")
+	request.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	if err := request.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return &testClient{t: t, conn: conn, reader: reader}, response
+}
+
+func (client *testClient) writeFrame(header byte, payload []byte) {
+	frame := []byte{header}
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, 0x80|byte(n))
+	default:
+		frame = append(frame, 0x80|126, byte(n>>8), byte(n))
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := client.conn.Write(frame); err != nil {
+		client.t.Fatal(err)
+	}
+}
+
+func (client *testClient) writeJSON(v interface{}) {
+	data, _ := json.Marshal(v)
+	client.writeFrame(0x80|opText, data)
+}
+
+func (client *testClient) readFrame() (int, []byte) {
+	var header [2]byte
+	if _, err := client.reader.Read(header[:1]); err != nil {
+		client.t.Fatal(err)
+	}
+	if _, err := client.reader.Read(header[1:]); err != nil {
+		client.t.Fatal(err)
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var extended [2]byte
+		client.reader.Read(extended[:])
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload := make([]byte, length)
+	for read := 0; read < length; {
+		n, err := client.reader.Read(payload[read:])
+		if err != nil {
+			client.t.Fatal(err)
+		}
+		read += n
+	}
+	return int(header[0] & 0x0F), payload
+}
+
+func (client *testClient) readEvent() StreamEvent {
+	opcode, payload := client.readFrame()
+	var event StreamEvent
+	if opcode != opText || json.Unmarshal(payload, &event) != nil {
+		client.t.Fatalf("frame %d %q, want a JSON event", opcode, payload)
+	}
+	return event
+}
+
+func (client *testClient) readClose() int {
+	opcode, payload := client.readFrame()
+	if opcode != opClose || len(payload) < 2 {
+		client.t.Fatalf("frame %d %q, want a close frame", opcode, payload)
+	}
+	return int(binary.BigEndian.Uint16(payload))
+}
+
+func TestStream(t *testing.T) {
+	backend := new(fakeBackend)
+	server := httptest.NewServer(newServer(backend, "audio/wav", Options{}))
+	defer server.Close()
+	client, response := dialStream(t, server, nil)
+	defer client.conn.Close()
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want 101", response.StatusCode)
+	}
+	if accept := response.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Sec-WebSocket-Accept = %q", accept)
+	}
+
+	client.writeJSON(StreamControl{Type: "start", Language: "de-DE", Diarization: true, Format: "float32", SampleRate: 48000,
+		Channels: 2})
+	var frame bytes.Buffer
+	for _, sample := range []float32{0.5, 0.5, 1, -1, 2, 2} {
+		binary.Write(&frame, binary.LittleEndian, sample)
+	}
+	// The second sample of the last block is sent with the next frame.
+	client.writeFrame(0x80|opBinary, frame.Bytes()[:20])
+	if event := client.readEvent(); event.Type != "recognizing" || event.Text != "4" {
+		t.Errorf("event = %+v, want recognizing 4", event)
+	}
+	client.writeFrame(0x80|opPing, []byte("ping"))
+	if opcode, payload := client.readFrame(); opcode != opPong || string(payload) != "ping" {
+		t.Errorf("frame %d %q, want a pong", opcode, payload)
+	}
+	// A fragmented frame.
+	client.writeFrame(opBinary, frame.Bytes()[20:22])
+	client.writeFrame(0x80|opContinuation, frame.Bytes()[22:])
+	if event := client.readEvent(); event.Type != "recognizing" || event.Text != "6" {
+		t.Errorf("event = %+v, want recognizing 6", event)
+	}
+	client.writeJSON(StreamControl{Type: "stop"})
+	if event := client.readEvent(); event.Type != "recognized" || event.Text != "Hello." || event.SpeakerID != "Guest-1" {
+		t.Errorf("event = %+v, want recognized Hello.", event)
+	}
+	if event := client.readEvent(); event.Type != "end" || event.Phrase != nil || event.Error != nil {
+		t.Errorf("event = %+v, want end", event)
+	}
+	if code := client.readClose(); code != closeNormal {
+		t.Errorf("close = %d, want %d", code, closeNormal)
+	}
+	client.writeFrame(0x80|opClose, []byte{0x03, 0xE8})
+
+	backend.mu.Lock()
+	stream := backend.streams[0]
+	backend.mu.Unlock()
+	select {
+	case <-stream.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream is not closed")
+	}
+	if stream.control.Language != "de-DE" || !stream.control.Diarization {
+		t.Errorf("control = %+v", stream.control)
+	}
+	if want := (audioFormat{SampleRate: 48000, BitsPerSample: 16, Channels: 1, Encoding: audio.WavePCM}); stream.format != want {
+		t.Errorf("format = %+v, want %+v", stream.format, want)
+	}
+	want := []byte{0x00, 0x40, 0x00, 0x00, 0xFF, 0x7F}
+	if !bytes.Equal(stream.audio, want) {
+		t.Errorf("audio = %x, want %x", stream.audio, want)
+	}
+}
+
+func TestStreamDisconnect(t *testing.T) {
+	backend := new(fakeBackend)
+	server := httptest.NewServer(newServer(backend, "audio/wav", Options{MaxSessions: 1}))
+	defer server.Close()
+	for i := 0; i < 2; i++ {
+		client, _ := dialStream(t, server, nil)
+		client.writeJSON(StreamControl{Type: "start", Format: "opus"})
+		client.writeFrame(0x80|opBinary, []byte("OggS"))
+		client.readEvent()
+		client.conn.Close()
+
+		backend.mu.Lock()
+		stream := backend.streams[i]
+		backend.mu.Unlock()
+		select {
+		case <-stream.closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the stream is not closed after the disconnection")
+		}
+		if stream.format != (audioFormat{Container: audio.OGGOPUS}) || string(stream.audio) != "OggS" {
+			t.Errorf("format = %+v, audio = %q", stream.format, stream.audio)
+		}
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	backend := new(fakeBackend)
+	server := httptest.NewServer(newServer(backend, "audio/wav", Options{}))
+	defer server.Close()
+
+	tests := []struct {
+		header http.Header
+		status int
+	}{
+		{http.Header{"Origin": {"https://example.com"}}, http.StatusForbidden},
+		{http.Header{"Sec-Websocket-Version": {"8"}}, http.StatusUpgradeRequired},
+		{http.Header{"Sec-Websocket-Key": {"short"}}, http.StatusBadRequest},
+		{http.Header{"Origin": {server.URL}}, http.StatusSwitchingProtocols},
+	}
+	for _, test := range tests {
+		client, response := dialStream(t, server, test.header)
+		client.conn.Close()
+		if response.StatusCode != test.status {
+			t.Errorf("%v: status = %d, want %d", test.header, response.StatusCode, test.status)
+		}
+	}
+	response, err := http.Post(server.URL+"/stream", "audio/pcm", strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", response.StatusCode)
+	}
+
+	messages := []struct {
+		start interface{}
+		code  int
+	}{
+		{StreamControl{Type: "start", Diarization: true, TargetLanguages: []string{"de"}}, closePolicyViolation},
+		{StreamControl{Type: "start", SampleRate: 12345}, closePolicyViolation},
+		{StreamControl{Type: "stop"}, closePolicyViolation},
+	}
+	for _, message := range messages {
+		client, _ := dialStream(t, server, nil)
+		client.writeJSON(message.start)
+		if event := client.readEvent(); event.Type != "error" || event.Error == nil || event.Error.Code != "BadRequest" {
+			t.Errorf("%+v: event = %+v, want a BadRequest error", message.start, event)
+		}
+		if code := client.readClose(); code != message.code {
+			t.Errorf("%+v: close = %d, want %d", message.start, code, message.code)
+		}
+		client.conn.Close()
+	}
+
+	// Unmasked frames are a protocol error.
+	client, _ := dialStream(t, server, nil)
+	defer client.conn.Close()
+	client.conn.Write([]byte{0x80 | opText, 2, '{', '}'})
+	if code := client.readClose(); code != closeProtocolError {
+		t.Errorf("close = %d, want %d", code, closeProtocolError)
+	}
+}
+
+func TestPCMConverter(t *testing.T) {
+	samples := func(values ...int16) []byte {
+		var b bytes.Buffer
+		binary.Write(&b, binary.LittleEndian, values)
+		return b.Bytes()
+	}
+	converter := &pcmConverter{channels: 2}
+	data := samples(100, 300, -32768, -32768, 1, 2)
+	got := append(converter.convert(data[:5]), converter.convert(data[5:])...)
+	if want := samples(200, -32768, 2); !bytes.Equal(got, want) {
+		t.Errorf("stereo = %v, want %v", got, want)
+	}
+
+	converter = &pcmConverter{float: true, channels: 1}
+	var floats bytes.Buffer
+	binary.Write(&floats, binary.LittleEndian, []float32{0, -1, 1.5, float32(math.NaN())})
+	if got, want := converter.convert(floats.Bytes()), samples(0, -32767, 32767, 0); !bytes.Equal(got, want) {
+		t.Errorf("float = %v, want %v", got, want)
+	}
+
+	converter = &pcmConverter{channels: 1}
+	if got := converter.convert([]byte{1, 2, 3}); !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Errorf("mono = %v, want the frame", got)
+	}
+}
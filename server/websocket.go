@@ -0,0 +1,299 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package server
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is appended to the key of a handshake to compute its Sec-WebSocket-Accept header.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opcodes of WebSocket frames.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Status codes of WebSocket close frames.
+const (
+	closeNormal          = 1000
+	closeProtocolError   = 1002
+	closeNoStatus        = 1005
+	closePolicyViolation = 1008
+	closeMessageTooBig   = 1009
+	closeInternalError   = 1011
+)
+
+const (
+	// maxMessageSize is the maximum size of a message received from a client.
+	maxMessageSize = 1 << 20
+
+	// writeTimeout is the maximum duration of a frame write, so that clients that stop reading do not block the
+	// sessions.
+	writeTimeout = 10 * time.Second
+)
+
+// errConnectionClosed is returned by writes after the close frame is sent.
+var errConnectionClosed = errors.New("websocket connection closed")
+
+// closeError ends the messages of a connection, closed by the client or because of a protocol violation.
+type closeError struct {
+	code   int
+	reason string
+}
+
+func (e *closeError) Error() string {
+	return fmt.Sprintf("websocket closed with status %d %s", e.code, e.reason)
+}
+
+// wsConn is the server side of a WebSocket connection (RFC 6455), with neither extensions nor subprotocols. Writes are
+// safe for concurrent use; messages are read by a single goroutine.
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mu        sync.Mutex
+	closeSent bool
+}
+
+// checkUpgrade checks that a request is a WebSocket handshake from an allowed origin. checkOrigin defaults to
+// sameOrigin.
+func checkUpgrade(w http.ResponseWriter, r *http.Request, checkOrigin func(*http.Request) bool) error {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		return newError(http.StatusMethodNotAllowed, "MethodNotAllowed", "%s is not allowed, use GET", r.Method)
+	}
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		w.Header().Set("Upgrade", "websocket")
+		return newError(http.StatusUpgradeRequired, "UpgradeRequired", "a WebSocket handshake is required")
+	}
+	if version := r.Header.Get("Sec-WebSocket-Version"); version != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return newError(http.StatusUpgradeRequired, "UpgradeRequired", "unsupported WebSocket version %q, expected 13",
+			version)
+	}
+	if key, err := base64.StdEncoding.DecodeString(r.Header.Get("Sec-WebSocket-Key")); err != nil || len(key) != 16 {
+		return newError(http.StatusBadRequest, "BadRequest", "invalid Sec-WebSocket-Key")
+	}
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(r) {
+		return newError(http.StatusForbidden, "Forbidden", "origin %q is not allowed", r.Header.Get("Origin"))
+	}
+	return nil
+}
+
+// headerContains reports whether a comma-separated header contains a token, ignoring case.
+func headerContains(header http.Header, name string, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin allows requests without Origin header, which are not sent by browsers, and those whose origin is the
+// host of the request.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// upgrade completes the handshake of a request checked by checkUpgrade, and takes over its connection.
+func upgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, newError(http.StatusInternalServerError, "InternalError", "the connection cannot be upgraded")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	accept := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + websocketGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(accept[:]) + "\r\n\r\n"
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, reader: rw.Reader}, nil
+}
+
+// readMessage reads the next text or binary message, answering pings and closes. It returns a *closeError once the
+// client closes the connection, or after sending a close frame for a protocol violation.
+func (ws *wsConn) readMessage() (int, []byte, error) {
+	opcode := -1
+	var message []byte
+	for {
+		fin, op, payload, err := ws.readFrame(maxMessageSize - len(message))
+		if err != nil {
+			return 0, nil, err
+		}
+		switch op {
+		case opPing:
+			ws.writeFrame(opPong, payload)
+			continue
+		case opPong:
+			continue
+		case opClose:
+			e := &closeError{code: closeNoStatus}
+			if len(payload) >= 2 {
+				e.code, e.reason = int(binary.BigEndian.Uint16(payload)), string(payload[2:])
+				payload = payload[:2]
+			}
+			// The status code is echoed, as required.
+			ws.writeFrame(opClose, payload)
+			return 0, nil, e
+		case opContinuation:
+			if opcode < 0 {
+				return 0, nil, ws.fail(closeProtocolError, "unexpected continuation frame")
+			}
+		case opText, opBinary:
+			if opcode >= 0 {
+				return 0, nil, ws.fail(closeProtocolError, "expected a continuation frame")
+			}
+			opcode = op
+		default:
+			return 0, nil, ws.fail(closeProtocolError, fmt.Sprintf("unknown opcode %d", op))
+		}
+		message = append(message, payload...)
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// readFrame reads a frame whose payload is at most limit bytes, or a control frame.
+func (ws *wsConn) readFrame(limit int) (bool, int, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode := header[0]&0x80 != 0, int(header[0]&0x0F)
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, ws.fail(closeProtocolError, "reserved bits set without extension")
+	}
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, ws.fail(closeProtocolError, "client frames must be masked")
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(ws.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(ws.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if opcode >= opClose && (length > 125 || !fin) {
+		return false, 0, nil, ws.fail(closeProtocolError, "invalid control frame")
+	}
+	if opcode < opClose && length > uint64(limit) {
+		return false, 0, nil, ws.fail(closeMessageTooBig, fmt.Sprintf("messages are limited to %d bytes",
+			maxMessageSize))
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(ws.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// fail sends a close frame for a violation by the client and returns it as an error.
+func (ws *wsConn) fail(code int, reason string) error {
+	ws.writeClose(code, reason)
+	return &closeError{code: code, reason: reason}
+}
+
+// writeFrame writes an unfragmented frame. Nothing is written after a close frame.
+func (ws *wsConn) writeFrame(opcode int, payload []byte) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.closeSent {
+		return errConnectionClosed
+	}
+	frame := make([]byte, 0, 10+len(payload))
+	frame = append(frame, 0x80|byte(opcode))
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 126, byte(n>>8), byte(n))
+	default:
+		var extended [8]byte
+		binary.BigEndian.PutUint64(extended[:], uint64(n))
+		frame = append(append(frame, 127), extended[:]...)
+	}
+	frame = append(frame, payload...)
+	if opcode == opClose {
+		ws.closeSent = true
+	}
+	ws.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := ws.conn.Write(frame)
+	return err
+}
+
+// writeJSON writes a value as a text message.
+func (ws *wsConn) writeJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ws.writeFrame(opText, data)
+}
+
+// writeClose writes a close frame, whose reason is truncated to the size of a control frame.
+func (ws *wsConn) writeClose(code int, reason string) error {
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	return ws.writeFrame(opClose, append(payload, reason...))
+}
+
+// Close closes the connection, which ends the pending reads.
+func (ws *wsConn) Close() error {
+	return ws.conn.Close()
+}
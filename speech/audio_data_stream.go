@@ -93,7 +93,7 @@ func (stream AudioDataStream) CanReadDataAt(bytesRequested uint, off int64) bool
 // It returns size of data filled to the buffer and any write error encountered.
 func (stream AudioDataStream) Read(buffer []byte) (int, error) {
 	if len(buffer) == 0 {
-		return 0, common.NewCarbonError(0x005) // SPXERR_INVALID_ARG
+		return 0, common.ErrInvalidArg
 	}
 	var outSize C.uint32_t
 	ret := uintptr(C.audio_data_stream_read(stream.handle, (*C.uint8_t)(unsafe.Pointer(&buffer[0])), (C.uint32_t)(len(buffer)), &outSize))
@@ -110,7 +110,7 @@ func (stream AudioDataStream) Read(buffer []byte) (int, error) {
 // It returns size of data filled to the buffer and any write error encountered.
 func (stream AudioDataStream) ReadAt(buffer []byte, off int64) (int, error) {
 	if len(buffer) == 0 {
-		return 0, common.NewCarbonError(0x005) // SPXERR_INVALID_ARG
+		return 0, common.ErrInvalidArg
 	}
 	var outSize C.uint32_t
 	ret := uintptr(C.audio_data_stream_read_from_position(stream.handle, (*C.uint8_t)(unsafe.Pointer(&buffer[0])), (C.uint32_t)(len(buffer)), (C.uint32_t)(off), &outSize))
@@ -5,6 +5,8 @@ package speech
 
 import (
 	"sync"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
 )
 
 // #include <speechapi_c_common.h>
@@ -31,10 +33,15 @@ func getSessionStartedCallback(handle C.SPXHANDLE) SessionEventHandler {
 func recognizerFireEventSessionStarted(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHANDLE) {
 	handler := getSessionStartedCallback(handle)
 	event, err := NewSessionEventArgsFromHandle(handle2uintptr(eventHandle))
-	if err != nil || handler == nil {
+	if err != nil {
 		C.recognizer_event_handle_release(handle)
 		return
 	}
+	traceSessionStarted(handle, event.SessionID)
+	if handler == nil {
+		event.Close()
+		return
+	}
 	handler(*event)
 }
 
@@ -56,10 +63,16 @@ func getSessionStoppedCallback(handle C.SPXHANDLE) SessionEventHandler {
 func recognizerFireEventSessionStopped(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHANDLE) {
 	handler := getSessionStoppedCallback(handle)
 	event, err := NewSessionEventArgsFromHandle(handle2uintptr(eventHandle))
-	if err != nil || handler == nil {
+	if err != nil {
 		C.recognizer_event_handle_release(handle)
 		return
 	}
+	traceSessionStopped(handle)
+	endLimitedSession(handle)
+	if handler == nil {
+		event.Close()
+		return
+	}
 	handler(*event)
 }
 
@@ -81,10 +94,15 @@ func getSpeechStartDetectedCallback(handle C.SPXHANDLE) RecognitionEventHandler
 func recognizerFireEventSpeechStartDetected(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHANDLE) {
 	handler := getSpeechStartDetectedCallback(handle)
 	event, err := NewRecognitionEventArgsFromHandle(handle2uintptr(eventHandle))
-	if err != nil || handler == nil {
+	if err != nil {
 		C.recognizer_event_handle_release(handle)
 		return
 	}
+	traceUtteranceStarted(handle)
+	if handler == nil {
+		event.Close()
+		return
+	}
 	handler(*event)
 }
 
@@ -131,10 +149,18 @@ func getRecognizedCallback(handle C.SPXHANDLE) SpeechRecognitionEventHandler {
 func recognizerFireEventRecognized(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHANDLE) {
 	handler := getRecognizedCallback(handle)
 	event, err := NewSpeechRecognitionEventArgsFromHandle(handle2uintptr(eventHandle))
-	if err != nil || handler == nil {
+	if err != nil {
 		C.recognizer_event_handle_release(handle)
 		return
 	}
+	if traceResultEvents(handle) {
+		traceRecognitionResult(handle, &event.Result)
+	}
+	recordRecognitionResult(metrics.SpeechRecognizer, &event.Result)
+	if handler == nil {
+		event.Close()
+		return
+	}
 	handler(*event)
 }
 
@@ -160,6 +186,7 @@ func recognizerFireEventRecognizing(handle C.SPXRECOHANDLE, eventHandle C.SPXEVE
 		C.recognizer_event_handle_release(handle)
 		return
 	}
+	recordPartial(metrics.SpeechRecognizer, handle)
 	handler(*event)
 }
 
@@ -181,10 +208,20 @@ func getCanceledCallback(handle C.SPXHANDLE) SpeechRecognitionCanceledEventHandl
 func recognizerFireEventCanceled(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHANDLE) {
 	handler := getCanceledCallback(handle)
 	event, err := NewSpeechRecognitionCanceledEventArgsFromHandle(handle2uintptr(eventHandle))
-	if err != nil || handler == nil {
+	if err != nil {
 		C.recognizer_event_handle_release(handle)
 		return
 	}
+	if traceResultEvents(handle) {
+		traceRecognitionCanceled(handle, &event.Result, event.Err())
+	}
+	cancelLimitedSession(handle, event.Err())
+	recordRecognitionStopped(handle)
+	recordRecognitionCanceled(metrics.SpeechRecognizer, event.Result.ResultID, event.Err())
+	if handler == nil {
+		event.Close()
+		return
+	}
 	handler(*event)
 }
 
@@ -260,6 +297,7 @@ func synthesizerFireEventSynthesisCompleted(handle C.SPXRECOHANDLE, eventHandle
 		C.synthesizer_event_handle_release(handle)
 		return
 	}
+	recordSynthesisResult(&event.Result)
 	handler(*event)
 }
 
@@ -285,6 +323,7 @@ func synthesizerFireEventSynthesisCanceled(handle C.SPXRECOHANDLE, eventHandle C
 		C.synthesizer_event_handle_release(handle)
 		return
 	}
+	recordSynthesisResult(&event.Result)
 	handler(*event)
 }
 
@@ -5,6 +5,8 @@ package speech
 
 import (
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/diagnostics/logging"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/cancellation"
 )
 
 // #include <stdlib.h>
@@ -18,6 +20,35 @@ type CancellationDetails struct {
 	Reason       common.CancellationReason
 	ErrorCode    common.CancellationErrorCode
 	ErrorDetails string
+
+	// SessionID is the identifier of the session of the canceled request, when known.
+	SessionID string
+
+	// SessionLog is the log captured for the session when it was canceled with an error and a session log capture
+	// is enabled (see logging.EnableSessionLogCapture).
+	SessionLog *logging.SessionLog
+}
+
+// NewCancellationDetailsFromSpeechRecognitionResult creates the object from the speech recognition result.
+func NewCancellationDetailsFromSpeechRecognitionResult(result *SpeechRecognitionResult) (*CancellationDetails, error) {
+	cancellationDetails := new(CancellationDetails)
+	/* Reason */
+	var cReason C.Result_CancellationReason
+	ret := uintptr(C.result_get_reason_canceled(result.handle, &cReason))
+	if ret != C.SPX_NOERROR {
+		return nil, common.NewCarbonError(ret)
+	}
+	cancellationDetails.Reason = (common.CancellationReason)(cReason)
+	/* ErrorCode */
+	var cCode C.Result_CancellationErrorCode
+	ret = uintptr(C.result_get_canceled_error_code(result.handle, &cCode))
+	if ret != C.SPX_NOERROR {
+		return nil, common.NewCarbonError(ret)
+	}
+	cancellationDetails.ErrorCode = (common.CancellationErrorCode)(cCode)
+	cancellationDetails.ErrorDetails = result.Properties.GetProperty(common.SpeechServiceResponseJSONErrorDetails, "")
+	cancellationDetails.SetSessionID(result.Properties.GetProperty(common.SpeechSessionID, ""))
+	return cancellationDetails, nil
 }
 
 // NewCancellationDetailsFromSpeechSynthesisResult creates the object from the speech synthesis result.
@@ -38,5 +69,47 @@ func NewCancellationDetailsFromSpeechSynthesisResult(result *SpeechSynthesisResu
 	}
 	cancellationDetails.ErrorCode = (common.CancellationErrorCode)(cCode)
 	cancellationDetails.ErrorDetails = result.Properties.GetProperty(common.CancellationDetailsReasonDetailedText, "")
+	cancellationDetails.SetSessionID(result.Properties.GetProperty(common.SpeechSessionID, ""))
 	return cancellationDetails, nil
 }
+
+// SetSessionID sets the session of the canceled request, as reported by the SpeechSessionID property of its
+// recognizer or synthesizer, and looks up the log captured for the session if it was canceled with an error.
+func (details *CancellationDetails) SetSessionID(sessionID string) {
+	details.SessionID = sessionID
+	details.SessionLog = nil
+	if details.Reason == common.Error {
+		details.SessionLog = logging.CapturedSessionLog(sessionID)
+	}
+}
+
+// Err returns the cancellation as an error.
+func (details *CancellationDetails) Err() *common.CancellationError {
+	return &common.CancellationError{Reason: details.Reason, ErrorCode: details.ErrorCode, ErrorDetails: details.ErrorDetails, SessionID: details.SessionID}
+}
+
+// canceledRecognitionError returns the error of a canceled recognition result, or nil if the result is not canceled.
+// The session is taken from the properties of the recognizer when the result does not report it.
+func canceledRecognitionError(result *SpeechRecognitionResult, recognizerProperties *common.PropertyCollection) error {
+	if result == nil || result.Reason != common.Canceled {
+		return nil
+	}
+	details, err := NewCancellationDetailsFromSpeechRecognitionResult(result)
+	if err != nil {
+		return err
+	}
+	return cancellation.Error(details.Err(), recognizerProperties)
+}
+
+// canceledSynthesisError returns the error of a canceled synthesis result, or nil if the result is not canceled.
+// The session is taken from the properties of the synthesizer when the result does not report it.
+func canceledSynthesisError(result *SpeechSynthesisResult, synthesizerProperties *common.PropertyCollection) error {
+	if result == nil || result.Reason != common.Canceled {
+		return nil
+	}
+	details, err := NewCancellationDetailsFromSpeechSynthesisResult(result)
+	if err != nil {
+		return err
+	}
+	return cancellation.Error(details.Err(), synthesizerProperties)
+}
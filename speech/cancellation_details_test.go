@@ -0,0 +1,40 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"testing"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/diagnostics/logging"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/cancellation"
+)
+
+func TestCancellationDetailsSessionID(t *testing.T) {
+	const sessionID = "3f2504e04f8941d39a0c0305e82c3301"
+	capture := logging.NewSessionLogCapture(10, 10)
+	if err := logging.EnableSessionLogCapture(capture); err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	defer logging.EnableSessionLogCapture(nil)
+	capture.Handle(logging.LogEvent{SessionID: sessionID, Message: "connection failed"})
+
+	details := &CancellationDetails{Reason: common.Error, ErrorCode: common.ConnectionFailure}
+	details.SetSessionID(sessionID)
+	if details.SessionLog == nil || len(details.SessionLog.Events) != 1 {
+		t.Error("Unexpected session log: ", details.SessionLog)
+	}
+	if err := details.Err(); err.SessionID != sessionID {
+		t.Error("Unexpected session ID: ", err.SessionID)
+	}
+	if _, ok := logging.SessionLogFromError(cancellation.Error(details.Err(), nil)); !ok {
+		t.Error("Expected the session log to be attached to the outcome error")
+	}
+
+	details = &CancellationDetails{Reason: common.EndOfStream}
+	details.SetSessionID(sessionID)
+	if details.SessionLog != nil || details.Err().SessionID != sessionID {
+		t.Error("Unexpected details for end of stream: ", details)
+	}
+}
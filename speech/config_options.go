@@ -0,0 +1,533 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// Auth identifies the service a SpeechConfig connects to and the credentials used to authenticate. Exactly one of
+// Region, Endpoint and Host must be set. A Region requires a Key or a Token, an Endpoint or a Host may use either or
+// none of them.
+type Auth struct {
+	Key      string
+	Token    string
+	Region   string
+	Endpoint string
+	Host     string
+}
+
+// SubscriptionAuth authenticates with a subscription key against the service in the given region.
+func SubscriptionAuth(subscriptionKey string, region string) Auth {
+	return Auth{Key: subscriptionKey, Region: region}
+}
+
+// TokenAuth authenticates with an authorization token against the service in the given region.
+func TokenAuth(authorizationToken string, region string) Auth {
+	return Auth{Token: authorizationToken, Region: region}
+}
+
+// EndpointAuth connects to a non-standard service endpoint, using the subscription key if not empty.
+func EndpointAuth(endpoint string, subscriptionKey string) Auth {
+	return Auth{Key: subscriptionKey, Endpoint: endpoint}
+}
+
+// HostAuth connects to a non-default service host, using the subscription key if not empty.
+func HostAuth(host string, subscriptionKey string) Auth {
+	return Auth{Key: subscriptionKey, Host: host}
+}
+
+// String describes the auth without revealing the key or the token.
+func (auth Auth) String() string {
+	var parts []string
+	if auth.Region != "" {
+		parts = append(parts, "region="+auth.Region)
+	}
+	if auth.Endpoint != "" {
+		parts = append(parts, "endpoint="+auth.Endpoint)
+	}
+	if auth.Host != "" {
+		parts = append(parts, "host="+auth.Host)
+	}
+	if auth.Key != "" {
+		parts = append(parts, "key=REDACTED")
+	}
+	if auth.Token != "" {
+		parts = append(parts, "token=REDACTED")
+	}
+	return "Auth{" + strings.Join(parts, " ") + "}"
+}
+
+func (auth Auth) validate(builder *configBuilder) {
+	targets := 0
+	for _, target := range []string{auth.Region, auth.Endpoint, auth.Host} {
+		if target != "" {
+			targets++
+		}
+	}
+	switch {
+	case targets == 0:
+		builder.fail("Auth", "one of Region, Endpoint or Host is required")
+	case targets > 1:
+		builder.fail("Auth", "only one of Region, Endpoint or Host can be set")
+	case auth.Region != "" && auth.Key == "" && auth.Token == "":
+		builder.fail("Auth", "a Key or a Token is required with a Region")
+	case auth.Endpoint != "" && !isAbsoluteURL(auth.Endpoint):
+		builder.fail("Auth", "endpoint %q is not an absolute URL", auth.Endpoint)
+	case auth.Host != "" && !isAbsoluteURL(auth.Host):
+		builder.fail("Auth", "host %q is not an absolute URL", auth.Host)
+	}
+}
+
+func (auth Auth) newSpeechConfig() (*SpeechConfig, error) {
+	var config *SpeechConfig
+	var err error
+	switch {
+	case auth.Endpoint != "" && auth.Key != "":
+		config, err = NewSpeechConfigFromEndpointWithSubscription(auth.Endpoint, auth.Key)
+	case auth.Endpoint != "":
+		config, err = NewSpeechConfigFromEndpoint(auth.Endpoint)
+	case auth.Host != "" && auth.Key != "":
+		config, err = NewSpeechConfigFromHostWithSubscription(auth.Host, auth.Key)
+	case auth.Host != "":
+		config, err = NewSpeechConfigFromHost(auth.Host)
+	case auth.Key != "":
+		config, err = NewSpeechConfigFromSubscription(auth.Key, auth.Region)
+	default:
+		return NewSpeechConfigFromAuthorizationToken(auth.Token, auth.Region)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if auth.Token != "" {
+		if err = config.SetAuthorizationToken(auth.Token); err != nil {
+			config.Close()
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+func authFromConfig(config *SpeechConfig) Auth {
+	return Auth{
+		Key:      config.SubscriptionKey(),
+		Token:    config.AuthorizationToken(),
+		Region:   config.Region(),
+		Endpoint: config.GetProperty(common.SpeechServiceConnectionEndpoint),
+		Host:     config.GetProperty(common.SpeechServiceConnectionHost),
+	}
+}
+
+func isAbsoluteURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
+
+// OptionError reports an Auth or a ConfigOption that failed validation. It matches common.ErrInvalidArg with
+// errors.Is.
+type OptionError struct {
+	// Option is the name of the option, such as "WithLanguage", or "Auth".
+	Option  string
+	Message string
+}
+
+func (e *OptionError) Error() string {
+	return e.Option + ": " + e.Message
+}
+
+// Is reports whether target is common.ErrInvalidArg.
+func (e *OptionError) Is(target error) bool {
+	return common.ErrInvalidArg.Is(target)
+}
+
+// ConfigError aggregates the errors of all the options passed to NewConfig or Clone that failed validation.
+type ConfigError struct {
+	Errors []error
+}
+
+func (e *ConfigError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid speech config: " + strings.Join(messages, "; ")
+}
+
+// Is reports whether any of the aggregated errors matches target.
+func (e *ConfigError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first aggregated error that matches target.
+func (e *ConfigError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigOption configures a SpeechConfig created by NewConfig or SpeechConfig.Clone. Options validate their
+// arguments when they are called, before any native configuration is created.
+type ConfigOption func(builder *configBuilder)
+
+type configBuilder struct {
+	errs  []error
+	steps []func(config *SpeechConfig) error
+}
+
+func (builder *configBuilder) fail(option string, format string, args ...interface{}) {
+	builder.errs = append(builder.errs, &OptionError{Option: option, Message: fmt.Sprintf(format, args...)})
+}
+
+func (builder *configBuilder) apply(step func(config *SpeechConfig) error) {
+	builder.steps = append(builder.steps, step)
+}
+
+func (builder *configBuilder) err() error {
+	if len(builder.errs) == 0 {
+		return nil
+	}
+	return &ConfigError{Errors: builder.errs}
+}
+
+func (builder *configBuilder) applyTo(config *SpeechConfig) error {
+	for _, step := range builder.steps {
+		if err := step(config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (builder *configBuilder) run(opts []ConfigOption) {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(builder)
+		}
+	}
+}
+
+// NewConfig creates a SpeechConfig for auth, configured by opts:
+//
+//	config, err := speech.NewConfig(speech.SubscriptionAuth(key, region),
+//		speech.WithLanguage("de-DE"),
+//		speech.WithProfanity(common.Masked),
+//		speech.WithSegmentationSilence(800*time.Millisecond))
+//
+// The auth and all the options are validated first, and all the validation failures are returned together in a
+// *ConfigError, matching common.ErrInvalidArg with errors.Is.
+func NewConfig(auth Auth, opts ...ConfigOption) (*SpeechConfig, error) {
+	builder := new(configBuilder)
+	auth.validate(builder)
+	builder.run(opts)
+	if err := builder.err(); err != nil {
+		return nil, err
+	}
+	config, err := auth.newSpeechConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err = builder.applyTo(config); err != nil {
+		config.Close()
+		return nil, err
+	}
+	return config, nil
+}
+
+// Clone creates a new SpeechConfig with the same service, credentials and settings as config, then applies opts.
+// It is meant to derive per-request configs from a shared one, for example with a different language, without
+// changing the shared config.
+// Settings made through the SpeechConfig setters and through the options of NewConfig are copied. Settings made
+// directly on the native handle, or on configs created by NewSpeechConfigFromHandle before they were wrapped, are not.
+// The clone shares the limiter of config, if any.
+// The returned config must be closed independently of config.
+func (config *SpeechConfig) Clone(opts ...ConfigOption) (*SpeechConfig, error) {
+	builder, err := newCloneBuilder(opts)
+	if err != nil {
+		return nil, err
+	}
+	clone, err := authFromConfig(config).newSpeechConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err = config.cloneInto(clone, builder); err != nil {
+		clone.Close()
+		return nil, err
+	}
+	return clone, nil
+}
+
+func newCloneBuilder(opts []ConfigOption) (*configBuilder, error) {
+	builder := new(configBuilder)
+	builder.run(opts)
+	return builder, builder.err()
+}
+
+// cloneInto copies the recorded settings and the limiter of config to clone, then applies the options of builder.
+func (config *SpeechConfig) cloneInto(clone *SpeechConfig, builder *configBuilder) error {
+	if err := config.history.replay(clone); err != nil {
+		return err
+	}
+	if err := builder.applyTo(clone); err != nil {
+		return err
+	}
+	clone.limiter = config.limiter
+	return nil
+}
+
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z]{4})?(-([A-Za-z]{2}|[0-9]{3}))?$`)
+
+func isLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+// WithLanguage sets the recognition language, a BCP-47 locale such as "en-US".
+func WithLanguage(locale string) ConfigOption {
+	return func(builder *configBuilder) {
+		if !isLocale(locale) {
+			builder.fail("WithLanguage", "%q is not a BCP-47 locale", locale)
+			return
+		}
+		builder.apply(func(config *SpeechConfig) error {
+			return config.SetSpeechRecognitionLanguage(locale)
+		})
+	}
+}
+
+// WithOutputFormat sets the recognition result output format.
+func WithOutputFormat(format common.OutputFormat) ConfigOption {
+	return func(builder *configBuilder) {
+		if format != common.Simple && format != common.Detailed {
+			builder.fail("WithOutputFormat", "unknown output format %d", format)
+			return
+		}
+		builder.apply(func(config *SpeechConfig) error {
+			return config.SetOutputFormat(format)
+		})
+	}
+}
+
+// WithProfanity sets how profanity is handled in the results.
+func WithProfanity(profanity common.ProfanityOption) ConfigOption {
+	return func(builder *configBuilder) {
+		if profanity != common.Masked && profanity != common.Removed && profanity != common.Raw {
+			builder.fail("WithProfanity", "unknown profanity option %d", profanity)
+			return
+		}
+		builder.apply(func(config *SpeechConfig) error {
+			return config.SetProfanity(profanity)
+		})
+	}
+}
+
+// WithWordTimestamps includes word-level timestamps in the results.
+func WithWordTimestamps() ConfigOption {
+	return func(builder *configBuilder) {
+		builder.apply(func(config *SpeechConfig) error {
+			return config.RequestWordLevelTimestamps()
+		})
+	}
+}
+
+func durationOption(option string, id common.PropertyID, timeout time.Duration) ConfigOption {
+	return func(builder *configBuilder) {
+		if err := common.ValidatePropertyDuration(id, timeout); err != nil {
+			builder.fail(option, "%v", err)
+			return
+		}
+		builder.apply(func(config *SpeechConfig) error {
+			return config.SetProperty(id, strconv.FormatInt(int64(timeout/time.Millisecond), 10))
+		})
+	}
+}
+
+// WithSegmentationSilence sets the duration of silence after which a phrase is considered finished, between
+// common.MinSegmentationSilenceTimeout and common.MaxSegmentationSilenceTimeout.
+func WithSegmentationSilence(timeout time.Duration) ConfigOption {
+	return durationOption("WithSegmentationSilence", common.SegmentationSilenceTimeoutMs, timeout)
+}
+
+// WithInitialSilenceTimeout sets how long the recognition waits for speech before ending with no match.
+func WithInitialSilenceTimeout(timeout time.Duration) ConfigOption {
+	return durationOption("WithInitialSilenceTimeout", common.SpeechServiceConnectionInitialSilenceTimeoutMs, timeout)
+}
+
+// WithProxy connects through the given proxy. Credentials are optional and given as a username and a password.
+//
+// Note: Proxy functionality is not available on macOS. This option has no effect on this platform.
+func WithProxy(hostname string, port uint64, credentials ...string) ConfigOption {
+	return func(builder *configBuilder) {
+		valid := true
+		if hostname == "" {
+			builder.fail("WithProxy", "empty hostname")
+			valid = false
+		}
+		if port == 0 || port > 65535 {
+			builder.fail("WithProxy", "invalid port %d", port)
+			valid = false
+		}
+		if len(credentials) != 0 && len(credentials) != 2 {
+			builder.fail("WithProxy", "credentials must be a username and a password")
+			valid = false
+		}
+		if !valid {
+			return
+		}
+		builder.apply(func(config *SpeechConfig) error {
+			if len(credentials) == 2 {
+				return config.SetProxyWithUsernameAndPassword(hostname, port, credentials[0], credentials[1])
+			}
+			return config.SetProxy(hostname, port)
+		})
+	}
+}
+
+// WithServiceProperty sets a property passed to the service using the given channel.
+func WithServiceProperty(name string, value string, channel common.ServicePropertyChannel) ConfigOption {
+	return func(builder *configBuilder) {
+		if name == "" {
+			builder.fail("WithServiceProperty", "empty property name")
+			return
+		}
+		builder.apply(func(config *SpeechConfig) error {
+			return config.SetServiceProperty(name, value, channel)
+		})
+	}
+}
+
+// WithEndpointID sets the endpoint ID of a Custom Speech model.
+func WithEndpointID(endpointID string) ConfigOption {
+	return func(builder *configBuilder) {
+		if endpointID == "" {
+			builder.fail("WithEndpointID", "empty endpoint ID")
+			return
+		}
+		builder.apply(func(config *SpeechConfig) error {
+			return config.SetEndpointID(endpointID)
+		})
+	}
+}
+
+type servicePropertyKey struct {
+	name    string
+	channel common.ServicePropertyChannel
+}
+
+// configAccessor sets the properties of a SpeechConfig, recording them in its history.
+type configAccessor struct {
+	properties *common.PropertyCollection
+	history    *configHistory
+}
+
+func (accessor configAccessor) GetProperty(id common.PropertyID) string {
+	return accessor.properties.GetProperty(id, "")
+}
+
+func (accessor configAccessor) SetProperty(id common.PropertyID, value string) error {
+	err := accessor.properties.SetProperty(id, value)
+	if err == nil {
+		accessor.history.setProperty(id, value)
+	}
+	return err
+}
+
+// configHistory records the settings made through the SpeechConfig setters, so that Clone can replay them. The
+// property bag of the native configuration cannot be enumerated.
+type configHistory struct {
+	properties            map[common.PropertyID]string
+	propertyIDs           []common.PropertyID
+	namedProperties       map[string]string
+	propertyNames         []string
+	serviceProperties     map[servicePropertyKey]string
+	servicePropertyKeys   []servicePropertyKey
+	profanity             *common.ProfanityOption
+	synthesisOutputFormat *common.SpeechSynthesisOutputFormat
+}
+
+func (history *configHistory) setProperty(id common.PropertyID, value string) {
+	if history == nil {
+		return
+	}
+	if history.properties == nil {
+		history.properties = make(map[common.PropertyID]string)
+	}
+	if _, ok := history.properties[id]; !ok {
+		history.propertyIDs = append(history.propertyIDs, id)
+	}
+	history.properties[id] = value
+}
+
+func (history *configHistory) setPropertyByString(name string, value string) {
+	if history == nil {
+		return
+	}
+	if history.namedProperties == nil {
+		history.namedProperties = make(map[string]string)
+	}
+	if _, ok := history.namedProperties[name]; !ok {
+		history.propertyNames = append(history.propertyNames, name)
+	}
+	history.namedProperties[name] = value
+}
+
+func (history *configHistory) setServiceProperty(name string, value string, channel common.ServicePropertyChannel) {
+	if history == nil {
+		return
+	}
+	if history.serviceProperties == nil {
+		history.serviceProperties = make(map[servicePropertyKey]string)
+	}
+	key := servicePropertyKey{name, channel}
+	if _, ok := history.serviceProperties[key]; !ok {
+		history.servicePropertyKeys = append(history.servicePropertyKeys, key)
+	}
+	history.serviceProperties[key] = value
+}
+
+func (history *configHistory) replay(config *SpeechConfig) error {
+	if history == nil {
+		return nil
+	}
+	for _, id := range history.propertyIDs {
+		if err := config.SetProperty(id, history.properties[id]); err != nil {
+			return err
+		}
+	}
+	for _, name := range history.propertyNames {
+		if err := config.SetPropertyByString(name, history.namedProperties[name]); err != nil {
+			return err
+		}
+	}
+	for _, key := range history.servicePropertyKeys {
+		if err := config.SetServiceProperty(key.name, history.serviceProperties[key], key.channel); err != nil {
+			return err
+		}
+	}
+	if history.profanity != nil {
+		if err := config.SetProfanity(*history.profanity); err != nil {
+			return err
+		}
+	}
+	if history.synthesisOutputFormat != nil {
+		if err := config.SetSpeechSynthesisOutputFormat(*history.synthesisOutputFormat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
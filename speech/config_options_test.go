@@ -0,0 +1,223 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+func TestNewConfigAppliesOptions(t *testing.T) {
+	config, err := NewConfig(SubscriptionAuth("test", "region"),
+		WithLanguage("de-DE"),
+		WithOutputFormat(common.Detailed),
+		WithWordTimestamps(),
+		WithSegmentationSilence(800*time.Millisecond),
+		WithInitialSilenceTimeout(5*time.Second),
+		WithEndpointID("endpoint"))
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer config.Close()
+	if config.SubscriptionKey() != "test" || config.Region() != "region" {
+		t.Error("Auth not properly set")
+	}
+	if config.SpeechRecognitionLanguage() != "de-DE" || config.OutputFormat() != common.Detailed || config.EndpointID() != "endpoint" {
+		t.Error("Options not properly set")
+	}
+	if config.GetProperty(common.SegmentationSilenceTimeoutMs) != "800" {
+		t.Error("Unexpected segmentation silence: ", config.GetProperty(common.SegmentationSilenceTimeoutMs))
+	}
+	if config.GetProperty(common.SpeechServiceConnectionInitialSilenceTimeoutMs) != "5000" {
+		t.Error("Unexpected initial silence timeout: ", config.GetProperty(common.SpeechServiceConnectionInitialSilenceTimeoutMs))
+	}
+	if config.GetProperty(common.SpeechServiceResponseRequestWordLevelTimestamps) != "true" {
+		t.Error("Word level timestamps not requested")
+	}
+}
+
+func TestNewConfigAggregatesErrors(t *testing.T) {
+	config, err := NewConfig(Auth{Region: "region"},
+		WithLanguage("english"),
+		WithSegmentationSilence(-time.Second),
+		WithProxy("", 0))
+	if config != nil {
+		t.Error("Unexpected config")
+	}
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Errors) != 5 {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if !errors.Is(err, common.ErrInvalidArg) {
+		t.Error("Expected the error to match ErrInvalidArg")
+	}
+	var optionErr *OptionError
+	if !errors.As(err, &optionErr) || optionErr.Option != "Auth" {
+		t.Error("Unexpected first option error: ", optionErr)
+	}
+	for _, option := range []string{"WithLanguage", "WithSegmentationSilence", "WithProxy"} {
+		if !strings.Contains(err.Error(), option) {
+			t.Error("Missing option in error: ", option)
+		}
+	}
+}
+
+func TestAuthValidation(t *testing.T) {
+	valid := []Auth{
+		SubscriptionAuth("key", "westus"),
+		TokenAuth("token", "westus"),
+		EndpointAuth("wss://westus.stt.speech.microsoft.com/speech/recognition", ""),
+		HostAuth("ws://localhost:5000", ""),
+	}
+	for _, auth := range valid {
+		builder := new(configBuilder)
+		auth.validate(builder)
+		if builder.err() != nil {
+			t.Error("Unexpected error: ", auth, builder.err())
+		}
+	}
+	invalid := []Auth{
+		{},
+		{Key: "key"},
+		{Key: "key", Region: "westus", Host: "ws://localhost"},
+		{Region: "westus"},
+		{Endpoint: "westus.stt.speech.microsoft.com"},
+	}
+	for _, auth := range invalid {
+		builder := new(configBuilder)
+		auth.validate(builder)
+		if builder.err() == nil {
+			t.Error("Expected an error: ", auth)
+		}
+	}
+	if s := (Auth{Key: "secret", Region: "westus"}).String(); strings.Contains(s, "secret") {
+		t.Error("Key not redacted: ", s)
+	}
+}
+
+func TestLocaleValidation(t *testing.T) {
+	for _, locale := range []string{"en-US", "zh-Hans-CN", "es-419", "fil-PH", "de"} {
+		if !isLocale(locale) {
+			t.Error("Expected a valid locale: ", locale)
+		}
+	}
+	for _, locale := range []string{"", "en_US", "english", "en-US-x", "e-US"} {
+		if isLocale(locale) {
+			t.Error("Expected an invalid locale: ", locale)
+		}
+	}
+}
+
+func TestCloneDerivesConfig(t *testing.T) {
+	config, err := NewConfig(SubscriptionAuth("test", "region"), WithLanguage("en-US"))
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer config.Close()
+	if err = config.SetPropertyByString("custom", "value"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if err = config.SetSegmentationSilenceTimeout(600 * time.Millisecond); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	clone, err := config.Clone(WithLanguage("fr-FR"))
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer clone.Close()
+	if clone.SubscriptionKey() != "test" || clone.Region() != "region" || clone.GetPropertyByString("custom") != "value" {
+		t.Error("Settings not properly cloned")
+	}
+	if clone.SegmentationSilenceTimeout() != 600*time.Millisecond {
+		t.Error("Unexpected segmentation silence: ", clone.SegmentationSilenceTimeout())
+	}
+	if clone.SpeechRecognitionLanguage() != "fr-FR" || config.SpeechRecognitionLanguage() != "en-US" {
+		t.Error("Unexpected languages: ", clone.SpeechRecognitionLanguage(), config.SpeechRecognitionLanguage())
+	}
+	if _, err = config.Clone(WithInitialSilenceTimeout(-time.Second)); !errors.Is(err, common.ErrInvalidArg) {
+		t.Error("Unexpected error: ", err)
+	}
+}
+
+func TestCloneTranslationConfig(t *testing.T) {
+	config, err := NewSpeechTranslationConfigFromSubscription("test", "region")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer config.Close()
+	if err = config.AddTargetLanguage("de"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if err = config.AddTargetLanguage("fr"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if err = config.SetVoiceName("de-DE-KatjaNeural"); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	clone, err := config.Clone(WithLanguage("en-GB"))
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer clone.Close()
+	if languages := clone.GetTargetLanguages(); len(languages) != 2 || languages[0] != "de" || languages[1] != "fr" {
+		t.Error("Unexpected target languages: ", languages)
+	}
+	if clone.GetVoiceName() != "de-DE-KatjaNeural" || clone.SubscriptionKey() != "test" || clone.Region() != "region" {
+		t.Error("Settings not properly cloned")
+	}
+	if clone.SpeechRecognitionLanguage() != "en-GB" {
+		t.Error("Unexpected language: ", clone.SpeechRecognitionLanguage())
+	}
+}
+
+type countingLimiter struct {
+	acquired int
+}
+
+func (limiter *countingLimiter) AcquireSession(ctx context.Context, text string, ssml bool) (func(err error), error) {
+	limiter.acquired++
+	return func(error) {}, nil
+}
+
+func TestCloneSharesLimiter(t *testing.T) {
+	config, err := NewConfig(SubscriptionAuth("test", "region"))
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer config.Close()
+	limiter := new(countingLimiter)
+	config.SetLimiter(limiter)
+	clone, err := config.Clone(WithLanguage("fr-FR"))
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer clone.Close()
+	if clone.Limiter() != limiter {
+		t.Error("Unexpected limiter of the clone: ", clone.Limiter())
+	}
+	audioConfig, err := audio.NewAudioConfigFromWavFileInput("../test_files/turn_on_the_lamp.wav")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer audioConfig.Close()
+	recognizer, err := NewSpeechRecognizerFromConfig(clone, audioConfig)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer recognizer.Close()
+	release, err := acquireSession(context.Background(), recognizer.limiter, "", false)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	release(nil)
+	if limiter.acquired != 1 {
+		t.Error("Unexpected acquisitions from the limiter: ", limiter.acquired)
+	}
+}
@@ -0,0 +1,428 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/yaml"
+)
+
+// Environment variables read by LoadConfig. They override the values of the configuration file.
+const (
+	// ConfigFileEnvironmentVariable names the configuration file loaded when LoadConfig is called with an empty path.
+	ConfigFileEnvironmentVariable = "SPEECH_CONFIG_FILE"
+	// SubscriptionKeyEnvironmentVariable sets ConfigSettings.Key.
+	SubscriptionKeyEnvironmentVariable = "SPEECH_SUBSCRIPTION_KEY"
+	// SubscriptionRegionEnvironmentVariable sets ConfigSettings.Region.
+	SubscriptionRegionEnvironmentVariable = "SPEECH_SUBSCRIPTION_REGION"
+	// AuthorizationTokenEnvironmentVariable sets ConfigSettings.Token.
+	AuthorizationTokenEnvironmentVariable = "SPEECH_AUTHORIZATION_TOKEN"
+	// EndpointEnvironmentVariable sets ConfigSettings.Endpoint.
+	EndpointEnvironmentVariable = "SPEECH_ENDPOINT"
+	// HostEnvironmentVariable sets ConfigSettings.Host.
+	HostEnvironmentVariable = "SPEECH_HOST"
+	// EmbeddedModelsDirEnvironmentVariable sets the model paths of ConfigSettings.Embedded.
+	EmbeddedModelsDirEnvironmentVariable = "EMBEDDED_MODELS_DIR"
+)
+
+const redacted = "REDACTED"
+
+// ProxySettings configures the proxy used to connect to the service.
+type ProxySettings struct {
+	Host     string `json:"host"`
+	Port     uint64 `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// ModelSettings names an embedded model or voice and its license.
+type ModelSettings struct {
+	Name    string `json:"name"`
+	License string `json:"license,omitempty"`
+}
+
+// EmbeddedSettings configures the models of an EmbeddedSpeechConfig.
+type EmbeddedSettings struct {
+	ModelPaths       []string       `json:"modelPaths,omitempty"`
+	RecognitionModel *ModelSettings `json:"recognitionModel,omitempty"`
+	SynthesisVoice   *ModelSettings `json:"synthesisVoice,omitempty"`
+	TranslationModel *ModelSettings `json:"translationModel,omitempty"`
+	KeywordModel     *ModelSettings `json:"keywordModel,omitempty"`
+}
+
+// DialogSettings configures the dialog service configs created by the dialog package.
+type DialogSettings struct {
+	BotID         string `json:"botId,omitempty"`
+	ApplicationID string `json:"applicationId,omitempty"`
+}
+
+// PropertyValues maps property names to values. A name is either the name of a common.PropertyID constant, such as
+// SegmentationSilenceTimeoutMs, or any other name set with SetPropertyByString. In configuration files, numbers and
+// booleans are accepted as values.
+type PropertyValues map[string]string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (values *PropertyValues) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+	result := make(PropertyValues, len(raw))
+	for name, value := range raw {
+		switch v := value.(type) {
+		case string:
+			result[name] = v
+		case json.Number:
+			result[name] = v.String()
+		case bool:
+			result[name] = strconv.FormatBool(v)
+		default:
+			return fmt.Errorf("property %q must be a string, a number or a boolean", name)
+		}
+	}
+	*values = result
+	return nil
+}
+
+// PropertySetter is implemented by the configurations PropertyValues can be applied to.
+type PropertySetter interface {
+	SetProperty(id common.PropertyID, value string) error
+	SetPropertyByString(name string, value string) error
+}
+
+// ApplyTo sets the properties on target, by ID for the names of common.PropertyID constants and by name otherwise.
+func (values PropertyValues) ApplyTo(target PropertySetter) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var err error
+		if id, parseErr := common.ParsePropertyID(name); parseErr == nil {
+			err = target.SetProperty(id, values[name])
+		} else {
+			err = target.SetPropertyByString(name, values[name])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConfigSettings holds speech configuration settings loaded by LoadConfig, from which SpeechConfig,
+// SpeechTranslationConfig and EmbeddedSpeechConfig instances are created. The dialog package creates its configs from
+// ConfigSettings as well.
+type ConfigSettings struct {
+	Key        string `json:"key,omitempty"`
+	Token      string `json:"token,omitempty"`
+	Region     string `json:"region,omitempty"`
+	Endpoint   string `json:"endpoint,omitempty"`
+	Host       string `json:"host,omitempty"`
+	EndpointID string `json:"endpointId,omitempty"`
+
+	Proxy *ProxySettings `json:"proxy,omitempty"`
+
+	// Language is the recognition language.
+	Language string `json:"language,omitempty"`
+	// TargetLanguages are the translation target languages.
+	TargetLanguages []string `json:"targetLanguages,omitempty"`
+	// Voice is the synthesis voice, also used to synthesize translations.
+	Voice string `json:"voice,omitempty"`
+	// OutputFormat is the recognition output format, "simple" or "detailed".
+	OutputFormat string `json:"outputFormat,omitempty"`
+	// SynthesisOutputFormat is the service name of the synthesis output format, e.g. riff-16khz-16bit-mono-pcm.
+	SynthesisOutputFormat string `json:"synthesisOutputFormat,omitempty"`
+
+	Embedded   *EmbeddedSettings `json:"embedded,omitempty"`
+	Dialog     *DialogSettings   `json:"dialog,omitempty"`
+	Properties PropertyValues    `json:"properties,omitempty"`
+}
+
+// LoadConfig loads configuration settings from the JSON or YAML file at path, then overrides them with the environment
+// variables that are set (see SubscriptionKeyEnvironmentVariable and the following constants). With an empty path,
+// the file named by the SPEECH_CONFIG_FILE environment variable is loaded, if any, so that:
+//
+//	settings, err := speech.LoadConfig("")
+//	config, err := settings.SpeechConfig()
+//
+// works with SPEECH_SUBSCRIPTION_KEY and SPEECH_SUBSCRIPTION_REGION alone. Files whose extension is .yaml or .yml are
+// parsed as YAML, others as JSON. A minimal subset of YAML is supported: mappings, sequences and scalars.
+func LoadConfig(path string) (*ConfigSettings, error) {
+	settings := new(ConfigSettings)
+	if path == "" {
+		path = os.Getenv(ConfigFileEnvironmentVariable)
+	}
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		isYAML := strings.EqualFold(filepath.Ext(path), ".yaml") || strings.EqualFold(filepath.Ext(path), ".yml")
+		if err = settings.unmarshal(data, isYAML); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	settings.applyEnvironment(os.LookupEnv)
+	return settings, nil
+}
+
+func (settings *ConfigSettings) unmarshal(data []byte, isYAML bool) error {
+	if isYAML {
+		return yaml.Unmarshal(data, settings)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(settings)
+}
+
+func (settings *ConfigSettings) applyEnvironment(lookup func(name string) (string, bool)) {
+	env := func(name string) string {
+		value, _ := lookup(name)
+		return value
+	}
+	// The service is overridden as a whole, so that a region in the environment replaces an endpoint in the file.
+	region, endpoint, host := env(SubscriptionRegionEnvironmentVariable), env(EndpointEnvironmentVariable), env(HostEnvironmentVariable)
+	if region != "" || endpoint != "" || host != "" {
+		settings.Region, settings.Endpoint, settings.Host = region, endpoint, host
+	}
+	if key := env(SubscriptionKeyEnvironmentVariable); key != "" {
+		settings.Key = key
+	}
+	if token := env(AuthorizationTokenEnvironmentVariable); token != "" {
+		settings.Token = token
+	}
+	if dir := env(EmbeddedModelsDirEnvironmentVariable); dir != "" {
+		if settings.Embedded == nil {
+			settings.Embedded = new(EmbeddedSettings)
+		}
+		settings.Embedded.ModelPaths = []string{dir}
+	}
+}
+
+// Auth returns the service and the credentials of the settings.
+func (settings *ConfigSettings) Auth() Auth {
+	return Auth{Key: settings.Key, Token: settings.Token, Region: settings.Region, Endpoint: settings.Endpoint, Host: settings.Host}
+}
+
+func withSetting(set func(config *SpeechConfig) error) ConfigOption {
+	return func(builder *configBuilder) {
+		builder.apply(set)
+	}
+}
+
+// options returns the options applying the settings shared by all the speech configs.
+func (settings *ConfigSettings) options() []ConfigOption {
+	var opts []ConfigOption
+	if settings.EndpointID != "" {
+		opts = append(opts, WithEndpointID(settings.EndpointID))
+	}
+	if settings.Proxy != nil {
+		if settings.Proxy.Username != "" || settings.Proxy.Password != "" {
+			opts = append(opts, WithProxy(settings.Proxy.Host, settings.Proxy.Port, settings.Proxy.Username, settings.Proxy.Password))
+		} else {
+			opts = append(opts, WithProxy(settings.Proxy.Host, settings.Proxy.Port))
+		}
+	}
+	if settings.Language != "" {
+		opts = append(opts, WithLanguage(settings.Language))
+	}
+	switch strings.ToLower(settings.OutputFormat) {
+	case "":
+	case "simple":
+		opts = append(opts, WithOutputFormat(common.Simple))
+	case "detailed":
+		opts = append(opts, WithOutputFormat(common.Detailed))
+	default:
+		opts = append(opts, func(builder *configBuilder) {
+			builder.fail("OutputFormat", "unknown output format %q", settings.OutputFormat)
+		})
+	}
+	if settings.Voice != "" {
+		voice := settings.Voice
+		opts = append(opts, withSetting(func(config *SpeechConfig) error {
+			return config.SetSpeechSynthesisVoiceName(voice)
+		}))
+	}
+	if settings.SynthesisOutputFormat != "" {
+		format, err := common.ParseSpeechSynthesisOutputFormat(settings.SynthesisOutputFormat)
+		opts = append(opts, func(builder *configBuilder) {
+			if err != nil {
+				builder.fail("SynthesisOutputFormat", "%v", err)
+				return
+			}
+			builder.apply(func(config *SpeechConfig) error {
+				return config.SetSpeechSynthesisOutputFormat(format)
+			})
+		})
+	}
+	if len(settings.Properties) > 0 {
+		properties := settings.Properties
+		opts = append(opts, withSetting(func(config *SpeechConfig) error {
+			return properties.ApplyTo(config)
+		}))
+	}
+	return opts
+}
+
+// SpeechConfig creates a SpeechConfig from the settings. The caller must close it.
+func (settings *ConfigSettings) SpeechConfig() (*SpeechConfig, error) {
+	return NewConfig(settings.Auth(), settings.options()...)
+}
+
+// TranslationConfig creates a SpeechTranslationConfig from the settings, with the target languages and the voice
+// used to synthesize the translations. The caller must close it.
+func (settings *ConfigSettings) TranslationConfig() (*SpeechTranslationConfig, error) {
+	auth := settings.Auth()
+	builder := new(configBuilder)
+	auth.validate(builder)
+	builder.run(settings.options())
+	for _, language := range settings.TargetLanguages {
+		if !isLocale(language) {
+			builder.fail("TargetLanguages", "%q is not a BCP-47 language", language)
+		}
+	}
+	if err := builder.err(); err != nil {
+		return nil, err
+	}
+	config, err := newSpeechTranslationConfig(auth)
+	if err != nil {
+		return nil, err
+	}
+	err = builder.applyTo(&config.SpeechConfig)
+	for i := 0; err == nil && i < len(settings.TargetLanguages); i++ {
+		err = config.AddTargetLanguage(settings.TargetLanguages[i])
+	}
+	if err == nil && settings.Voice != "" {
+		err = config.SetVoiceName(settings.Voice)
+	}
+	if err != nil {
+		config.Close()
+		return nil, err
+	}
+	return config, nil
+}
+
+func newSpeechTranslationConfig(auth Auth) (*SpeechTranslationConfig, error) {
+	var config *SpeechTranslationConfig
+	var err error
+	switch {
+	case auth.Endpoint != "" && auth.Key != "":
+		config, err = NewSpeechTranslationConfigFromEndpointWithSubscription(auth.Endpoint, auth.Key)
+	case auth.Endpoint != "":
+		config, err = NewSpeechTranslationConfigFromEndpoint(auth.Endpoint)
+	case auth.Host != "" && auth.Key != "":
+		config, err = NewSpeechTranslationConfigFromHostWithSubscription(auth.Host, auth.Key)
+	case auth.Host != "":
+		config, err = NewSpeechTranslationConfigFromHost(auth.Host)
+	case auth.Key != "":
+		config, err = NewSpeechTranslationConfigFromSubscription(auth.Key, auth.Region)
+	default:
+		return NewSpeechTranslationConfigFromAuthorizationToken(auth.Token, auth.Region)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if auth.Token != "" {
+		if err = config.SetAuthorizationToken(auth.Token); err != nil {
+			config.Close()
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// EmbeddedConfig creates an EmbeddedSpeechConfig from the model paths and the models of the embedded settings. The
+// caller must close it.
+func (settings *ConfigSettings) EmbeddedConfig() (*EmbeddedSpeechConfig, error) {
+	builder := new(configBuilder)
+	if settings.Embedded == nil || len(settings.Embedded.ModelPaths) == 0 {
+		builder.fail("Embedded", "at least one model path is required")
+	}
+	builder.run(settings.options())
+	if err := builder.err(); err != nil {
+		return nil, err
+	}
+	embedded := settings.Embedded
+	config, err := NewEmbeddedSpeechConfigFromPaths(embedded.ModelPaths)
+	if err != nil {
+		return nil, err
+	}
+	err = builder.applyTo(config.SpeechConfig)
+	if err == nil && embedded.RecognitionModel != nil {
+		err = config.SetSpeechRecognitionModel(embedded.RecognitionModel.Name, embedded.RecognitionModel.License)
+	}
+	if err == nil && embedded.SynthesisVoice != nil {
+		err = config.SetSpeechSynthesisVoice(embedded.SynthesisVoice.Name, embedded.SynthesisVoice.License)
+	}
+	if err == nil && embedded.TranslationModel != nil {
+		err = config.SetSpeechTranslationModel(embedded.TranslationModel.Name, embedded.TranslationModel.License)
+	}
+	if err == nil && embedded.KeywordModel != nil {
+		err = config.SetKeywordRecognitionModel(embedded.KeywordModel.Name, embedded.KeywordModel.License)
+	}
+	if err != nil {
+		config.Close()
+		return nil, err
+	}
+	return config, nil
+}
+
+// String returns the settings as JSON, with the key, the token, the proxy password, the model licenses and the
+// properties whose name mentions a key, a token, a password or a license redacted.
+func (settings ConfigSettings) String() string {
+	redactValue(&settings.Key)
+	redactValue(&settings.Token)
+	if settings.Proxy != nil {
+		proxy := *settings.Proxy
+		redactValue(&proxy.Password)
+		settings.Proxy = &proxy
+	}
+	if settings.Embedded != nil {
+		embedded := *settings.Embedded
+		for _, model := range []**ModelSettings{&embedded.RecognitionModel, &embedded.SynthesisVoice, &embedded.TranslationModel, &embedded.KeywordModel} {
+			if *model != nil {
+				copied := **model
+				redactValue(&copied.License)
+				*model = &copied
+			}
+		}
+		settings.Embedded = &embedded
+	}
+	if settings.Properties != nil {
+		properties := make(PropertyValues, len(settings.Properties))
+		for name, value := range settings.Properties {
+			lower := strings.ToLower(name)
+			if strings.Contains(lower, "key") || strings.Contains(lower, "token") || strings.Contains(lower, "password") || strings.Contains(lower, "license") {
+				redactValue(&value)
+			}
+			properties[name] = value
+		}
+		settings.Properties = properties
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Sprintf("ConfigSettings{%v}", err)
+	}
+	return string(data)
+}
+
+func redactValue(value *string) {
+	if *value != "" {
+		*value = redacted
+	}
+}
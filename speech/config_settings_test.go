@@ -0,0 +1,199 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+const settingsYAML = `# Speech settings
+region: westus
+key: "secret-key"
+proxy:
+  host: proxy.local
+  port: 8080
+  password: 'proxy-secret'
+language: en-US
+targetLanguages: [de, "fr"]
+voice: en-US-AvaNeural   # default voice
+outputFormat: detailed
+embedded:
+  modelPaths:
+    - /models/stt
+    - /models/tts
+  recognitionModel:
+    name: en-US model
+    license: model-license
+dialog: {botId: my-bot}
+properties:
+  SegmentationSilenceTimeoutMs: 800
+  custom-flag: true
+`
+
+const settingsJSON = `{
+	"region": "westus",
+	"key": "secret-key",
+	"proxy": {"host": "proxy.local", "port": 8080, "password": "proxy-secret"},
+	"language": "en-US",
+	"targetLanguages": ["de", "fr"],
+	"voice": "en-US-AvaNeural",
+	"outputFormat": "detailed",
+	"embedded": {
+		"modelPaths": ["/models/stt", "/models/tts"],
+		"recognitionModel": {"name": "en-US model", "license": "model-license"}
+	},
+	"dialog": {"botId": "my-bot"},
+	"properties": {"SegmentationSilenceTimeoutMs": 800, "custom-flag": true}
+}`
+
+func TestConfigSettingsYAMLMatchesJSON(t *testing.T) {
+	fromYAML := new(ConfigSettings)
+	if err := fromYAML.unmarshal([]byte(settingsYAML), true); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	fromJSON := new(ConfigSettings)
+	if err := fromJSON.unmarshal([]byte(settingsJSON), false); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Error("Unexpected settings: ", fromYAML, fromJSON)
+	}
+	if fromYAML.Proxy.Port != 8080 || len(fromYAML.Embedded.ModelPaths) != 2 || fromYAML.Dialog.BotID != "my-bot" {
+		t.Error("Unexpected settings: ", fromYAML)
+	}
+	if fromYAML.Properties["SegmentationSilenceTimeoutMs"] != "800" || fromYAML.Properties["custom-flag"] != "true" {
+		t.Error("Unexpected properties: ", fromYAML.Properties)
+	}
+}
+
+func TestConfigSettingsYAMLNumericStrings(t *testing.T) {
+	settings := new(ConfigSettings)
+	if err := settings.unmarshal([]byte("key: 0123456789\nendpointId: 12345\nlanguage: true\nproxy: {host: 10.0.0.1, port: 8080}\n"), true); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if settings.Key != "0123456789" || settings.EndpointID != "12345" || settings.Language != "true" {
+		t.Error("Unexpected settings: ", settings)
+	}
+	if settings.Proxy.Host != "10.0.0.1" || settings.Proxy.Port != 8080 {
+		t.Error("Unexpected proxy: ", settings.Proxy)
+	}
+}
+
+func TestConfigSettingsRejectsInvalidFiles(t *testing.T) {
+	invalid := map[string]bool{
+		"region: westus\nregion: eastus\n":   true,
+		"region: westus\n  key: value\n":     true,
+		"voice: |\n  multi-line\n":           true,
+		"proxy:\n\thost: proxy.local\n":      true,
+		"unknown: value\n":                   true,
+		"targetLanguages: [de, [fr]]\n":      true,
+		"properties:\n  nested:\n    a: b\n": true,
+	}
+	for data := range invalid {
+		if err := new(ConfigSettings).unmarshal([]byte(data), true); err == nil {
+			t.Error("Expected an error for: ", data)
+		}
+	}
+}
+
+func TestLoadConfigAppliesEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "speech-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "speech.yml")
+	if err = ioutil.WriteFile(path, []byte("endpoint: wss://localhost/speech\nkey: file-key\nlanguage: de-DE\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	settings.applyEnvironment(func(name string) (string, bool) {
+		value, ok := map[string]string{
+			SubscriptionKeyEnvironmentVariable:    "env-key",
+			SubscriptionRegionEnvironmentVariable: "eastus",
+			EmbeddedModelsDirEnvironmentVariable:  "/models",
+		}[name]
+		return value, ok
+	})
+	expected := Auth{Key: "env-key", Region: "eastus"}
+	if settings.Auth() != expected || settings.Language != "de-DE" {
+		t.Error("Unexpected settings: ", settings)
+	}
+	if settings.Embedded == nil || !reflect.DeepEqual(settings.Embedded.ModelPaths, []string{"/models"}) {
+		t.Error("Unexpected embedded settings: ", settings.Embedded)
+	}
+	if _, err = LoadConfig(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestConfigSettingsStringRedactsSecrets(t *testing.T) {
+	settings := new(ConfigSettings)
+	if err := settings.unmarshal([]byte(settingsJSON), false); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	settings.Token = "secret-token"
+	settings.Properties["SpeechServiceConnectionKey"] = "secret-property"
+	s := settings.String()
+	for _, secret := range []string{"secret-key", "secret-token", "proxy-secret", "model-license", "secret-property"} {
+		if strings.Contains(s, secret) {
+			t.Error("Secret not redacted: ", secret, s)
+		}
+	}
+	if !strings.Contains(s, "westus") || !strings.Contains(s, "REDACTED") {
+		t.Error("Unexpected string: ", s)
+	}
+	if settings.Key != "secret-key" || settings.Proxy.Password != "proxy-secret" || settings.Embedded.RecognitionModel.License != "model-license" {
+		t.Error("String modified the settings")
+	}
+}
+
+type recordingPropertySetter struct {
+	byID   map[common.PropertyID]string
+	byName map[string]string
+}
+
+func (setter *recordingPropertySetter) SetProperty(id common.PropertyID, value string) error {
+	setter.byID[id] = value
+	return nil
+}
+
+func (setter *recordingPropertySetter) SetPropertyByString(name string, value string) error {
+	setter.byName[name] = value
+	return nil
+}
+
+func TestPropertyValuesApplyTo(t *testing.T) {
+	setter := &recordingPropertySetter{byID: map[common.PropertyID]string{}, byName: map[string]string{}}
+	values := PropertyValues{"SegmentationSilenceTimeoutMs": "800", "custom-flag": "true"}
+	if err := values.ApplyTo(setter); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if setter.byID[common.SegmentationSilenceTimeoutMs] != "800" || setter.byName["custom-flag"] != "true" {
+		t.Error("Unexpected properties: ", setter.byID, setter.byName)
+	}
+}
+
+func TestConfigSettingsValidatesBeforeCreatingConfigs(t *testing.T) {
+	settings := &ConfigSettings{Key: "key", Region: "westus", OutputFormat: "verbose", SynthesisOutputFormat: "mp3", TargetLanguages: []string{"german"}}
+	_, err := settings.TranslationConfig()
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Errors) != 3 {
+		t.Error("Unexpected error: ", err)
+	}
+	if _, err = settings.EmbeddedConfig(); !errors.Is(err, common.ErrInvalidArg) {
+		t.Error("Unexpected error: ", err)
+	}
+}
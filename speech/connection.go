@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// #include <speechapi_c_connection.h>
+import "C"
+
+// Connection is the connection of a recognizer or a synthesizer to the service. Recognizers and synthesizers connect
+// when they start; opening their connection ahead avoids the connection latency of their first request.
+type Connection struct {
+	handle C.SPXHANDLE
+}
+
+func newConnection(handle C.SPXHANDLE) *Connection {
+	connection := new(Connection)
+	connection.handle = handle
+	return connection
+}
+
+// NewConnectionFromRecognizer gets the connection of a speech recognizer.
+func NewConnectionFromRecognizer(recognizer *SpeechRecognizer) (*Connection, error) {
+	var handle C.SPXHANDLE
+	ret := uintptr(C.connection_from_recognizer(recognizer.handle, &handle))
+	if ret != C.SPX_NOERROR {
+		return nil, common.NewCarbonError(ret)
+	}
+	return newConnection(handle), nil
+}
+
+// NewConnectionFromTranslationRecognizer gets the connection of a translation recognizer.
+func NewConnectionFromTranslationRecognizer(recognizer *TranslationRecognizer) (*Connection, error) {
+	var handle C.SPXHANDLE
+	ret := uintptr(C.connection_from_recognizer(recognizer.handle, &handle))
+	if ret != C.SPX_NOERROR {
+		return nil, common.NewCarbonError(ret)
+	}
+	return newConnection(handle), nil
+}
+
+// NewConnectionFromSpeechSynthesizer gets the connection of a speech synthesizer.
+func NewConnectionFromSpeechSynthesizer(synthesizer *SpeechSynthesizer) (*Connection, error) {
+	var handle C.SPXHANDLE
+	ret := uintptr(C.connection_from_speech_synthesizer(synthesizer.handle, &handle))
+	if ret != C.SPX_NOERROR {
+		return nil, common.NewCarbonError(ret)
+	}
+	return newConnection(handle), nil
+}
+
+// Open opens the connection, for a continuous recognition or for single-shot recognitions and syntheses. Opening an
+// open connection does nothing.
+func (connection *Connection) Open(forContinuousRecognition bool) error {
+	ret := uintptr(C.connection_open(connection.handle, C.bool(forContinuousRecognition)))
+	if ret != C.SPX_NOERROR {
+		return common.NewCarbonError(ret)
+	}
+	return nil
+}
+
+// Disconnect closes the connection. The recognizer or synthesizer connects again when it starts.
+func (connection *Connection) Disconnect() error {
+	ret := uintptr(C.connection_close(connection.handle))
+	if ret != C.SPX_NOERROR {
+		return common.NewCarbonError(ret)
+	}
+	return nil
+}
+
+// Close releases the associated resources, without closing the connection.
+func (connection *Connection) Close() {
+	if connection.handle != C.SPXHANDLE_INVALID {
+		C.connection_handle_release(connection.handle)
+		connection.handle = C.SPXHANDLE_INVALID
+	}
+}
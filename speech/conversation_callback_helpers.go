@@ -3,6 +3,10 @@
 
 package speech
 
+import (
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
+)
+
 // #include <speechapi_c_common.h>
 // #include <speechapi_c_recognizer.h>
 import "C"
@@ -39,6 +43,7 @@ func conversationTranscriberFireEventTranscribing(handle C.SPXRECOHANDLE, eventH
 		C.recognizer_event_handle_release(eventHandle)
 		return
 	}
+	recordPartial(metrics.ConversationTranscriber, handle)
 	handler(*event)
 }
 
@@ -58,10 +63,18 @@ func getConversationTranscribedCallback(handle C.SPXHANDLE) ConversationTranscri
 func conversationTranscriberFireEventTranscribed(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHANDLE) {
 	handler := getConversationTranscribedCallback(handle)
 	event, err := NewConversationTranscriptionEventArgsFromHandle(handle2uintptr(eventHandle))
-	if err != nil || handler == nil {
+	if err != nil {
 		C.recognizer_event_handle_release(eventHandle)
 		return
 	}
+	if traceResultEvents(handle) {
+		traceRecognitionResult(handle, &event.Result.SpeechRecognitionResult)
+	}
+	recordRecognitionResult(metrics.ConversationTranscriber, &event.Result.SpeechRecognitionResult)
+	if handler == nil {
+		event.Close()
+		return
+	}
 	handler(*event)
 }
 
@@ -81,9 +94,19 @@ func getConversationCanceledCallback(handle C.SPXHANDLE) ConversationTranscripti
 func conversationTranscriberFireEventCanceled(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHANDLE) {
 	handler := getConversationCanceledCallback(handle)
 	event, err := NewConversationTranscriptionCanceledEventArgsFromHandle(handle2uintptr(eventHandle))
-	if err != nil || handler == nil {
+	if err != nil {
 		C.recognizer_event_handle_release(eventHandle)
 		return
 	}
+	if traceResultEvents(handle) {
+		traceRecognitionCanceled(handle, &event.Result.SpeechRecognitionResult, event.Err())
+	}
+	cancelLimitedSession(handle, event.Err())
+	recordRecognitionStopped(handle)
+	recordRecognitionCanceled(metrics.ConversationTranscriber, event.Result.ResultID, event.Err())
+	if handler == nil {
+		event.Close()
+		return
+	}
 	handler(*event)
 }
\ No newline at end of file
@@ -4,11 +4,13 @@
 package speech
 
 import (
+	"context"
 	"math"
 	"unsafe"
 
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
 )
 
 // #include <stdlib.h>
@@ -28,13 +30,15 @@ import "C"
 
 // ConversationTranscriber is the class for conversation transcribers.
 type ConversationTranscriber struct {
+	common.RecognitionProperties
 	Properties                 *common.PropertyCollection
 	handle                     C.SPXHANDLE
 	handleAsyncStartTranscribing C.SPXASYNCHANDLE
 	handleAsyncStopTranscribing  C.SPXASYNCHANDLE
+	limiter                      SessionLimiter
 }
 
-func newConversationTranscriberFromHandle(handle C.SPXHANDLE) (*ConversationTranscriber, error) {
+func newConversationTranscriberFromHandle(handle C.SPXHANDLE, limiter SessionLimiter) (*ConversationTranscriber, error) {
 	var propBagHandle C.SPXHANDLE
 	ret := uintptr(C.recognizer_get_property_bag(handle, &propBagHandle))
 	if ret != C.SPX_NOERROR {
@@ -43,9 +47,11 @@ func newConversationTranscriberFromHandle(handle C.SPXHANDLE) (*ConversationTran
 
 	transcriber := new(ConversationTranscriber)
 	transcriber.handle = handle
+	transcriber.limiter = limiter
 	transcriber.handleAsyncStartTranscribing = C.SPXHANDLE_INVALID
 	transcriber.handleAsyncStopTranscribing = C.SPXHANDLE_INVALID
 	transcriber.Properties = common.NewPropertyCollectionFromHandle(handle2uintptr(propBagHandle))
+	transcriber.RecognitionProperties = common.NewRecognitionPropertiesFromCollection(transcriber.Properties)
 	
 	return transcriber, nil
 }
@@ -72,7 +78,7 @@ func NewConversationTranscriberFromConfig(config *SpeechConfig, audioConfig *aud
 		return nil, common.NewCarbonError(ret)
 	}
 	
-	return newConversationTranscriberFromHandle(handle)
+	return newConversationTranscriberFromHandle(handle, config.limiter)
 }
 
 // NewConversationTranscriberFromAutoDetectSourceLangConfig creates a conversation transcriber with auto language detection
@@ -102,7 +108,7 @@ func NewConversationTranscriberFromAutoDetectSourceLangConfig(config *SpeechConf
 		return nil, common.NewCarbonError(ret)
 	}
 	
-	return newConversationTranscriberFromHandle(handle)
+	return newConversationTranscriberFromHandle(handle, config.limiter)
 }
 
 // NewConversationTranscriberFromSourceLanguageConfig creates a conversation transcriber with a specific source language
@@ -132,18 +138,33 @@ func NewConversationTranscriberFromSourceLanguageConfig(config *SpeechConfig, so
 		return nil, common.NewCarbonError(ret)
 	}
 	
-	return newConversationTranscriberFromHandle(handle)
+	return newConversationTranscriberFromHandle(handle, config.limiter)
 }
 
 // StartTranscribingAsync asynchronously initiates continuous conversation transcription.
 func (transcriber ConversationTranscriber) StartTranscribingAsync() chan error {
+	return transcriber.StartTranscribingWithContextAsync(context.Background())
+}
+
+// StartTranscribingWithContextAsync is like StartTranscribingAsync, with ctx as the parent of the trace spans of the
+// sessions and utterances (see the tracing package). The transcription is not stopped with ctx.
+func (transcriber ConversationTranscriber) StartTranscribingWithContextAsync(ctx context.Context) chan error {
 	outcome := make(chan error)
+	transcriber.beginTrace(ctx)
 	
 	go func() {
+		if err := startLimitedSession(ctx, transcriber.limiter, transcriber.handle, transcriber.connectLimitedEvents); err != nil {
+			traceEnd(transcriber.handle)
+			outcome <- err
+			return
+		}
+
 		// Close any unfinished previous attempt
 		ret := releaseAsyncHandleIfValid(&transcriber.handleAsyncStartTranscribing)
 		
 		if ret == C.SPX_NOERROR {
+			transcriber.connectRecordedEvents()
+			recordRecognitionStarted(metrics.ConversationTranscriber, transcriber.handle)
 			ret = uintptr(C.recognizer_start_continuous_recognition_async(transcriber.handle, &transcriber.handleAsyncStartTranscribing))
 		}
 		
@@ -154,6 +175,7 @@ func (transcriber ConversationTranscriber) StartTranscribingAsync() chan error {
 		releaseAsyncHandleIfValid(&transcriber.handleAsyncStartTranscribing)
 		
 		if ret != C.SPX_NOERROR {
+			endLimitedSession(transcriber.handle)
 			outcome <- common.NewCarbonError(ret)
 			return
 		}
@@ -180,6 +202,8 @@ func (transcriber ConversationTranscriber) StopTranscribingAsync() chan error {
 		}
 		
 		releaseAsyncHandleIfValid(&transcriber.handleAsyncStopTranscribing)
+		recordRecognitionStopped(transcriber.handle)
+		endLimitedSession(transcriber.handle)
 		
 		if ret != C.SPX_NOERROR {
 			outcome <- common.NewCarbonError(ret)
@@ -293,8 +317,58 @@ func (transcriber ConversationTranscriber) Canceled(handler ConversationTranscri
 	}
 }
 
+// connectTracedEvents connects the native callbacks of the events traced during a transcription for which no handler
+// is set.
+func (transcriber ConversationTranscriber) connectTracedEvents() {
+	handle := transcriber.handle
+	if getSessionStartedCallback(handle) == nil {
+		C.recognizer_session_started_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_conversation_transcriber_session_started)), nil)
+	}
+	if getSessionStoppedCallback(handle) == nil {
+		C.recognizer_session_stopped_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_conversation_transcriber_session_stopped)), nil)
+	}
+	if getSpeechStartDetectedCallback(handle) == nil {
+		C.recognizer_speech_start_detected_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_conversation_transcriber_speech_start_detected)), nil)
+	}
+	if getConversationTranscribedCallback(handle) == nil {
+		C.recognizer_recognized_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_conversation_transcriber_transcribed)), nil)
+	}
+	if getConversationCanceledCallback(handle) == nil {
+		C.recognizer_canceled_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_conversation_transcriber_canceled)), nil)
+	}
+}
+
+// connectRecordedEvents connects the native callbacks of the recognized and canceled events for which no handler is
+// set when a metrics hook is set, so that the results of a continuous transcription are recorded.
+func (transcriber ConversationTranscriber) connectRecordedEvents() {
+	if metrics.CurrentHook() == nil {
+		return
+	}
+	handle := transcriber.handle
+	if getConversationTranscribedCallback(handle) == nil {
+		C.recognizer_recognized_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_conversation_transcriber_transcribed)), nil)
+	}
+	if getConversationCanceledCallback(handle) == nil {
+		C.recognizer_canceled_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_conversation_transcriber_canceled)), nil)
+	}
+}
+
+// connectLimitedEvents connects the native callbacks of the canceled and session stopped events for which no handler
+// is set, so that the session of a transcription is released when it stops, with the error of its cancellation.
+func (transcriber ConversationTranscriber) connectLimitedEvents() {
+	handle := transcriber.handle
+	if getConversationCanceledCallback(handle) == nil {
+		C.recognizer_canceled_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_conversation_transcriber_canceled)), nil)
+	}
+	if getSessionStoppedCallback(handle) == nil {
+		C.recognizer_session_stopped_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_conversation_transcriber_session_stopped)), nil)
+	}
+}
+
 // Close disposes the associated resources.
 func (transcriber ConversationTranscriber) Close() {
+	traceEnd(transcriber.handle)
+	endLimitedSession(transcriber.handle)
 	transcriber.SessionStarted(nil)
 	transcriber.SessionStopped(nil)
 	transcriber.SpeechStartDetected(nil)
@@ -319,4 +393,8 @@ func (transcriber ConversationTranscriber) Close() {
 		C.recognizer_handle_release(transcriber.handle)
 		transcriber.handle = C.SPXHANDLE_INVALID
 	}
-}
\ No newline at end of file
+}
+
+func (transcriber *ConversationTranscriber) recognizerHandle() common.SPXHandle {
+	return handle2uintptr(transcriber.handle)
+}
@@ -0,0 +1,330 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// TranscriptUtterance is one transcribed utterance of a speaker.
+type TranscriptUtterance struct {
+	ResultID  string
+	SpeakerID string
+	Text      string
+	Offset    time.Duration
+	Duration  time.Duration
+}
+
+// End returns the offset of the end of the utterance.
+func (utterance TranscriptUtterance) End() time.Duration {
+	return utterance.Offset + utterance.Duration
+}
+
+// TranscriptTurn is a sequence of consecutive utterances of the same speaker.
+type TranscriptTurn struct {
+	SpeakerID string
+
+	// Speaker is the display name of the speaker, or SpeakerID when no name is mapped to it.
+	Speaker string
+
+	// Text is the text of the utterances, separated by spaces.
+	Text string
+
+	// Offset is the offset of the first utterance and Duration spans up to the end of the last one.
+	Offset   time.Duration
+	Duration time.Duration
+
+	Utterances []TranscriptUtterance
+}
+
+// SpeakerStats summarizes the participation of a speaker.
+type SpeakerStats struct {
+	SpeakerID string
+	Speaker   string
+
+	// TalkTime is the total duration of the utterances of the speaker, excluding the pauses between them.
+	TalkTime time.Duration
+
+	// Words is the number of space-separated words. Languages written without spaces count one word per utterance.
+	Words int
+
+	Turns      int
+	Utterances int
+}
+
+// Transcript is a snapshot of the turns collected by a TranscriptBuilder.
+type Transcript struct {
+	// Turns holds the turns, sorted by offset.
+	Turns []TranscriptTurn
+
+	// Speakers holds the statistics of each speaker, in the order they first spoke.
+	Speakers []SpeakerStats
+}
+
+// TranscriptBuilder collects the transcribed utterances of a ConversationTranscriber into a speaker-attributed
+// Transcript, merging consecutive utterances of the same speaker into turns.
+type TranscriptBuilder struct {
+	// MaxTurnGap, if not zero, starts a new turn when a speaker pauses longer than it, even if nobody else spoke.
+	MaxTurnGap time.Duration
+
+	mu          sync.Mutex
+	transcriber *ConversationTranscriber
+	names       map[string]string
+	utterances  []TranscriptUtterance
+}
+
+// NewTranscriptBuilder creates a transcript builder attached to the Transcribed event of the transcriber. Speaker
+// IDs, such as Guest-1, are replaced by the display names of the names map, which may be nil. The Transcribed handler
+// previously registered is replaced; to keep your own handler, create the builder with a nil transcriber and forward
+// events to OnTranscribed instead.
+func NewTranscriptBuilder(transcriber *ConversationTranscriber, names map[string]string) *TranscriptBuilder {
+	builder := &TranscriptBuilder{transcriber: transcriber, names: make(map[string]string)}
+	for id, name := range names {
+		builder.names[id] = name
+	}
+	if transcriber != nil {
+		transcriber.Transcribed(func(event ConversationTranscriptionEventArgs) {
+			defer event.Close()
+			builder.OnTranscribed(event)
+		})
+	}
+	return builder
+}
+
+// OnTranscribed adds the result of a Transcribed event to the transcript, unless it has no recognized text. It does
+// not close the event.
+func (builder *TranscriptBuilder) OnTranscribed(event ConversationTranscriptionEventArgs) {
+	result := event.Result
+	if result.Reason != common.RecognizedSpeech || strings.TrimSpace(result.Text) == "" {
+		return
+	}
+	builder.Add(TranscriptUtterance{
+		ResultID:  result.ResultID,
+		SpeakerID: result.SpeakerID,
+		Text:      result.Text,
+		Offset:    result.Offset,
+		Duration:  result.Duration,
+	})
+}
+
+// Add adds an utterance to the transcript, e.g. one restored from a previous session.
+func (builder *TranscriptBuilder) Add(utterance TranscriptUtterance) {
+	builder.mu.Lock()
+	defer builder.mu.Unlock()
+	builder.utterances = append(builder.utterances, utterance)
+}
+
+// SetSpeakerName maps a speaker ID to a display name, for the utterances already collected as well. An empty name
+// removes the mapping.
+func (builder *TranscriptBuilder) SetSpeakerName(speakerID string, name string) {
+	builder.mu.Lock()
+	defer builder.mu.Unlock()
+	if name == "" {
+		delete(builder.names, speakerID)
+	} else {
+		builder.names[speakerID] = name
+	}
+}
+
+func (builder *TranscriptBuilder) speakerName(speakerID string) string {
+	if name, ok := builder.names[speakerID]; ok {
+		return name
+	}
+	return speakerID
+}
+
+// Transcript returns a snapshot of the collected utterances, merged into turns.
+func (builder *TranscriptBuilder) Transcript() Transcript {
+	builder.mu.Lock()
+	defer builder.mu.Unlock()
+	utterances := append([]TranscriptUtterance(nil), builder.utterances...)
+	sort.SliceStable(utterances, func(i, j int) bool {
+		return utterances[i].Offset < utterances[j].Offset
+	})
+
+	var transcript Transcript
+	stats := make(map[string]*SpeakerStats)
+	var order []string
+	for _, utterance := range utterances {
+		speaker, ok := stats[utterance.SpeakerID]
+		if !ok {
+			speaker = &SpeakerStats{SpeakerID: utterance.SpeakerID, Speaker: builder.speakerName(utterance.SpeakerID)}
+			stats[utterance.SpeakerID] = speaker
+			order = append(order, utterance.SpeakerID)
+		}
+		speaker.TalkTime += utterance.Duration
+		speaker.Words += len(strings.Fields(utterance.Text))
+		speaker.Utterances++
+
+		last := len(transcript.Turns) - 1
+		if last >= 0 && transcript.Turns[last].SpeakerID == utterance.SpeakerID &&
+			(builder.MaxTurnGap == 0 || utterance.Offset-transcript.Turns[last].end() <= builder.MaxTurnGap) {
+			turn := &transcript.Turns[last]
+			turn.Text += " " + utterance.Text
+			if utterance.End() > turn.end() {
+				turn.Duration = utterance.End() - turn.Offset
+			}
+			turn.Utterances = append(turn.Utterances, utterance)
+			continue
+		}
+		speaker.Turns++
+		transcript.Turns = append(transcript.Turns, TranscriptTurn{
+			SpeakerID:  utterance.SpeakerID,
+			Speaker:    speaker.Speaker,
+			Text:       utterance.Text,
+			Offset:     utterance.Offset,
+			Duration:   utterance.Duration,
+			Utterances: []TranscriptUtterance{utterance},
+		})
+	}
+	for _, id := range order {
+		transcript.Speakers = append(transcript.Speakers, *stats[id])
+	}
+	return transcript
+}
+
+// Reset discards the collected utterances, keeping the speaker names.
+func (builder *TranscriptBuilder) Reset() {
+	builder.mu.Lock()
+	defer builder.mu.Unlock()
+	builder.utterances = nil
+}
+
+// Close detaches the builder from the transcriber events.
+func (builder *TranscriptBuilder) Close() {
+	if builder.transcriber != nil {
+		builder.transcriber.Transcribed(nil)
+		builder.transcriber = nil
+	}
+}
+
+func (turn TranscriptTurn) end() time.Duration {
+	return turn.Offset + turn.Duration
+}
+
+// WriteText writes one line per turn: its offset, the speaker and the text.
+func (transcript Transcript) WriteText(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	for _, turn := range transcript.Turns {
+		fmt.Fprintf(writer, "[%s] %s: %s\n", formatTranscriptTime(turn.Offset), turn.Speaker, turn.Text)
+	}
+	return writer.Flush()
+}
+
+// WriteMarkdown writes one paragraph per turn, followed by a table of the speaker statistics.
+func (transcript Transcript) WriteMarkdown(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	for _, turn := range transcript.Turns {
+		fmt.Fprintf(writer, "**%s** (%s): %s\n\n", escapeMarkdown(turn.Speaker), formatTranscriptTime(turn.Offset), escapeMarkdown(turn.Text))
+	}
+	if len(transcript.Speakers) > 0 {
+		fmt.Fprint(writer, "| Speaker | Talk time | Words | Turns |\n| --- | --- | --- | --- |\n")
+		for _, speaker := range transcript.Speakers {
+			fmt.Fprintf(writer, "| %s | %s | %d | %d |\n", escapeMarkdown(speaker.Speaker), formatTranscriptTime(speaker.TalkTime), speaker.Words, speaker.Turns)
+		}
+	}
+	return writer.Flush()
+}
+
+// WriteJSON writes the turns, with their utterances, and the speaker statistics as JSON. Offsets and durations are
+// written in milliseconds.
+func (transcript Transcript) WriteJSON(w io.Writer) error {
+	type utterance struct {
+		ResultID   string  `json:"resultId,omitempty"`
+		OffsetMs   float64 `json:"offsetMs"`
+		DurationMs float64 `json:"durationMs"`
+		Text       string  `json:"text"`
+	}
+	type turn struct {
+		SpeakerID  string      `json:"speakerId"`
+		Speaker    string      `json:"speaker"`
+		OffsetMs   float64     `json:"offsetMs"`
+		DurationMs float64     `json:"durationMs"`
+		Text       string      `json:"text"`
+		Utterances []utterance `json:"utterances"`
+	}
+	type speaker struct {
+		SpeakerID  string  `json:"speakerId"`
+		Speaker    string  `json:"speaker"`
+		TalkTimeMs float64 `json:"talkTimeMs"`
+		Words      int     `json:"words"`
+		Turns      int     `json:"turns"`
+		Utterances int     `json:"utterances"`
+	}
+	doc := struct {
+		Turns    []turn    `json:"turns"`
+		Speakers []speaker `json:"speakers"`
+	}{
+		Turns:    make([]turn, 0, len(transcript.Turns)),
+		Speakers: make([]speaker, 0, len(transcript.Speakers)),
+	}
+	for _, t := range transcript.Turns {
+		utterances := make([]utterance, 0, len(t.Utterances))
+		for _, u := range t.Utterances {
+			utterances = append(utterances, utterance{ResultID: u.ResultID, OffsetMs: durationToMs(u.Offset), DurationMs: durationToMs(u.Duration), Text: u.Text})
+		}
+		doc.Turns = append(doc.Turns, turn{
+			SpeakerID:  t.SpeakerID,
+			Speaker:    t.Speaker,
+			OffsetMs:   durationToMs(t.Offset),
+			DurationMs: durationToMs(t.Duration),
+			Text:       t.Text,
+			Utterances: utterances,
+		})
+	}
+	for _, s := range transcript.Speakers {
+		doc.Speakers = append(doc.Speakers, speaker{
+			SpeakerID:  s.SpeakerID,
+			Speaker:    s.Speaker,
+			TalkTimeMs: durationToMs(s.TalkTime),
+			Words:      s.Words,
+			Turns:      s.Turns,
+			Utterances: s.Utterances,
+		})
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// WriteRTTM writes one NIST RTTM SPEAKER line per utterance, for diarization evaluation tools such as dscore or
+// pyannote.metrics. fileID identifies the audio file; spaces in it and in speaker names are replaced by underscores.
+// Utterances are written rather than turns, so that pauses within a turn are not counted as speech.
+func (transcript Transcript) WriteRTTM(w io.Writer, fileID string) error {
+	writer := bufio.NewWriter(w)
+	fileID = rttmField(fileID)
+	for _, turn := range transcript.Turns {
+		speaker := rttmField(turn.Speaker)
+		for _, utterance := range turn.Utterances {
+			fmt.Fprintf(writer, "SPEAKER %s 1 %.3f %.3f <NA> <NA> %s <NA> <NA>\n", fileID, utterance.Offset.Seconds(), utterance.Duration.Seconds(), speaker)
+		}
+	}
+	return writer.Flush()
+}
+
+func rttmField(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return "<NA>"
+	}
+	return strings.Join(fields, "_")
+}
+
+func formatTranscriptTime(d time.Duration) string {
+	ms := int64(d / time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "|", `\|`)
+
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+func transcribedEvent(speakerID string, text string, offset time.Duration, duration time.Duration) ConversationTranscriptionEventArgs {
+	var event ConversationTranscriptionEventArgs
+	event.Result.SpeakerID = speakerID
+	event.Result.Reason = common.RecognizedSpeech
+	event.Result.Text = text
+	event.Result.Offset = offset
+	event.Result.Duration = duration
+	return event
+}
+
+func newTestTranscript() *TranscriptBuilder {
+	builder := NewTranscriptBuilder(nil, map[string]string{"Guest-1": "Alice Smith"})
+	builder.OnTranscribed(transcribedEvent("Guest-2", "Fine, thanks.", 4*time.Second, time.Second))
+	builder.OnTranscribed(transcribedEvent("Guest-1", "Hello Bob.", 0, time.Second))
+	builder.OnTranscribed(transcribedEvent("Guest-1", "How are you?", 1500*time.Millisecond, 1500*time.Millisecond))
+	builder.OnTranscribed(transcribedEvent("Guest-2", "", 6*time.Second, time.Second))
+	builder.OnTranscribed(transcribedEvent("Guest-1", "Great_news *today*.", 10*time.Second, 2*time.Second))
+	return builder
+}
+
+func TestTranscriptBuilderMergesTurns(t *testing.T) {
+	builder := newTestTranscript()
+	defer builder.Close()
+	transcript := builder.Transcript()
+	if len(transcript.Turns) != 3 {
+		t.Fatal("Unexpected turns: ", transcript.Turns)
+	}
+	first := transcript.Turns[0]
+	if first.Speaker != "Alice Smith" || first.Text != "Hello Bob. How are you?" || first.Duration != 3*time.Second || len(first.Utterances) != 2 {
+		t.Error("Unexpected first turn: ", first)
+	}
+	if transcript.Turns[1].Speaker != "Guest-2" || transcript.Turns[1].Offset != 4*time.Second {
+		t.Error("Unexpected second turn: ", transcript.Turns[1])
+	}
+	if len(transcript.Speakers) != 2 {
+		t.Fatal("Unexpected speakers: ", transcript.Speakers)
+	}
+	alice := transcript.Speakers[0]
+	if alice.SpeakerID != "Guest-1" || alice.TalkTime != 4500*time.Millisecond || alice.Words != 7 || alice.Turns != 2 || alice.Utterances != 3 {
+		t.Error("Unexpected speaker stats: ", alice)
+	}
+
+	builder.SetSpeakerName("Guest-2", "Bob")
+	builder.MaxTurnGap = time.Second
+	builder.Add(TranscriptUtterance{SpeakerID: "Guest-1", Text: "Bye.", Offset: 20 * time.Second, Duration: time.Second})
+	transcript = builder.Transcript()
+	if len(transcript.Turns) != 4 || transcript.Turns[1].Speaker != "Bob" {
+		t.Error("Unexpected turns: ", transcript.Turns)
+	}
+	builder.Reset()
+	if len(builder.Transcript().Turns) != 0 {
+		t.Error("Transcript not reset")
+	}
+}
+
+func TestTranscriptExports(t *testing.T) {
+	transcript := newTestTranscript().Transcript()
+
+	var text bytes.Buffer
+	if err := transcript.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(text.String(), "[00:00:00.000] Alice Smith: Hello Bob. How are you?\n[00:00:04.000] Guest-2: Fine, thanks.\n") {
+		t.Error("Unexpected text: ", text.String())
+	}
+
+	var markdown bytes.Buffer
+	if err := transcript.WriteMarkdown(&markdown); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(markdown.String(), `**Alice Smith** (00:00:10.000): Great\_news \*today\*.`) ||
+		!strings.Contains(markdown.String(), "| Alice Smith | 00:00:04.500 | 7 | 2 |") {
+		t.Error("Unexpected markdown: ", markdown.String())
+	}
+
+	var rttm bytes.Buffer
+	if err := transcript.WriteRTTM(&rttm, "meeting 1"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(rttm.String()), "\n")
+	if len(lines) != 4 || lines[1] != "SPEAKER meeting_1 1 1.500 1.500 <NA> <NA> Alice_Smith <NA> <NA>" {
+		t.Error("Unexpected RTTM: ", rttm.String())
+	}
+
+	var doc struct {
+		Turns []struct {
+			Speaker    string
+			OffsetMs   float64
+			Utterances []struct{ Text string }
+		}
+		Speakers []struct {
+			SpeakerID  string
+			TalkTimeMs float64
+			Words      int
+		}
+	}
+	var encoded bytes.Buffer
+	if err := transcript.WriteJSON(&encoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(encoded.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Turns) != 3 || doc.Turns[1].OffsetMs != 4000 || len(doc.Turns[0].Utterances) != 2 {
+		t.Error("Unexpected JSON turns: ", encoded.String())
+	}
+	if len(doc.Speakers) != 2 || doc.Speakers[1].SpeakerID != "Guest-2" || doc.Speakers[1].TalkTimeMs != 1000 || doc.Speakers[1].Words != 2 {
+		t.Error("Unexpected JSON speakers: ", encoded.String())
+	}
+}
@@ -5,6 +5,7 @@ package speech
 
 import (
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/diagnostics/logging"
 )
 
 // #include <stdlib.h>
@@ -64,6 +65,10 @@ type ConversationTranscriptionCanceledEventArgs struct {
 	Reason       common.CancellationReason    // Direct field instead of nested object
 	ErrorCode    common.CancellationErrorCode // Direct field instead of nested object
 	ErrorDetails string                       // Direct field instead of nested object
+
+	// SessionLog is the log captured for the session when it was canceled with an error and a session log capture
+	// is enabled (see logging.EnableSessionLogCapture).
+	SessionLog *logging.SessionLog
 }
 
 // NewConversationTranscriptionCanceledEventArgsFromHandle creates a ConversationTranscriptionCanceledEventArgs from an event handle
@@ -94,10 +99,18 @@ func NewConversationTranscriptionCanceledEventArgsFromHandle(handle common.SPXHa
 	}
 	event.ErrorCode = (common.CancellationErrorCode)(cCode)
 	event.ErrorDetails = event.Result.Properties.GetProperty(common.SpeechServiceResponseJSONErrorDetails, "")
+	if event.Reason == common.Error {
+		event.SessionLog = logging.CapturedSessionLog(event.SessionID)
+	}
 	
 	return event, nil
 }
 
+// Err returns the cancellation as an error.
+func (event ConversationTranscriptionCanceledEventArgs) Err() *common.CancellationError {
+	return &common.CancellationError{Reason: event.Reason, ErrorCode: event.ErrorCode, ErrorDetails: event.ErrorDetails, SessionID: event.SessionID}
+}
+
 // Close releases the associated resources.
 func (event ConversationTranscriptionCanceledEventArgs) Close() {
 	event.ConversationTranscriptionEventArgs.Close()
@@ -23,6 +23,17 @@ import "C"
 // device and the corresponding native runtime extensions. This is a Limited Access feature.
 type EmbeddedSpeechConfig struct {
 	*SpeechConfig
+	paths            []string
+	recognitionModel *embeddedModel
+	synthesisVoice   *embeddedModel
+	keywordModel     *embeddedModel
+	translationModel *embeddedModel
+}
+
+// embeddedModel is a model or a voice set on an EmbeddedSpeechConfig, recorded so that Clone can set it again.
+type embeddedModel struct {
+	name    string
+	license string
 }
 
 // GetSpeechConfig returns the underlying SpeechConfig. Use it with the existing recognizer and synthesizer
@@ -69,7 +80,12 @@ func NewEmbeddedSpeechConfigFromPaths(paths []string) (*EmbeddedSpeechConfig, er
 			return nil, common.NewCarbonError(ret)
 		}
 	}
-	return newEmbeddedSpeechConfigFromHandle(handle)
+	config, err := newEmbeddedSpeechConfigFromHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+	config.paths = append([]string(nil), paths...)
+	return config, nil
 }
 
 // GetSpeechRecognitionModels returns the list of embedded speech recognition models available in the
@@ -106,6 +122,7 @@ func (config *EmbeddedSpeechConfig) SetSpeechRecognitionModel(name string, licen
 	if ret != C.SPX_NOERROR {
 		return common.NewCarbonError(ret)
 	}
+	config.recognitionModel = &embeddedModel{name, license}
 	return nil
 }
 
@@ -125,9 +142,20 @@ func (config *EmbeddedSpeechConfig) SetSpeechSynthesisVoice(name string, license
 	if ret != C.SPX_NOERROR {
 		return common.NewCarbonError(ret)
 	}
+	config.synthesisVoice = &embeddedModel{name, license}
 	return nil
 }
 
+// SetSpeechSynthesisOutputFormat sets the output format for embedded speech synthesis.
+// Embedded voices produce uncompressed PCM audio only, so raw or RIFF PCM formats (e.g. Riff24Khz16BitMonoPcm)
+// are accepted and compressed formats are rejected with an SPXERR_INVALID_ARG error.
+func (config *EmbeddedSpeechConfig) SetSpeechSynthesisOutputFormat(format common.SpeechSynthesisOutputFormat) error {
+	if !format.Describe().IsPCM() {
+		return common.NewCarbonError(uintptr(C.SPXERR_INVALID_ARG))
+	}
+	return config.SpeechConfig.SetSpeechSynthesisOutputFormat(format)
+}
+
 // GetSpeechSynthesisVoiceName returns the voice name for embedded speech synthesis.
 func (config *EmbeddedSpeechConfig) GetSpeechSynthesisVoiceName() string {
 	return config.GetProperty(common.SpeechServiceConnectionSynthOfflineVoice)
@@ -145,6 +173,7 @@ func (config *EmbeddedSpeechConfig) SetKeywordRecognitionModel(name string, lice
 	if ret != C.SPX_NOERROR {
 		return common.NewCarbonError(ret)
 	}
+	config.keywordModel = &embeddedModel{name, license}
 	return nil
 }
 
@@ -187,6 +216,7 @@ func (config *EmbeddedSpeechConfig) SetSpeechTranslationModel(name string, licen
 	if ret != C.SPX_NOERROR {
 		return common.NewCarbonError(ret)
 	}
+	config.translationModel = &embeddedModel{name, license}
 	return nil
 }
 
@@ -194,3 +224,38 @@ func (config *EmbeddedSpeechConfig) SetSpeechTranslationModel(name string, licen
 func (config *EmbeddedSpeechConfig) GetSpeechTranslationModelName() string {
 	return config.GetProperty(common.SpeechTranslationModelName)
 }
+
+// Clone creates a new EmbeddedSpeechConfig with the same model paths, models, voice and settings as config, then
+// applies opts. See SpeechConfig.Clone for the settings that are copied.
+// The returned config must be closed independently of config.
+func (config *EmbeddedSpeechConfig) Clone(opts ...ConfigOption) (*EmbeddedSpeechConfig, error) {
+	builder, err := newCloneBuilder(opts)
+	if err != nil {
+		return nil, err
+	}
+	clone, err := NewEmbeddedSpeechConfigFromPaths(config.paths)
+	if err != nil {
+		return nil, err
+	}
+	for _, model := range []struct {
+		model *embeddedModel
+		set   func(name string, license string) error
+	}{
+		{config.recognitionModel, clone.SetSpeechRecognitionModel},
+		{config.synthesisVoice, clone.SetSpeechSynthesisVoice},
+		{config.keywordModel, clone.SetKeywordRecognitionModel},
+		{config.translationModel, clone.SetSpeechTranslationModel},
+	} {
+		if err == nil && model.model != nil {
+			err = model.set(model.model.name, model.model.license)
+		}
+	}
+	if err == nil {
+		err = config.SpeechConfig.cloneInto(clone.SpeechConfig, builder)
+	}
+	if err != nil {
+		clone.Close()
+		return nil, err
+	}
+	return clone, nil
+}
@@ -5,6 +5,8 @@ package speech
 
 import (
 	"testing"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
 )
 
 func TestEmbeddedConfigFromPath(t *testing.T) {
@@ -123,6 +125,36 @@ func TestEmbeddedConfigTranslationModel(t *testing.T) {
 	}
 }
 
+func TestEmbeddedConfigClone(t *testing.T) {
+	config, err := NewEmbeddedSpeechConfigFromPaths([]string{"models1", "models2"})
+	if err != nil {
+		t.Error("Unexpected error creating embedded speech config: ", err)
+		return
+	}
+	defer config.Close()
+	if err = config.SetSpeechRecognitionModel("en-US model", "license text"); err != nil {
+		t.Error("Unexpected error setting recognition model: ", err)
+	}
+	if err = config.SetSpeechSynthesisVoice("en-US voice name", "license text"); err != nil {
+		t.Error("Unexpected error setting synthesis voice: ", err)
+	}
+	clone, err := config.Clone()
+	if err != nil {
+		t.Error("Unexpected error cloning embedded speech config: ", err)
+		return
+	}
+	defer clone.Close()
+	if len(clone.paths) != 2 || clone.paths[0] != "models1" || clone.paths[1] != "models2" {
+		t.Error("Unexpected model paths: ", clone.paths)
+	}
+	if clone.GetSpeechRecognitionModelName() != "en-US model" {
+		t.Error("Recognition model name not properly cloned")
+	}
+	if clone.GetSpeechSynthesisVoiceName() != "en-US voice name" {
+		t.Error("Synthesis voice name not properly cloned")
+	}
+}
+
 func TestEmbeddedConfigGetSpeechTranslationModels(t *testing.T) {
 	config, err := NewEmbeddedSpeechConfigFromPath("models")
 	if err != nil {
@@ -140,3 +172,18 @@ func TestEmbeddedConfigGetSpeechTranslationModels(t *testing.T) {
 		model.Close()
 	}
 }
+
+func TestEmbeddedConfigSynthesisOutputFormat(t *testing.T) {
+	config, err := NewEmbeddedSpeechConfigFromPath("models")
+	if err != nil {
+		t.Error("Unexpected error creating embedded speech config: ", err)
+		return
+	}
+	defer config.Close()
+	if err = config.SetSpeechSynthesisOutputFormat(common.Riff24Khz16BitMonoPcm); err != nil {
+		t.Error("Unexpected error setting PCM output format: ", err)
+	}
+	if err = config.SetSpeechSynthesisOutputFormat(common.Audio24Khz48KBitRateMonoMp3); err == nil {
+		t.Error("Expected an error when setting a compressed output format")
+	}
+}
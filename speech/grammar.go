@@ -0,0 +1,140 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"unsafe"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// #include <stdlib.h>
+// #include <speechapi_c_grammar.h>
+//
+import "C"
+
+// RecognitionFactorScope is the scope of the recognition factor of a GrammarList.
+type RecognitionFactorScope int
+
+const (
+	// PartialPhrase applies the recognition factor to the grammars matching parts of a phrase.
+	PartialPhrase RecognitionFactorScope = 1
+)
+
+// GrammarListItem is a grammar a GrammarList can reference: a Grammar or a ClassLanguageModel.
+type GrammarListItem interface {
+	grammarHandle() common.SPXHandle
+}
+
+// Grammar is a custom grammar stored in the service, referenced by its storage ID.
+type Grammar struct {
+	handle C.SPXHANDLE
+}
+
+// NewGrammarFromStorageID creates a grammar referencing a custom grammar stored in the service.
+func NewGrammarFromStorageID(storageID string) (*Grammar, error) {
+	var handle C.SPXHANDLE
+	id := C.CString(storageID)
+	defer C.free(unsafe.Pointer(id))
+	ret := uintptr(C.grammar_create_from_storage_id(&handle, id))
+	if ret != C.SPX_NOERROR {
+		return nil, common.NewCarbonError(ret)
+	}
+	grammar := new(Grammar)
+	grammar.handle = handle
+	return grammar, nil
+}
+
+func (grammar *Grammar) grammarHandle() common.SPXHandle {
+	return handle2uintptr(grammar.handle)
+}
+
+// Close releases the associated resources.
+func (grammar *Grammar) Close() {
+	C.grammar_handle_release(grammar.handle)
+}
+
+// ClassLanguageModel is a class-based language model stored in the service, referenced by its storage ID, whose
+// classes are filled by grammars.
+type ClassLanguageModel struct {
+	handle C.SPXHANDLE
+}
+
+// NewClassLanguageModelFromStorageID creates a class language model referencing a model stored in the service.
+func NewClassLanguageModelFromStorageID(storageID string) (*ClassLanguageModel, error) {
+	var handle C.SPXHANDLE
+	id := C.CString(storageID)
+	defer C.free(unsafe.Pointer(id))
+	ret := uintptr(C.class_language_model_from_storage_id(&handle, id))
+	if ret != C.SPX_NOERROR {
+		return nil, common.NewCarbonError(ret)
+	}
+	model := new(ClassLanguageModel)
+	model.handle = handle
+	return model, nil
+}
+
+// AssignClass assigns a grammar to a class of the model, e.g. a grammar of product names to the class ProductName.
+func (model *ClassLanguageModel) AssignClass(className string, grammar *Grammar) error {
+	name := C.CString(className)
+	defer C.free(unsafe.Pointer(name))
+	ret := uintptr(C.class_language_model_assign_class(model.handle, name, grammar.handle))
+	if ret != C.SPX_NOERROR {
+		return common.NewCarbonError(ret)
+	}
+	return nil
+}
+
+func (model *ClassLanguageModel) grammarHandle() common.SPXHandle {
+	return handle2uintptr(model.handle)
+}
+
+// Close releases the associated resources.
+func (model *ClassLanguageModel) Close() {
+	C.grammar_handle_release(model.handle)
+}
+
+// GrammarList is the list of grammars referenced by the recognitions of a recognizer, in addition to its phrase list.
+// Changes apply from the next utterance.
+type GrammarList struct {
+	handle C.SPXHANDLE
+}
+
+// NewGrammarList returns the grammar list of a speech recognizer, a translation recognizer or a conversation
+// transcriber.
+func NewGrammarList(recognizer GrammarRecognizer) (*GrammarList, error) {
+	var handle C.SPXHANDLE
+	ret := uintptr(C.grammar_list_from_recognizer(&handle, uintptr2handle(recognizer.recognizerHandle())))
+	if ret != C.SPX_NOERROR {
+		return nil, common.NewCarbonError(ret)
+	}
+	list := new(GrammarList)
+	list.handle = handle
+	return list, nil
+}
+
+// Add adds a grammar or a class language model to the list. The grammar can be closed afterwards.
+func (list *GrammarList) Add(grammar GrammarListItem) error {
+	ret := uintptr(C.grammar_list_add_grammar(list.handle, uintptr2handle(grammar.grammarHandle())))
+	if ret != C.SPX_NOERROR {
+		return common.NewCarbonError(ret)
+	}
+	return nil
+}
+
+// SetRecognitionFactor sets the weight of the grammars of the list in the given scope. Higher factors bias the
+// recognition more towards the grammars.
+func (list *GrammarList) SetRecognitionFactor(factor float64, scope RecognitionFactorScope) error {
+	ret := uintptr(C.grammar_list_set_recognition_factor(list.handle, C.double(factor),
+		C.GrammarList_RecognitionFactorScope(scope)))
+	if ret != C.SPX_NOERROR {
+		return common.NewCarbonError(ret)
+	}
+	return nil
+}
+
+// Close releases the associated resources.
+func (list *GrammarList) Close() {
+	C.grammar_handle_release(list.handle)
+}
@@ -0,0 +1,285 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
+)
+
+// contextStandIn is a local endpoint standing in for the service. It accepts the WebSocket connections of recognizers
+// and captures the speech.context message they send at the start of a turn, which carries their phrase list and
+// grammars, then closes the connection.
+type contextStandIn struct {
+	listener net.Listener
+	contexts chan string
+}
+
+func newContextStandIn(t *testing.T) *contextStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	standIn := &contextStandIn{listener: listener, contexts: make(chan string, 10)}
+	go http.Serve(listener, http.HandlerFunc(standIn.serve))
+	return standIn
+}
+
+func (standIn *contextStandIn) endpoint() string {
+	return "ws://" + standIn.listener.Addr().String() + "/speech/recognition/conversation/cognitiveservices/v1"
+}
+
+func (standIn *contextStandIn) Close() {
+	standIn.listener.Close()
+}
+
+func (standIn *contextStandIn) serve(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	hijacker, ok := w.(http.Hijacker)
+	if key == "" || !ok {
+		http.Error(w, "not a WebSocket handshake", http.StatusBadRequest)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	sum := sha1.Sum([]byte(key + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	if rw.Flush() != nil {
+		return
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	var message []byte
+	for {
+		opcode, fin, payload, err := readTestFrame(rw.Reader)
+		if err != nil || opcode == 8 {
+			return
+		}
+		if opcode >= 8 {
+			// Ping and pong frames.
+			continue
+		}
+		message = append(message, payload...)
+		if !fin {
+			continue
+		}
+		if body, ok := speechContextBody(string(message)); ok {
+			standIn.contexts <- body
+			return
+		}
+		message = nil
+	}
+}
+
+// readTestFrame reads a WebSocket frame, unmasking its payload.
+func readTestFrame(r *bufio.Reader) (opcode byte, fin bool, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(r, header[:]); err != nil {
+		return
+	}
+	fin, opcode = header[0]&0x80 != 0, header[0]&0x0f
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err = io.ReadFull(r, extended[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err = io.ReadFull(r, extended[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	var mask [4]byte
+	if header[1]&0x80 != 0 {
+		if _, err = io.ReadFull(r, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// speechContextBody returns the body of a text message whose Path header is speech.context.
+func speechContextBody(message string) (string, bool) {
+	end := strings.Index(message, "\r\n\r\n")
+	if end < 0 {
+		return "", false
+	}
+	for _, line := range strings.Split(message[:end], "\r\n") {
+		colon := strings.Index(line, ":")
+		if colon > 0 && strings.EqualFold(strings.TrimSpace(line[:colon]), "Path") &&
+			strings.TrimSpace(line[colon+1:]) == "speech.context" {
+			return message[end+4:], true
+		}
+	}
+	return "", false
+}
+
+// captureSpeechContext starts a recognition against a stand-in after configuring the recognizer, and returns the
+// speech.context payload it sends.
+func captureSpeechContext(t *testing.T, configure func(recognizer *SpeechRecognizer) error) string {
+	standIn := newContextStandIn(t)
+	defer standIn.Close()
+	config, err := NewSpeechConfigFromEndpointWithSubscription(standIn.endpoint(), "test-key")
+	if err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	defer config.Close()
+	audioConfig, err := audio.NewAudioConfigFromWavFileInput("../test_files/peloozoid.wav")
+	if err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	defer audioConfig.Close()
+	recognizer, err := NewSpeechRecognizerFromConfig(config, audioConfig)
+	if err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	defer recognizer.Close()
+	if err = configure(recognizer); err != nil {
+		t.Fatal("Got an error configuring the recognizer: ", err)
+	}
+	outcomes := recognizer.RecognizeOnceAsync()
+	defer func() {
+		// The stand-in closes the connection, which cancels the recognition.
+		outcome := <-outcomes
+		outcome.Close()
+	}()
+	select {
+	case payload := <-standIn.contexts:
+		var decoded map[string]interface{}
+		if err := json.Unmarshal([]byte(payload), &decoded); err != nil {
+			t.Error("Invalid speech.context payload: ", payload)
+		}
+		return payload
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout waiting for the speech.context message")
+		return ""
+	}
+}
+
+// numberPaths returns the paths of the JSON numbers equal to value in a payload, e.g. dgi.Groups[0].Weight.
+func numberPaths(payload string, value float64) []string {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(payload), &decoded); err != nil {
+		return nil
+	}
+	var paths []string
+	var walk func(path string, node interface{})
+	walk = func(path string, node interface{}) {
+		switch node := node.(type) {
+		case float64:
+			if node == value {
+				paths = append(paths, path)
+			}
+		case map[string]interface{}:
+			for key, child := range node {
+				walk(strings.TrimPrefix(path+"."+key, "."), child)
+			}
+		case []interface{}:
+			for i, child := range node {
+				walk(fmt.Sprintf("%s[%d]", path, i), child)
+			}
+		}
+	}
+	walk("", decoded)
+	return paths
+}
+
+func addGrammars(recognizer *SpeechRecognizer, factor float64, grammars ...GrammarListItem) error {
+	list, err := NewGrammarList(recognizer)
+	if err != nil {
+		return err
+	}
+	defer list.Close()
+	for _, grammar := range grammars {
+		if err = list.Add(grammar); err != nil {
+			return err
+		}
+	}
+	if factor != 0 {
+		return list.SetRecognitionFactor(factor, PartialPhrase)
+	}
+	return nil
+}
+
+func TestGrammarListSpeechContext(t *testing.T) {
+	grammar, err := NewGrammarFromStorageID("product-grammar-id")
+	if err != nil {
+		t.Fatal("Grammar creation failed: ", err)
+	}
+	defer grammar.Close()
+	payload := captureSpeechContext(t, func(recognizer *SpeechRecognizer) error {
+		return addGrammars(recognizer, 0, grammar)
+	})
+	if !strings.Contains(payload, "product-grammar-id") {
+		t.Error("Storage ID missing from the speech.context payload: ", payload)
+	}
+	weighted := captureSpeechContext(t, func(recognizer *SpeechRecognizer) error {
+		return addGrammars(recognizer, 1.5, grammar)
+	})
+	if paths := numberPaths(payload, 1.5); len(paths) != 0 {
+		t.Error("Unexpected recognition factor in the unweighted speech.context payload: ", paths)
+	}
+	if paths := numberPaths(weighted, 1.5); !strings.Contains(weighted, "product-grammar-id") || len(paths) == 0 {
+		t.Error("Recognition factor missing from the speech.context payload: ", weighted)
+	}
+}
+
+func TestClassLanguageModelSpeechContext(t *testing.T) {
+	model, err := NewClassLanguageModelFromStorageID("class-model-id")
+	if err != nil {
+		t.Fatal("Class language model creation failed: ", err)
+	}
+	defer model.Close()
+	names, err := NewGrammarFromStorageID("product-names-id")
+	if err != nil {
+		t.Fatal("Grammar creation failed: ", err)
+	}
+	defer names.Close()
+	if err = model.AssignClass("ProductName", names); err != nil {
+		t.Fatal("AssignClass failed: ", err)
+	}
+	payload := captureSpeechContext(t, func(recognizer *SpeechRecognizer) error {
+		return addGrammars(recognizer, 0, model)
+	})
+	for _, want := range []string{"class-model-id", "ProductName", "product-names-id"} {
+		if !strings.Contains(payload, want) {
+			t.Error(want, " missing from the speech.context payload: ", payload)
+		}
+	}
+}
+
+func TestPhraseListSpeechContext(t *testing.T) {
+	payload := captureSpeechContext(t, func(recognizer *SpeechRecognizer) error {
+		return NewPhraseList("peloozoid", "Contoso  Cloud", "contoso cloud").ApplyTo(recognizer)
+	})
+	if !strings.Contains(payload, "peloozoid") || strings.Count(payload, "Contoso Cloud") != 1 {
+		t.Error("Phrases missing from the speech.context payload: ", payload)
+	}
+}
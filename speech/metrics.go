@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recorder"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
+)
+
+// #include <speechapi_c_common.h>
+import "C"
+
+// The helpers below report to the metrics hook. The recognized and canceled events of continuous recognitions are
+// connected by connectRecordedEvents when a hook is set; partial results are only recorded when a Recognizing handler
+// is connected.
+
+func recordRecognitionStarted(recognizer string, handle C.SPXHANDLE) {
+	recorder.RecognitionStarted(recognizer, handleKey(handle))
+}
+
+func recordRecognitionStopped(handle C.SPXHANDLE) {
+	recorder.RecognitionStopped(handleKey(handle))
+}
+
+func recordPartial(recognizer string, handle C.SPXHANDLE) {
+	recorder.PartialReceived(recognizer, handleKey(handle))
+}
+
+// recordRecognitionResult records a final or canceled recognition result.
+func recordRecognitionResult(recognizer string, result *SpeechRecognitionResult) {
+	if result == nil || metrics.CurrentHook() == nil {
+		return
+	}
+	switch result.Reason {
+	case common.RecognizedSpeech, common.TranslatedSpeech, common.RecognizedKeyword, common.NoMatch:
+		latency := result.Properties.GetProperty(common.SpeechServiceResponseRecognitionLatencyMs, "")
+		recorder.Recognized(recognizer, result.ResultID, latency, result.Duration)
+	case common.Canceled:
+		if details, err := NewCancellationDetailsFromSpeechRecognitionResult(result); err == nil {
+			recordRecognitionCanceled(recognizer, result.ResultID, details.Err())
+		}
+	}
+}
+
+func recordRecognitionCanceled(recognizer string, resultID string, canceled *common.CancellationError) {
+	if canceled.Reason == common.Error {
+		recorder.Canceled(recognizer, resultID, canceled.ErrorCode.String())
+	}
+}
+
+// recordSynthesisResult records a completed or canceled synthesis result.
+func recordSynthesisResult(result *SpeechSynthesisResult) {
+	if result == nil || metrics.CurrentHook() == nil {
+		return
+	}
+	switch result.Reason {
+	case common.SynthesizingAudioCompleted:
+		recorder.Synthesized(result.ResultID, recorder.SynthesisLatencies{
+			FirstByteMs: result.Properties.GetProperty(common.SpeechServiceResponseSynthesisFirstByteLatencyMs, ""),
+			FinishMs:    result.Properties.GetProperty(common.SpeechServiceResponseSynthesisFinishLatencyMs, ""),
+			NetworkMs:   result.Properties.GetProperty(common.SpeechServiceResponseSynthesisNetworkLatencyMs, ""),
+			ServiceMs:   result.Properties.GetProperty(common.SpeechServiceResponseSynthesisServiceLatencyMs, ""),
+			UnderrunMs:  result.Properties.GetProperty(common.SpeechServiceResponseSynthesisUnderrunTimeMs, ""),
+		})
+	case common.Canceled:
+		details, err := NewCancellationDetailsFromSpeechSynthesisResult(result)
+		if err == nil && details.Reason == common.Error {
+			recorder.Canceled(metrics.SpeechSynthesizer, result.ResultID, details.ErrorCode.String())
+		}
+	}
+}
@@ -0,0 +1,531 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// PhraseListLimits are the limits a phrase list is validated against before it is applied.
+type PhraseListLimits struct {
+	// MaxPhrases is the maximum number of phrases of a list.
+	MaxPhrases int
+
+	// MaxPhraseLength is the maximum length of a phrase, in characters.
+	MaxPhraseLength int
+}
+
+// DefaultPhraseListLimits are the limits of the service for the phrase list of a recognition.
+var DefaultPhraseListLimits = PhraseListLimits{MaxPhrases: 500, MaxPhraseLength: 100}
+
+// Bounds of the weight of a phrase list, as accepted by PhraseListGrammar.SetWeight.
+const (
+	MinPhraseListWeight     = 0.0
+	MaxPhraseListWeight     = 2.0
+	DefaultPhraseListWeight = 1.0
+)
+
+// PhraseList is a list of phrases, such as names or domain terms, biasing the recognition towards them. Phrases are
+// normalized, i.e. trimmed with their inner spaces collapsed, and deduplicated ignoring case, keeping the first
+// spelling. A PhraseList is a value: its methods return modified copies, and it can be applied to any number of
+// recognizers with ApplyTo.
+type PhraseList struct {
+	phrases []string
+	weight  *float64
+}
+
+// NewPhraseList creates a phrase list of the given phrases, with the default weight.
+func NewPhraseList(phrases ...string) PhraseList {
+	return PhraseList{}.Add(phrases...)
+}
+
+// normalizePhrase trims a phrase and collapses its inner spaces.
+func normalizePhrase(phrase string) string {
+	return strings.Join(strings.Fields(phrase), " ")
+}
+
+func phraseKey(phrase string) string {
+	return strings.ToLower(phrase)
+}
+
+// Add returns the list with the given phrases appended, except empty ones and those already in the list.
+func (list PhraseList) Add(phrases ...string) PhraseList {
+	seen := make(map[string]bool, len(list.phrases)+len(phrases))
+	for _, phrase := range list.phrases {
+		seen[phraseKey(phrase)] = true
+	}
+	added := make([]string, len(list.phrases), len(list.phrases)+len(phrases))
+	copy(added, list.phrases)
+	for _, phrase := range phrases {
+		phrase = normalizePhrase(phrase)
+		if key := phraseKey(phrase); phrase != "" && !seen[key] {
+			seen[key] = true
+			added = append(added, phrase)
+		}
+	}
+	list.phrases = added
+	return list
+}
+
+// Remove returns the list without the given phrases, compared as Add does.
+func (list PhraseList) Remove(phrases ...string) PhraseList {
+	removed := make(map[string]bool, len(phrases))
+	for _, phrase := range phrases {
+		removed[phraseKey(normalizePhrase(phrase))] = true
+	}
+	kept := make([]string, 0, len(list.phrases))
+	for _, phrase := range list.phrases {
+		if !removed[phraseKey(phrase)] {
+			kept = append(kept, phrase)
+		}
+	}
+	list.phrases = kept
+	return list
+}
+
+// Merge returns the list with the phrases of other appended, keeping the weight of list.
+func (list PhraseList) Merge(other PhraseList) PhraseList {
+	return list.Add(other.phrases...)
+}
+
+// Contains reports whether the list contains a phrase, compared as Add does.
+func (list PhraseList) Contains(phrase string) bool {
+	key := phraseKey(normalizePhrase(phrase))
+	for _, existing := range list.phrases {
+		if phraseKey(existing) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Phrases returns a copy of the phrases of the list.
+func (list PhraseList) Phrases() []string {
+	return append([]string(nil), list.phrases...)
+}
+
+// Len returns the number of phrases of the list.
+func (list PhraseList) Len() int {
+	return len(list.phrases)
+}
+
+// Weight returns the weight of the list, DefaultPhraseListWeight unless set with WithWeight.
+func (list PhraseList) Weight() float64 {
+	if list.weight == nil {
+		return DefaultPhraseListWeight
+	}
+	return *list.weight
+}
+
+// WithWeight returns the list with the given weight, between MinPhraseListWeight and MaxPhraseListWeight. Higher
+// weights bias the recognition more towards the phrases.
+func (list PhraseList) WithWeight(weight float64) PhraseList {
+	list.weight = &weight
+	return list
+}
+
+// Validate checks the list against DefaultPhraseListLimits.
+func (list PhraseList) Validate() error {
+	return list.ValidateLimits(DefaultPhraseListLimits)
+}
+
+// ValidateLimits checks the weight of the list, and its phrases against the given limits, zero limits being ignored.
+func (list PhraseList) ValidateLimits(limits PhraseListLimits) error {
+	if weight := list.Weight(); weight < MinPhraseListWeight || weight > MaxPhraseListWeight {
+		return &PhraseListError{Index: -1, Message: fmt.Sprintf("weight %v is not between %v and %v",
+			weight, MinPhraseListWeight, MaxPhraseListWeight)}
+	}
+	if limits.MaxPhrases > 0 && len(list.phrases) > limits.MaxPhrases {
+		return &PhraseListError{Index: -1, Message: fmt.Sprintf("%d phrases exceed the limit of %d",
+			len(list.phrases), limits.MaxPhrases)}
+	}
+	for i, phrase := range list.phrases {
+		if length := utf8.RuneCountInString(phrase); limits.MaxPhraseLength > 0 && length > limits.MaxPhraseLength {
+			return &PhraseListError{Index: i, Phrase: phrase, Message: fmt.Sprintf("%d characters exceed the limit of %d",
+				length, limits.MaxPhraseLength)}
+		}
+	}
+	return nil
+}
+
+// ApplyTo validates the list and replaces the phrase list of a recognizer with it, from its next utterance.
+func (list PhraseList) ApplyTo(recognizer GrammarRecognizer) error {
+	if err := list.Validate(); err != nil {
+		return err
+	}
+	grammar, err := NewPhraseListGrammar(recognizer)
+	if err != nil {
+		return err
+	}
+	defer grammar.Close()
+	if err = grammar.Clear(); err != nil {
+		return err
+	}
+	for _, phrase := range list.phrases {
+		if err = grammar.AddPhrase(phrase); err != nil {
+			return err
+		}
+	}
+	return grammar.SetWeight(list.Weight())
+}
+
+// PhraseListError reports a phrase list that failed validation. It matches common.ErrInvalidArg with errors.Is.
+type PhraseListError struct {
+	// Index is the index of the invalid phrase, or -1 when the list as a whole is invalid.
+	Index   int
+	Phrase  string
+	Message string
+}
+
+func (e *PhraseListError) Error() string {
+	if e.Index < 0 {
+		return "invalid phrase list: " + e.Message
+	}
+	return fmt.Sprintf("invalid phrase %d %q: %s", e.Index, e.Phrase, e.Message)
+}
+
+// Is reports whether target is common.ErrInvalidArg.
+func (e *PhraseListError) Is(target error) bool {
+	return common.ErrInvalidArg.Is(target)
+}
+
+// phraseListJSON is the JSON representation of a phrase list.
+type phraseListJSON struct {
+	Phrases []string `json:"phrases"`
+	Weight  *float64 `json:"weight,omitempty"`
+}
+
+// MarshalJSON encodes the list as an object with its phrases and its weight, if set.
+func (list PhraseList) MarshalJSON() ([]byte, error) {
+	phrases := list.phrases
+	if phrases == nil {
+		phrases = []string{}
+	}
+	return json.Marshal(phraseListJSON{Phrases: phrases, Weight: list.weight})
+}
+
+// UnmarshalJSON decodes an object as encoded by MarshalJSON, or an array of phrases.
+func (list *PhraseList) UnmarshalJSON(data []byte) error {
+	var decoded phraseListJSON
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &decoded.Phrases); err != nil {
+			return err
+		}
+	} else if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*list = NewPhraseList(decoded.Phrases...)
+	if decoded.Weight != nil {
+		*list = list.WithWeight(*decoded.Weight)
+	}
+	return nil
+}
+
+// ReadPhraseListText reads a phrase list of one phrase per line. Empty lines and lines starting with # are skipped.
+func ReadPhraseListText(r io.Reader) (PhraseList, error) {
+	var phrases []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line != "" && !strings.HasPrefix(line, "#") {
+			phrases = append(phrases, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return PhraseList{}, err
+	}
+	return NewPhraseList(phrases...), nil
+}
+
+// ReadPhraseListCSV reads a phrase list from the first column of CSV records. A header record whose first field is
+// "phrase" or "phrases" is skipped, as are records starting with #.
+func ReadPhraseListCSV(r io.Reader) (PhraseList, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	var phrases []string
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return PhraseList{}, err
+		}
+		field := strings.TrimPrefix(record[0], "\ufeff")
+		if header := strings.ToLower(strings.TrimSpace(field)); first && (header == "phrase" || header == "phrases") {
+			continue
+		}
+		phrases = append(phrases, field)
+	}
+	return NewPhraseList(phrases...), nil
+}
+
+// ReadPhraseListJSON reads a phrase list from a JSON array of phrases, or an object with phrases and weight fields.
+func ReadPhraseListJSON(r io.Reader) (PhraseList, error) {
+	var list PhraseList
+	err := json.NewDecoder(r).Decode(&list)
+	return list, err
+}
+
+// LoadPhraseList reads a phrase list from a file, in the format of its extension: .csv for CSV, .json for JSON, and
+// text otherwise.
+func LoadPhraseList(path string) (PhraseList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return PhraseList{}, err
+	}
+	defer file.Close()
+	var list PhraseList
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		list, err = ReadPhraseListCSV(file)
+	case ".json":
+		list, err = ReadPhraseListJSON(file)
+	default:
+		list, err = ReadPhraseListText(file)
+	}
+	if err != nil {
+		return PhraseList{}, fmt.Errorf("reading phrase list %s: %w", path, err)
+	}
+	return list, nil
+}
+
+// WriteText writes the phrases of the list, one per line. The weight is not written.
+func (list PhraseList) WriteText(w io.Writer) error {
+	for _, phrase := range list.phrases {
+		if _, err := fmt.Fprintln(w, phrase); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the phrases of the list as CSV records of one field, after a phrase header. The weight is not
+// written.
+func (list PhraseList) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"phrase"})
+	for _, phrase := range list.phrases {
+		writer.Write([]string{phrase})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the list as an indented JSON object with its phrases and its weight, if set.
+func (list PhraseList) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(list)
+}
+
+// Save writes the list to a file, in the format of its extension as LoadPhraseList reads it.
+func (list PhraseList) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		err = list.WriteCSV(file)
+	case ".json":
+		err = list.WriteJSON(file)
+	default:
+		err = list.WriteText(file)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// GrammarRecognizer is a recognizer whose recognitions can be biased with phrase lists: a SpeechRecognizer, a
+// TranslationRecognizer or a ConversationTranscriber.
+type GrammarRecognizer interface {
+	recognizerHandle() common.SPXHandle
+}
+
+// PhraseListProfiles is a set of named phrase lists, e.g. "medical" or "product-names", of which one at a time, the
+// active profile, is applied to the attached recognizers. Activating another profile replaces the phrase list of the
+// recognizers from their next utterance, so that profiles can be swapped while they recognize. It is safe for
+// concurrent use.
+type PhraseListProfiles struct {
+	mu          sync.Mutex
+	profiles    map[string]PhraseList
+	active      string
+	recognizers []GrammarRecognizer
+	apply       func(recognizer GrammarRecognizer, list PhraseList) error
+}
+
+// NewPhraseListProfiles creates an empty set of profiles, without active profile.
+func NewPhraseListProfiles() *PhraseListProfiles {
+	return &PhraseListProfiles{
+		profiles: make(map[string]PhraseList),
+		apply: func(recognizer GrammarRecognizer, list PhraseList) error {
+			return list.ApplyTo(recognizer)
+		},
+	}
+}
+
+// LoadPhraseListProfiles creates a set of profiles from the phrase list files of a directory, with the .txt, .csv or
+// .json extension, each named by its file name without extension.
+func LoadPhraseListProfiles(dir string) (*PhraseListProfiles, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	profiles := NewPhraseListProfiles()
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file.Name()))
+		if file.IsDir() || (ext != ".txt" && ext != ".csv" && ext != ".json") {
+			continue
+		}
+		list, err := LoadPhraseList(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if err := profiles.Set(strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())), list); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+// Save writes each profile to a JSON file of a directory, named by the profile, as LoadPhraseListProfiles reads them.
+func (profiles *PhraseListProfiles) Save(dir string) error {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	for name, list := range profiles.profiles {
+		if err := list.Save(filepath.Join(dir, name+".json")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set adds or replaces a profile, after validating its list. Replacing the active profile applies the new list to
+// the attached recognizers.
+func (profiles *PhraseListProfiles) Set(name string, list PhraseList) error {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return &PhraseListError{Index: -1, Message: fmt.Sprintf("invalid profile name %q", name)}
+	}
+	if err := list.Validate(); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	profiles.profiles[name] = list
+	if name == profiles.active {
+		return profiles.applyLocked(profiles.recognizers, list)
+	}
+	return nil
+}
+
+// Get returns the list of a profile.
+func (profiles *PhraseListProfiles) Get(name string) (PhraseList, bool) {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	list, ok := profiles.profiles[name]
+	return list, ok
+}
+
+// Names returns the sorted names of the profiles.
+func (profiles *PhraseListProfiles) Names() []string {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	names := make([]string, 0, len(profiles.profiles))
+	for name := range profiles.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Remove removes a profile. Removing the active profile deactivates it, clearing the phrase list of the attached
+// recognizers.
+func (profiles *PhraseListProfiles) Remove(name string) error {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	delete(profiles.profiles, name)
+	if name == profiles.active {
+		profiles.active = ""
+		return profiles.applyLocked(profiles.recognizers, PhraseList{})
+	}
+	return nil
+}
+
+// Activate applies the list of a profile to the attached recognizers, and to the recognizers attached afterwards.
+// The empty name deactivates the active profile, clearing their phrase list.
+func (profiles *PhraseListProfiles) Activate(name string) error {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	list, ok := profiles.profiles[name]
+	if !ok && name != "" {
+		return &PhraseListError{Index: -1, Message: fmt.Sprintf("unknown profile %q", name)}
+	}
+	profiles.active = name
+	return profiles.applyLocked(profiles.recognizers, list)
+}
+
+// Active returns the name of the active profile, empty if none.
+func (profiles *PhraseListProfiles) Active() string {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	return profiles.active
+}
+
+// Attach applies the active profile, if any, to a recognizer and keeps it applied as profiles are activated, until
+// Detach is called. Recognizers must be detached before they are closed.
+func (profiles *PhraseListProfiles) Attach(recognizer GrammarRecognizer) error {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	for _, attached := range profiles.recognizers {
+		if attached == recognizer {
+			return nil
+		}
+	}
+	profiles.recognizers = append(profiles.recognizers, recognizer)
+	if profiles.active == "" {
+		return nil
+	}
+	return profiles.applyLocked([]GrammarRecognizer{recognizer}, profiles.profiles[profiles.active])
+}
+
+// Detach stops applying the profiles to a recognizer. Its phrase list is left as is.
+func (profiles *PhraseListProfiles) Detach(recognizer GrammarRecognizer) {
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	for i, attached := range profiles.recognizers {
+		if attached == recognizer {
+			profiles.recognizers = append(profiles.recognizers[:i], profiles.recognizers[i+1:]...)
+			return
+		}
+	}
+}
+
+// applyLocked applies a list to recognizers, returning the first error.
+func (profiles *PhraseListProfiles) applyLocked(recognizers []GrammarRecognizer, list PhraseList) error {
+	var first error
+	for _, recognizer := range recognizers {
+		if err := profiles.apply(recognizer, list); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,251 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+func TestPhraseListNormalizesAndDeduplicates(t *testing.T) {
+	list := NewPhraseList("  Contoso  Cloud ", "contoso cloud", "", "Peloozoid", "\tpeloozoid\n", "Azure")
+	if phrases := list.Phrases(); !reflect.DeepEqual(phrases, []string{"Contoso Cloud", "Peloozoid", "Azure"}) {
+		t.Error("Unexpected phrases: ", phrases)
+	}
+	added := list.Add("AZURE", "Fabrikam").WithWeight(1.5)
+	if added.Len() != 4 || !added.Contains(" fabrikam ") || added.Weight() != 1.5 {
+		t.Error("Unexpected list after Add: ", added.Phrases(), added.Weight())
+	}
+	removed := added.Remove("contoso   CLOUD")
+	if removed.Contains("Contoso Cloud") || removed.Len() != 3 {
+		t.Error("Unexpected list after Remove: ", removed.Phrases())
+	}
+	if list.Len() != 3 || list.Contains("Fabrikam") || list.Weight() != DefaultPhraseListWeight {
+		t.Error("Add modified the original list: ", list.Phrases())
+	}
+	if merged := NewPhraseList("a").Merge(added); merged.Len() != 5 || merged.Weight() != DefaultPhraseListWeight {
+		t.Error("Unexpected merged list: ", merged.Phrases(), merged.Weight())
+	}
+}
+
+func TestPhraseListValidate(t *testing.T) {
+	phrases := make([]string, DefaultPhraseListLimits.MaxPhrases+1)
+	for i := range phrases {
+		phrases[i] = fmt.Sprintf("phrase %d", i)
+	}
+	tests := []struct {
+		list  PhraseList
+		index int
+	}{
+		{NewPhraseList(phrases[:DefaultPhraseListLimits.MaxPhrases]...), 0},
+		{NewPhraseList(phrases...), -1},
+		{NewPhraseList("ok", strings.Repeat("é", DefaultPhraseListLimits.MaxPhraseLength+1)), 1},
+		{NewPhraseList("ok").WithWeight(2.5), -1},
+		{NewPhraseList("ok").WithWeight(-1), -1},
+	}
+	for i, test := range tests {
+		err := test.list.Validate()
+		if i == 0 {
+			if err != nil {
+				t.Error("Unexpected error at the limits: ", err)
+			}
+			continue
+		}
+		var listErr *PhraseListError
+		if !errors.As(err, &listErr) || listErr.Index != test.index || !errors.Is(err, common.ErrInvalidArg) {
+			t.Errorf("Validate() #%d = %v, want a PhraseListError at index %d", i, err, test.index)
+		}
+	}
+	if err := NewPhraseList("abcdef").ValidateLimits(PhraseListLimits{MaxPhraseLength: 5}); err == nil {
+		t.Error("Expected an error for a phrase longer than the limit")
+	}
+}
+
+func TestReadPhraseList(t *testing.T) {
+	want := []string{"Contoso", "Peloozoid tablets", "Fabrikam, Inc."}
+	text, err := ReadPhraseListText(strings.NewReader("\ufeff# products\nContoso\n\n  Peloozoid   tablets \r\ncontoso\nFabrikam, Inc.\n"))
+	if err != nil || !reflect.DeepEqual(text.Phrases(), want) {
+		t.Error("Unexpected text phrase list: ", text.Phrases(), err)
+	}
+	csv, err := ReadPhraseListCSV(strings.NewReader("phrase,category\nContoso,company\n# comment\n\"Peloozoid tablets\"\nCONTOSO\n\"Fabrikam, Inc.\",company\n"))
+	if err != nil || !reflect.DeepEqual(csv.Phrases(), want) {
+		t.Error("Unexpected CSV phrase list: ", csv.Phrases(), err)
+	}
+	array, err := ReadPhraseListJSON(strings.NewReader(`["Contoso", "Peloozoid tablets", "contoso", "Fabrikam, Inc."]`))
+	if err != nil || !reflect.DeepEqual(array.Phrases(), want) {
+		t.Error("Unexpected JSON array phrase list: ", array.Phrases(), err)
+	}
+	object, err := ReadPhraseListJSON(strings.NewReader(`{"phrases": ["Contoso", "Peloozoid tablets", "Fabrikam, Inc."], "weight": 1.5}`))
+	if err != nil || !reflect.DeepEqual(object.Phrases(), want) || object.Weight() != 1.5 {
+		t.Error("Unexpected JSON object phrase list: ", object.Phrases(), object.Weight(), err)
+	}
+	if _, err := ReadPhraseListCSV(strings.NewReader("\"unterminated\n")); err == nil {
+		t.Error("Expected an error for invalid CSV")
+	}
+}
+
+func TestPhraseListZeroWeight(t *testing.T) {
+	list := NewPhraseList("Contoso").WithWeight(0)
+	if list.Weight() != 0 || list.Validate() != nil {
+		t.Error("Unexpected zero weight: ", list.Weight(), list.Validate())
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	if string(data) != `{"phrases":["Contoso"],"weight":0}` {
+		t.Error("Unexpected JSON: ", string(data))
+	}
+	var decoded PhraseList
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Weight() != 0 {
+		t.Error("Unexpected decoded weight: ", decoded.Weight(), err)
+	}
+	if data, _ := json.Marshal(NewPhraseList("Contoso")); string(data) != `{"phrases":["Contoso"]}` {
+		t.Error("Unexpected JSON without weight: ", string(data))
+	}
+}
+
+func TestPhraseListSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "phrase-list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	list := NewPhraseList("Contoso", "Fabrikam, Inc.", `Say "hi"`).WithWeight(1.5)
+	for _, name := range []string{"list.txt", "list.csv", "list.json"} {
+		path := filepath.Join(dir, name)
+		if err := list.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadPhraseList(path)
+		if err != nil || !reflect.DeepEqual(loaded.Phrases(), list.Phrases()) {
+			t.Error(name, ": unexpected phrases: ", loaded.Phrases(), err)
+		}
+		if weight := loaded.Weight(); (name == "list.json") != (weight == 1.5) {
+			t.Error(name, ": unexpected weight: ", weight)
+		}
+	}
+	if _, err := LoadPhraseList(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+
+	var buffer bytes.Buffer
+	if err := json.NewEncoder(&buffer).Encode(struct{ List PhraseList }{NewPhraseList()}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(buffer.String()); got != `{"List":{"phrases":[]}}` {
+		t.Error("Unexpected JSON: ", got)
+	}
+}
+
+type fakeGrammarRecognizer struct {
+	applied []PhraseList
+}
+
+func (recognizer *fakeGrammarRecognizer) recognizerHandle() common.SPXHandle {
+	return 0
+}
+
+func newTestProfiles(t *testing.T) *PhraseListProfiles {
+	profiles := NewPhraseListProfiles()
+	profiles.apply = func(recognizer GrammarRecognizer, list PhraseList) error {
+		fake := recognizer.(*fakeGrammarRecognizer)
+		fake.applied = append(fake.applied, list)
+		return nil
+	}
+	if err := profiles.Set("medical", NewPhraseList("Peloozoid", "ibuprofen")); err != nil {
+		t.Fatal(err)
+	}
+	if err := profiles.Set("product-names", NewPhraseList("Contoso").WithWeight(2)); err != nil {
+		t.Fatal(err)
+	}
+	return profiles
+}
+
+func TestPhraseListProfiles(t *testing.T) {
+	profiles := newTestProfiles(t)
+	first, second := new(fakeGrammarRecognizer), new(fakeGrammarRecognizer)
+	profiles.Attach(first)
+	if len(first.applied) != 0 {
+		t.Error("Unexpected list applied without active profile: ", first.applied)
+	}
+	if err := profiles.Activate("medical"); err != nil {
+		t.Fatal(err)
+	}
+	profiles.Attach(second)
+	profiles.Attach(second)
+	if len(first.applied) != 1 || len(second.applied) != 1 || !second.applied[0].Contains("peloozoid") {
+		t.Error("Unexpected applied lists: ", first.applied, second.applied)
+	}
+
+	// Swapping the profile applies it to the attached recognizers only.
+	profiles.Detach(first)
+	if err := profiles.Activate("product-names"); err != nil {
+		t.Fatal(err)
+	}
+	if last := second.applied[len(second.applied)-1]; len(first.applied) != 1 || !last.Contains("Contoso") || last.Weight() != 2 {
+		t.Error("Unexpected applied lists after the swap: ", first.applied, second.applied)
+	}
+	profiles.Set("product-names", NewPhraseList("Contoso", "Fabrikam"))
+	if last := second.applied[len(second.applied)-1]; last.Len() != 2 || profiles.Active() != "product-names" {
+		t.Error("Unexpected applied list after replacing the active profile: ", last.Phrases())
+	}
+	profiles.Set("medical", NewPhraseList("aspirin"))
+	if len(second.applied) != 3 {
+		t.Error("Replacing an inactive profile applied it: ", second.applied)
+	}
+
+	if err := profiles.Activate("legal"); !errors.Is(err, common.ErrInvalidArg) || profiles.Active() != "product-names" {
+		t.Error("Unexpected activation of an unknown profile: ", err)
+	}
+	if err := profiles.Remove("product-names"); err != nil || profiles.Active() != "" {
+		t.Error("Unexpected removal of the active profile: ", err, profiles.Active())
+	}
+	if last := second.applied[len(second.applied)-1]; last.Len() != 0 {
+		t.Error("Removing the active profile did not clear the list: ", last.Phrases())
+	}
+	if names := profiles.Names(); !reflect.DeepEqual(names, []string{"medical"}) {
+		t.Error("Unexpected names: ", names)
+	}
+	if err := profiles.Set("bad", NewPhraseList("x").WithWeight(3)); !errors.Is(err, common.ErrInvalidArg) {
+		t.Error("Expected a validation error, got ", err)
+	}
+}
+
+func TestPhraseListProfilesSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "phrase-list-profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := newTestProfiles(t).Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "support.txt"), []byte("refund\nwarranty\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "notes.md"), []byte("ignored"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := LoadPhraseListProfiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := profiles.Names(); !reflect.DeepEqual(names, []string{"medical", "product-names", "support"}) {
+		t.Error("Unexpected names: ", names)
+	}
+	if list, ok := profiles.Get("product-names"); !ok || list.Weight() != 2 || !list.Contains("Contoso") {
+		t.Error("Unexpected loaded profile: ", list.Phrases(), list.Weight())
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"context"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// #include <speechapi_c_common.h>
+import "C"
+
+// SessionLimiter limits the sessions of the recognizers and synthesizers created from a config, e.g. to keep them
+// within the quotas of their resource. See limit.SetLimiter to use a limit.Limiter.
+type SessionLimiter interface {
+	// AcquireSession waits until a session can start, or until the context is done. text is the plain text or SSML
+	// of a synthesis, and is empty for a recognition. The returned function ends the session, given its error.
+	AcquireSession(ctx context.Context, text string, ssml bool) (release func(err error), err error)
+}
+
+// SetLimiter sets the limiter of the recognizers, translation recognizers, conversation transcribers and synthesizers
+// created from the config afterwards, or removes it when limiter is nil. Each single-shot recognition, continuous
+// recognition or transcription, SpeakTextAsync and SpeakSsmlAsync then waits for the limiter to start. A continuous
+// recognition holds its session until the session stops, the recognition is stopped or the recognizer is closed.
+func (config *SpeechConfig) SetLimiter(limiter SessionLimiter) {
+	config.limiter = limiter
+}
+
+// Limiter returns the limiter set with SetLimiter, or nil.
+func (config *SpeechConfig) Limiter() SessionLimiter {
+	return config.limiter
+}
+
+// acquireSession waits for the limiter, if any. The returned function is never nil.
+func acquireSession(ctx context.Context, limiter SessionLimiter, text string, ssml bool) (func(err error), error) {
+	if limiter == nil {
+		return func(error) {}, nil
+	}
+	return limiter.AcquireSession(ctx, text, ssml)
+}
+
+// startLimitedSession acquires the session of a continuous recognition from the limiter, if any, and holds it until
+// the session stops or the recognition is stopped. connect connects the native callbacks of the canceled and session
+// stopped events, which record the error of the session and release it.
+func startLimitedSession(ctx context.Context, limiter SessionLimiter, handle C.SPXHANDLE, connect func()) error {
+	if limiter == nil {
+		return nil
+	}
+	release, err := limiter.AcquireSession(ctx, "", false)
+	if err != nil {
+		return err
+	}
+	connect()
+	beginLimitedSession(handle, release)
+	return nil
+}
+
+// limitedSession is the session of a continuous recognition acquired from a limiter, with the error of its
+// cancellation, if any.
+type limitedSession struct {
+	release func(err error)
+	err     error
+}
+
+var limitedSessions = make(map[C.SPXHANDLE]*limitedSession)
+
+func beginLimitedSession(handle C.SPXHANDLE, release func(err error)) {
+	endLimitedSession(handle)
+	mu.Lock()
+	defer mu.Unlock()
+	limitedSessions[handle] = &limitedSession{release: release}
+}
+
+// cancelLimitedSession records the error of a canceled continuous recognition, so that its session is released
+// with it, e.g. to back off when it is throttled.
+func cancelLimitedSession(handle C.SPXHANDLE, canceled *common.CancellationError) {
+	mu.Lock()
+	defer mu.Unlock()
+	if session, ok := limitedSessions[handle]; ok && session.err == nil && canceled.Reason == common.Error {
+		session.err = canceled
+	}
+}
+
+func endLimitedSession(handle C.SPXHANDLE) {
+	mu.Lock()
+	session, ok := limitedSessions[handle]
+	delete(limitedSessions, handle)
+	mu.Unlock()
+	if ok {
+		session.release(session.err)
+	}
+}
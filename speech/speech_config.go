@@ -16,8 +16,11 @@ import "C"
 
 // SpeechConfig is the class that defines configurations for speech recognition or speech synthesis.
 type SpeechConfig struct {
+	common.RecognitionProperties
 	handle     C.SPXHANDLE
 	properties *common.PropertyCollection
+	history    *configHistory
+	limiter    SessionLimiter
 }
 
 // GetHandle gets the handle to the resource (for internal use)
@@ -36,6 +39,8 @@ func NewSpeechConfigFromHandle(handle common.SPXHandle) (*SpeechConfig, error) {
 	config := new(SpeechConfig)
 	config.handle = cHandle
 	config.properties = common.NewPropertyCollectionFromHandle(handle2uintptr(propBagHandle))
+	config.history = new(configHistory)
+	config.RecognitionProperties = common.NewRecognitionProperties(configAccessor{config.properties, config.history})
 	err := config.properties.SetPropertyByString("SPEECHSDK-SPEECH-CONFIG-SYSTEM-LANGUAGE", "Go")
 	if err != nil {
 		config.Close()
@@ -260,6 +265,9 @@ func (config *SpeechConfig) SetSpeechSynthesisOutputFormat(format common.SpeechS
 	if ret != C.SPX_NOERROR {
 		return common.NewCarbonError(ret)
 	}
+	if config.history != nil {
+		config.history.synthesisOutputFormat = &format
+	}
 	return nil
 }
 
@@ -291,7 +299,7 @@ func (config *SpeechConfig) SetProxyWithUsernameAndPassword(hostname string, por
 
 // SetProperty sets a property value by ID.
 func (config *SpeechConfig) SetProperty(id common.PropertyID, value string) error {
-	return config.properties.SetProperty(id, value)
+	return configAccessor{config.properties, config.history}.SetProperty(id, value)
 }
 
 // GetProperty gets a property value by ID.
@@ -301,7 +309,11 @@ func (config *SpeechConfig) GetProperty(id common.PropertyID) string {
 
 // SetPropertyByString sets a property value by string.
 func (config *SpeechConfig) SetPropertyByString(name string, value string) error {
-	return config.properties.SetPropertyByString(name, value)
+	err := config.properties.SetPropertyByString(name, value)
+	if err == nil {
+		config.history.setPropertyByString(name, value)
+	}
+	return err
 }
 
 // GetPropertyByString gets a property value by string.
@@ -319,6 +331,7 @@ func (config *SpeechConfig) SetServiceProperty(name string, value string, channe
 	if ret != C.SPX_NOERROR {
 		return common.NewCarbonError(ret)
 	}
+	config.history.setServiceProperty(name, value, channel)
 	return nil
 }
 
@@ -328,6 +341,9 @@ func (config *SpeechConfig) SetProfanity(profanity common.ProfanityOption) error
 	if ret != C.SPX_NOERROR {
 		return common.NewCarbonError(ret)
 	}
+	if config.history != nil {
+		config.history.profanity = &profanity
+	}
 	return nil
 }
 
@@ -5,6 +5,7 @@ package speech
 
 import (
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/diagnostics/logging"
 )
 
 // #include <stdlib.h>
@@ -17,6 +18,10 @@ type SpeechRecognitionCanceledEventArgs struct {
 	Reason       common.CancellationReason
 	ErrorCode    common.CancellationErrorCode
 	ErrorDetails string
+
+	// SessionLog is the log captured for the session when it was canceled with an error and a session log capture
+	// is enabled (see logging.EnableSessionLogCapture).
+	SessionLog *logging.SessionLog
 }
 
 // NewSpeechRecognitionCanceledEventArgsFromHandle creates the object from the handle (for internal use)
@@ -44,8 +49,16 @@ func NewSpeechRecognitionCanceledEventArgsFromHandle(handle common.SPXHandle) (*
 	}
 	event.ErrorCode = (common.CancellationErrorCode)(cCode)
 	event.ErrorDetails = event.Result.Properties.GetProperty(common.SpeechServiceResponseJSONErrorDetails, "")
+	if event.Reason == common.Error {
+		event.SessionLog = logging.CapturedSessionLog(event.SessionID)
+	}
 	return event, nil
 }
 
+// Err returns the cancellation as an error.
+func (event SpeechRecognitionCanceledEventArgs) Err() *common.CancellationError {
+	return &common.CancellationError{Reason: event.Reason, ErrorCode: event.ErrorCode, ErrorDetails: event.ErrorDetails, SessionID: event.SessionID}
+}
+
 // SpeechRecognitionCanceledEventHandler is the type of the event handler that receives SpeechRecognitionCanceledEventArgs
 type SpeechRecognitionCanceledEventHandler func(event SpeechRecognitionCanceledEventArgs)
@@ -4,11 +4,13 @@
 package speech
 
 import (
+	"context"
 	"math"
 	"unsafe"
 
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
 )
 
 // #include <stdlib.h>
@@ -29,15 +31,17 @@ import "C"
 
 // SpeechRecognizer is the class for speech recognizers.
 type SpeechRecognizer struct {
+	common.RecognitionProperties
 	Properties                 *common.PropertyCollection
 	handle                     C.SPXHANDLE
 	handleAsyncStartContinuous C.SPXASYNCHANDLE
 	handleAsyncStopContinuous  C.SPXASYNCHANDLE
 	handleAsyncStartKeyword    C.SPXASYNCHANDLE
 	handleAsyncStopKeyword     C.SPXASYNCHANDLE
+	limiter                    SessionLimiter
 }
 
-func newSpeechRecognizerFromHandle(handle C.SPXHANDLE) (*SpeechRecognizer, error) {
+func newSpeechRecognizerFromHandle(handle C.SPXHANDLE, limiter SessionLimiter) (*SpeechRecognizer, error) {
 	var propBagHandle C.SPXHANDLE
 	ret := uintptr(C.recognizer_get_property_bag(handle, &propBagHandle))
 	if ret != C.SPX_NOERROR {
@@ -45,11 +49,13 @@ func newSpeechRecognizerFromHandle(handle C.SPXHANDLE) (*SpeechRecognizer, error
 	}
 	recognizer := new(SpeechRecognizer)
 	recognizer.handle = handle
+	recognizer.limiter = limiter
 	recognizer.handleAsyncStartContinuous = C.SPXHANDLE_INVALID
 	recognizer.handleAsyncStopContinuous = C.SPXHANDLE_INVALID
 	recognizer.handleAsyncStartKeyword = C.SPXHANDLE_INVALID
 	recognizer.handleAsyncStopKeyword = C.SPXHANDLE_INVALID
 	recognizer.Properties = common.NewPropertyCollectionFromHandle(handle2uintptr(propBagHandle))
+	recognizer.RecognitionProperties = common.NewRecognitionPropertiesFromCollection(recognizer.Properties)
 	return recognizer, nil
 }
 
@@ -70,7 +76,7 @@ func NewSpeechRecognizerFromConfig(config *SpeechConfig, audioConfig *audio.Audi
 	if ret != C.SPX_NOERROR {
 		return nil, common.NewCarbonError(ret)
 	}
-	return newSpeechRecognizerFromHandle(handle)
+	return newSpeechRecognizerFromHandle(handle, config.limiter)
 }
 
 // NewSpeechRecognizerFromAutoDetectSourceLangConfig creates a speech recognizer from a speech config, auto detection source language config and audio config
@@ -94,7 +100,7 @@ func NewSpeechRecognizerFromAutoDetectSourceLangConfig(config *SpeechConfig, lan
 	if ret != C.SPX_NOERROR {
 		return nil, common.NewCarbonError(ret)
 	}
-	return newSpeechRecognizerFromHandle(handle)
+	return newSpeechRecognizerFromHandle(handle, config.limiter)
 }
 
 // NewSpeechRecognizerFomAutoDetectSourceLangConfig is a deprecated alias for NewSpeechRecognizerFromAutoDetectSourceLangConfig.
@@ -124,7 +130,7 @@ func NewSpeechRecognizerFromSourceLanguageConfig(config *SpeechConfig, sourceLan
 	if ret != C.SPX_NOERROR {
 		return nil, common.NewCarbonError(ret)
 	}
-	return newSpeechRecognizerFromHandle(handle)
+	return newSpeechRecognizerFromHandle(handle, config.limiter)
 }
 
 // NewSpeechRecognizerFromSourceLanguage creates a speech recognizer from a speech config, source language and audio config
@@ -142,15 +148,40 @@ func NewSpeechRecognizerFromSourceLanguage(config *SpeechConfig, sourceLanguage
 // Note: Since RecognizeOnceAsync() returns only a single utterance, it is suitable only for single
 // shot recognition like command or query.
 // For long-running multi-utterance recognition, use StartContinuousRecognitionAsync() instead.
+// When the result is canceled, the outcome holds both the result and a *common.CancellationError.
 func (recognizer SpeechRecognizer) RecognizeOnceAsync() chan SpeechRecognitionOutcome {
+	return recognizer.RecognizeOnceWithContextAsync(context.Background())
+}
+
+// RecognizeOnceWithContextAsync is like RecognizeOnceAsync, with ctx as the parent of the trace spans of the
+// recognition (see the tracing package). The recognition is not canceled with ctx.
+func (recognizer SpeechRecognizer) RecognizeOnceWithContextAsync(ctx context.Context) chan SpeechRecognitionOutcome {
 	outcome := make(chan SpeechRecognitionOutcome)
+	recognizer.beginTrace(ctx, false)
 	go func() {
+		release, err := acquireSession(ctx, recognizer.limiter, "", false)
+		if err != nil {
+			traceEnd(recognizer.handle)
+			outcome <- SpeechRecognitionOutcome{Result: nil, OperationOutcome: common.OperationOutcome{err}}
+			return
+		}
 		var handle C.SPXRESULTHANDLE
+		recordRecognitionStarted(metrics.SpeechRecognizer, recognizer.handle)
 		ret := uintptr(C.recognizer_recognize_once(recognizer.handle, &handle))
+		recordRecognitionStopped(recognizer.handle)
 		if ret != C.SPX_NOERROR {
-			outcome <- SpeechRecognitionOutcome{Result: nil, OperationOutcome: common.OperationOutcome{common.NewCarbonError(ret)}}
+			err := common.NewCarbonError(ret)
+			release(err)
+			outcome <- SpeechRecognitionOutcome{Result: nil, OperationOutcome: common.OperationOutcome{err}}
 		} else {
 			result, err := NewSpeechRecognitionResultFromHandle(handle2uintptr(handle))
+			if err == nil {
+				err = canceledRecognitionError(result, recognizer.Properties)
+			}
+			release(err)
+			recordRecognitionResult(metrics.SpeechRecognizer, result)
+			traceRecognitionResult(recognizer.handle, result)
+			traceEnd(recognizer.handle)
 			outcome <- SpeechRecognitionOutcome{Result: result, OperationOutcome: common.OperationOutcome{err}}
 		}
 	}()
@@ -168,11 +199,25 @@ func releaseAsyncHandleIfValid(handle *C.SPXASYNCHANDLE) uintptr {
 
 // StartContinuousRecognitionAsync asynchronously initiates continuous speech recognition operation.
 func (recognizer SpeechRecognizer) StartContinuousRecognitionAsync() chan error {
+	return recognizer.StartContinuousRecognitionWithContextAsync(context.Background())
+}
+
+// StartContinuousRecognitionWithContextAsync is like StartContinuousRecognitionAsync, with ctx as the parent of the
+// trace spans of the sessions and utterances (see the tracing package). The recognition is not stopped with ctx.
+func (recognizer SpeechRecognizer) StartContinuousRecognitionWithContextAsync(ctx context.Context) chan error {
 	outcome := make(chan error)
+	recognizer.beginTrace(ctx, true)
 	go func() {
+		if err := startLimitedSession(ctx, recognizer.limiter, recognizer.handle, recognizer.connectLimitedEvents); err != nil {
+			traceEnd(recognizer.handle)
+			outcome <- err
+			return
+		}
 		// Close any unfinished previous attempt
 		ret := releaseAsyncHandleIfValid(&recognizer.handleAsyncStartContinuous)
 		if ret == C.SPX_NOERROR {
+			recognizer.connectRecordedEvents()
+			recordRecognitionStarted(metrics.SpeechRecognizer, recognizer.handle)
 			ret = uintptr(C.recognizer_start_continuous_recognition_async(recognizer.handle, &recognizer.handleAsyncStartContinuous))
 		}
 		if ret == C.SPX_NOERROR {
@@ -180,6 +225,7 @@ func (recognizer SpeechRecognizer) StartContinuousRecognitionAsync() chan error
 		}
 		releaseAsyncHandleIfValid(&recognizer.handleAsyncStartContinuous)
 		if ret != C.SPX_NOERROR {
+			endLimitedSession(recognizer.handle)
 			outcome <- common.NewCarbonError(ret)
 			return
 		}
@@ -200,6 +246,8 @@ func (recognizer SpeechRecognizer) StopContinuousRecognitionAsync() chan error {
 			ret = uintptr(C.recognizer_stop_continuous_recognition_async_wait_for(recognizer.handleAsyncStopContinuous, math.MaxUint32))
 		}
 		releaseAsyncHandleIfValid(&recognizer.handleAsyncStopContinuous)
+		recordRecognitionStopped(recognizer.handle)
+		endLimitedSession(recognizer.handle)
 		if ret != C.SPX_NOERROR {
 			outcome <- common.NewCarbonError(ret)
 			return
@@ -216,6 +264,8 @@ func (recognizer SpeechRecognizer) StartKeywordRecognitionAsync(model KeywordRec
 	go func() {
 		ret := releaseAsyncHandleIfValid(&recognizer.handleAsyncStartKeyword)
 		if ret == C.SPX_NOERROR {
+			recognizer.connectRecordedEvents()
+			recordRecognitionStarted(metrics.SpeechRecognizer, recognizer.handle)
 			ret = uintptr(C.recognizer_start_keyword_recognition_async(recognizer.handle, modelHandle, &recognizer.handleAsyncStartKeyword))
 		}
 		if ret == C.SPX_NOERROR {
@@ -243,6 +293,7 @@ func (recognizer SpeechRecognizer) StopKeywordRecognitionAsync() chan error {
 			ret = uintptr(C.recognizer_stop_keyword_recognition_async_wait_for(recognizer.handleAsyncStopKeyword, math.MaxUint32))
 		}
 		releaseAsyncHandleIfValid(&recognizer.handleAsyncStopKeyword)
+		recordRecognitionStopped(recognizer.handle)
 		if ret != C.SPX_NOERROR {
 			outcome <- common.NewCarbonError(ret)
 			return
@@ -364,8 +415,62 @@ func (recognizer SpeechRecognizer) Canceled(handler SpeechRecognitionCanceledEve
 	}
 }
 
+// connectTracedEvents connects the native callbacks of the events traced during a recognition for which no handler
+// is set. The recognized and canceled events are only traced in continuous recognition.
+func (recognizer SpeechRecognizer) connectTracedEvents(continuous bool) {
+	handle := recognizer.handle
+	if getSessionStartedCallback(handle) == nil {
+		C.recognizer_session_started_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_session_started)), nil)
+	}
+	if getSessionStoppedCallback(handle) == nil {
+		C.recognizer_session_stopped_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_session_stopped)), nil)
+	}
+	if getSpeechStartDetectedCallback(handle) == nil {
+		C.recognizer_speech_start_detected_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_speech_start_detected)), nil)
+	}
+	if !continuous {
+		return
+	}
+	if getRecognizedCallback(handle) == nil {
+		C.recognizer_recognized_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_recognized)), nil)
+	}
+	if getCanceledCallback(handle) == nil {
+		C.recognizer_canceled_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_canceled)), nil)
+	}
+}
+
+// connectRecordedEvents connects the native callbacks of the recognized and canceled events for which no handler is
+// set when a metrics hook is set, so that the results of a continuous recognition are recorded.
+func (recognizer SpeechRecognizer) connectRecordedEvents() {
+	if metrics.CurrentHook() == nil {
+		return
+	}
+	handle := recognizer.handle
+	if getRecognizedCallback(handle) == nil {
+		C.recognizer_recognized_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_recognized)), nil)
+	}
+	if getCanceledCallback(handle) == nil {
+		C.recognizer_canceled_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_canceled)), nil)
+	}
+}
+
+// connectLimitedEvents connects the native callbacks of the canceled and session stopped events for which no handler
+// is set, so that the session of a continuous recognition is released when it stops, with the error of its
+// cancellation.
+func (recognizer SpeechRecognizer) connectLimitedEvents() {
+	handle := recognizer.handle
+	if getCanceledCallback(handle) == nil {
+		C.recognizer_canceled_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_canceled)), nil)
+	}
+	if getSessionStoppedCallback(handle) == nil {
+		C.recognizer_session_stopped_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_session_stopped)), nil)
+	}
+}
+
 // Close disposes the associated resources.
 func (recognizer SpeechRecognizer) Close() {
+	traceEnd(recognizer.handle)
+	endLimitedSession(recognizer.handle)
 	recognizer.SessionStarted(nil)
 	recognizer.SessionStopped(nil)
 	recognizer.SpeechStartDetected(nil)
@@ -390,6 +495,10 @@ func (recognizer SpeechRecognizer) Close() {
 	}
 }
 
+func (recognizer *SpeechRecognizer) recognizerHandle() common.SPXHandle {
+	return handle2uintptr(recognizer.handle)
+}
+
 type grammarPhrase struct {
 	handle C.SPXHANDLE
 }
@@ -417,10 +526,16 @@ type PhraseListGrammar struct {
 
 // NewPhraseListGrammarFromRecognizer Creates a phrase list grammar for the specified recognizer.
 func NewPhraseListGrammarFromRecognizer(recognizer *SpeechRecognizer) (*PhraseListGrammar, error) {
+	return NewPhraseListGrammar(recognizer)
+}
+
+// NewPhraseListGrammar creates a phrase list grammar for a speech recognizer, a translation recognizer or a
+// conversation transcriber.
+func NewPhraseListGrammar(recognizer GrammarRecognizer) (*PhraseListGrammar, error) {
 	var handle C.SPXHANDLE
 	name := C.CString("")
 	defer C.free(unsafe.Pointer(name))
-	ret := uintptr(C.phrase_list_grammar_from_recognizer_by_name(&handle, recognizer.handle, name))
+	ret := uintptr(C.phrase_list_grammar_from_recognizer_by_name(&handle, uintptr2handle(recognizer.recognizerHandle()), name))
 	if ret != C.SPX_NOERROR {
 		return nil, common.NewCarbonError(ret)
 	}
@@ -4,10 +4,12 @@
 package speech
 
 import (
+	"context"
 	"unsafe"
 
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/recorder"
 )
 
 // #include <stdlib.h>
@@ -29,9 +31,10 @@ import "C"
 type SpeechSynthesizer struct {
 	Properties *common.PropertyCollection
 	handle     C.SPXHANDLE
+	limiter    SessionLimiter
 }
 
-func newSpeechSynthesizerFromHandle(handle C.SPXHANDLE) (*SpeechSynthesizer, error) {
+func newSpeechSynthesizerFromHandle(handle C.SPXHANDLE, limiter SessionLimiter) (*SpeechSynthesizer, error) {
 	var propBagHandle C.SPXHANDLE
 	ret := uintptr(C.synthesizer_get_property_bag(handle, &propBagHandle))
 	if ret != C.SPX_NOERROR {
@@ -39,6 +42,7 @@ func newSpeechSynthesizerFromHandle(handle C.SPXHANDLE) (*SpeechSynthesizer, err
 	}
 	synthesizer := new(SpeechSynthesizer)
 	synthesizer.handle = handle
+	synthesizer.limiter = limiter
 	synthesizer.Properties = common.NewPropertyCollectionFromHandle(handle2uintptr(propBagHandle))
 	return synthesizer, nil
 }
@@ -60,7 +64,7 @@ func NewSpeechSynthesizerFromConfig(config *SpeechConfig, audioConfig *audio.Aud
 	if ret != C.SPX_NOERROR {
 		return nil, common.NewCarbonError(ret)
 	}
-	return newSpeechSynthesizerFromHandle(handle)
+	return newSpeechSynthesizerFromHandle(handle, config.limiter)
 }
 
 // NewSpeechSynthesizerFromAutoDetectSourceLangConfig creates a speech synthesizer from a speech config, auto detection source language config and audio config
@@ -84,7 +88,7 @@ func NewSpeechSynthesizerFromAutoDetectSourceLangConfig(config *SpeechConfig, la
 	if ret != C.SPX_NOERROR {
 		return nil, common.NewCarbonError(ret)
 	}
-	return newSpeechSynthesizerFromHandle(handle)
+	return newSpeechSynthesizerFromHandle(handle, config.limiter)
 }
 
 // NewSpeechSynthesizerFomAutoDetectSourceLangConfig is a deprecated alias for NewSpeechSynthesizerFromAutoDetectSourceLangConfig.
@@ -94,18 +98,42 @@ func NewSpeechSynthesizerFomAutoDetectSourceLangConfig(config *SpeechConfig, lan
 }
 
 // SpeakTextAsync executes the speech synthesis on plain text, asynchronously.
+// When the result is canceled, the outcome holds both the result and a *common.CancellationError.
 func (synthesizer SpeechSynthesizer) SpeakTextAsync(text string) chan SpeechSynthesisOutcome {
+	return synthesizer.SpeakTextWithContextAsync(context.Background(), text)
+}
+
+// SpeakTextWithContextAsync is like SpeakTextAsync, with ctx as the parent of the trace span of the synthesis (see the
+// tracing package). The synthesis is not canceled with ctx.
+func (synthesizer SpeechSynthesizer) SpeakTextWithContextAsync(ctx context.Context, text string) chan SpeechSynthesisOutcome {
 	outcome := make(chan SpeechSynthesisOutcome)
+	span := synthesizer.startSynthesisTrace(ctx)
 	go func() {
+		release, err := acquireSession(ctx, synthesizer.limiter, text, false)
+		if err != nil {
+			endSynthesisTrace(span, nil, err)
+			outcome <- SpeechSynthesisOutcome{Result: nil, OperationOutcome: common.OperationOutcome{err}}
+			return
+		}
 		var handle C.SPXRESULTHANDLE
+		recorder.SynthesisStarted(text, false)
 		cText := C.CString(text)
 		defer C.free(unsafe.Pointer(cText))
 		length := len(text)
 		ret := uintptr(C.synthesizer_speak_text(synthesizer.handle, cText, (C.uint32_t)(length), &handle))
 		if ret != C.SPX_NOERROR {
-			outcome <- SpeechSynthesisOutcome{Result: nil, OperationOutcome: common.OperationOutcome{common.NewCarbonError(ret)}}
+			err := common.NewCarbonError(ret)
+			release(err)
+			endSynthesisTrace(span, nil, err)
+			outcome <- SpeechSynthesisOutcome{Result: nil, OperationOutcome: common.OperationOutcome{err}}
 		} else {
 			result, err := NewSpeechSynthesisResultFromHandle(handle2uintptr(handle))
+			if err == nil {
+				err = canceledSynthesisError(result, synthesizer.Properties)
+			}
+			release(err)
+			recordSynthesisResult(result)
+			endSynthesisTrace(span, result, err)
 			outcome <- SpeechSynthesisOutcome{Result: result, OperationOutcome: common.OperationOutcome{err}}
 		}
 	}()
@@ -113,18 +141,42 @@ func (synthesizer SpeechSynthesizer) SpeakTextAsync(text string) chan SpeechSynt
 }
 
 // SpeakSsmlAsync executes the speech synthesis on SSML, asynchronously.
+// When the result is canceled, the outcome holds both the result and a *common.CancellationError.
 func (synthesizer SpeechSynthesizer) SpeakSsmlAsync(ssml string) chan SpeechSynthesisOutcome {
+	return synthesizer.SpeakSsmlWithContextAsync(context.Background(), ssml)
+}
+
+// SpeakSsmlWithContextAsync is like SpeakSsmlAsync, with ctx as the parent of the trace span of the synthesis (see the
+// tracing package). The synthesis is not canceled with ctx.
+func (synthesizer SpeechSynthesizer) SpeakSsmlWithContextAsync(ctx context.Context, ssml string) chan SpeechSynthesisOutcome {
 	outcome := make(chan SpeechSynthesisOutcome)
+	span := synthesizer.startSynthesisTrace(ctx)
 	go func() {
+		release, err := acquireSession(ctx, synthesizer.limiter, ssml, true)
+		if err != nil {
+			endSynthesisTrace(span, nil, err)
+			outcome <- SpeechSynthesisOutcome{Result: nil, OperationOutcome: common.OperationOutcome{err}}
+			return
+		}
 		var handle C.SPXRESULTHANDLE
+		recorder.SynthesisStarted(ssml, true)
 		cText := C.CString(ssml)
 		defer C.free(unsafe.Pointer(cText))
 		length := len(ssml)
 		ret := uintptr(C.synthesizer_speak_ssml(synthesizer.handle, cText, (C.uint32_t)(length), &handle))
 		if ret != C.SPX_NOERROR {
-			outcome <- SpeechSynthesisOutcome{Result: nil, OperationOutcome: common.OperationOutcome{common.NewCarbonError(ret)}}
+			err := common.NewCarbonError(ret)
+			release(err)
+			endSynthesisTrace(span, nil, err)
+			outcome <- SpeechSynthesisOutcome{Result: nil, OperationOutcome: common.OperationOutcome{err}}
 		} else {
 			result, err := NewSpeechSynthesisResultFromHandle(handle2uintptr(handle))
+			if err == nil {
+				err = canceledSynthesisError(result, synthesizer.Properties)
+			}
+			release(err)
+			recordSynthesisResult(result)
+			endSynthesisTrace(span, result, err)
 			outcome <- SpeechSynthesisOutcome{Result: result, OperationOutcome: common.OperationOutcome{err}}
 		}
 	}()
@@ -137,6 +189,7 @@ func (synthesizer SpeechSynthesizer) StartSpeakingTextAsync(text string) chan Sp
 	outcome := make(chan SpeechSynthesisOutcome)
 	go func() {
 		var handle C.SPXRESULTHANDLE
+		recorder.SynthesisStarted(text, false)
 		cText := C.CString(text)
 		defer C.free(unsafe.Pointer(cText))
 		length := len(text)
@@ -145,6 +198,9 @@ func (synthesizer SpeechSynthesizer) StartSpeakingTextAsync(text string) chan Sp
 			outcome <- SpeechSynthesisOutcome{Result: nil, OperationOutcome: common.OperationOutcome{common.NewCarbonError(ret)}}
 		} else {
 			result, err := NewSpeechSynthesisResultFromHandle(handle2uintptr(handle))
+			if err == nil {
+				err = canceledSynthesisError(result, synthesizer.Properties)
+			}
 			outcome <- SpeechSynthesisOutcome{Result: result, OperationOutcome: common.OperationOutcome{err}}
 		}
 	}()
@@ -157,6 +213,7 @@ func (synthesizer SpeechSynthesizer) StartSpeakingSsmlAsync(ssml string) chan Sp
 	outcome := make(chan SpeechSynthesisOutcome)
 	go func() {
 		var handle C.SPXRESULTHANDLE
+		recorder.SynthesisStarted(ssml, true)
 		cText := C.CString(ssml)
 		defer C.free(unsafe.Pointer(cText))
 		length := len(ssml)
@@ -165,6 +222,9 @@ func (synthesizer SpeechSynthesizer) StartSpeakingSsmlAsync(ssml string) chan Sp
 			outcome <- SpeechSynthesisOutcome{Result: nil, OperationOutcome: common.OperationOutcome{common.NewCarbonError(ret)}}
 		} else {
 			result, err := NewSpeechSynthesisResultFromHandle(handle2uintptr(handle))
+			if err == nil {
+				err = canceledSynthesisError(result, synthesizer.Properties)
+			}
 			outcome <- SpeechSynthesisOutcome{Result: result, OperationOutcome: common.OperationOutcome{err}}
 		}
 	}()
@@ -19,6 +19,7 @@ import "C"
 // SpeechTranslationConfig defines configurations for translation with speech input.
 type SpeechTranslationConfig struct {
 	SpeechConfig
+	categoryID string
 }
 
 // NewSpeechTranslationConfigFromSubscription creates a speech translation config instance with specified subscription key and region.
@@ -236,6 +237,7 @@ func (config *SpeechTranslationConfig) SetCustomModelCategoryID(categoryID strin
 	if ret != C.SPX_NOERROR {
 		return common.NewCarbonError(ret)
 	}
+	config.categoryID = categoryID
 	return nil
 }
 
@@ -248,3 +250,62 @@ func (config *SpeechTranslationConfig) SetVoiceName(voice string) error {
 func (config *SpeechTranslationConfig) GetVoiceName() string {
 	return config.GetProperty(common.SpeechServiceConnectionTranslationVoice)
 }
+
+// Clone creates a new SpeechTranslationConfig with the same service, credentials, settings, target languages and
+// custom model category as config, then applies opts. See SpeechConfig.Clone for the settings that are copied.
+// The returned config must be closed independently of config.
+func (config *SpeechTranslationConfig) Clone(opts ...ConfigOption) (*SpeechTranslationConfig, error) {
+	builder, err := newCloneBuilder(opts)
+	if err != nil {
+		return nil, err
+	}
+	clone, err := authFromConfig(&config.SpeechConfig).newSpeechTranslationConfig()
+	if err != nil {
+		return nil, err
+	}
+	for _, language := range config.GetTargetLanguages() {
+		if err == nil {
+			err = clone.AddTargetLanguage(language)
+		}
+	}
+	if err == nil && config.categoryID != "" {
+		err = clone.SetCustomModelCategoryID(config.categoryID)
+	}
+	if err == nil {
+		err = config.cloneInto(&clone.SpeechConfig, builder)
+	}
+	if err != nil {
+		clone.Close()
+		return nil, err
+	}
+	return clone, nil
+}
+
+func (auth Auth) newSpeechTranslationConfig() (*SpeechTranslationConfig, error) {
+	var config *SpeechTranslationConfig
+	var err error
+	switch {
+	case auth.Endpoint != "" && auth.Key != "":
+		config, err = NewSpeechTranslationConfigFromEndpointWithSubscription(auth.Endpoint, auth.Key)
+	case auth.Endpoint != "":
+		config, err = NewSpeechTranslationConfigFromEndpoint(auth.Endpoint)
+	case auth.Host != "" && auth.Key != "":
+		config, err = NewSpeechTranslationConfigFromHostWithSubscription(auth.Host, auth.Key)
+	case auth.Host != "":
+		config, err = NewSpeechTranslationConfigFromHost(auth.Host)
+	case auth.Key != "":
+		config, err = NewSpeechTranslationConfigFromSubscription(auth.Key, auth.Region)
+	default:
+		return NewSpeechTranslationConfigFromAuthorizationToken(auth.Token, auth.Region)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if auth.Token != "" {
+		if err = config.SetAuthorizationToken(auth.Token); err != nil {
+			config.Close()
+			return nil, err
+		}
+	}
+	return config, nil
+}
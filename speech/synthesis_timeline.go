@@ -0,0 +1,391 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// BlendShapeFrameRate is the number of blend shape frames per second reported in viseme animations.
+const BlendShapeFrameRate = 60
+
+// BlendShapeNames lists the names of the facial positions of a blend shape frame, in the order they are reported.
+var BlendShapeNames = []string{
+	"eyeBlinkLeft", "eyeLookDownLeft", "eyeLookInLeft", "eyeLookOutLeft", "eyeLookUpLeft", "eyeSquintLeft", "eyeWideLeft",
+	"eyeBlinkRight", "eyeLookDownRight", "eyeLookInRight", "eyeLookOutRight", "eyeLookUpRight", "eyeSquintRight", "eyeWideRight",
+	"jawForward", "jawLeft", "jawRight", "jawOpen",
+	"mouthClose", "mouthFunnel", "mouthPucker", "mouthLeft", "mouthRight", "mouthSmileLeft", "mouthSmileRight",
+	"mouthFrownLeft", "mouthFrownRight", "mouthDimpleLeft", "mouthDimpleRight", "mouthStretchLeft", "mouthStretchRight",
+	"mouthRollLower", "mouthRollUpper", "mouthShrugLower", "mouthShrugUpper", "mouthPressLeft", "mouthPressRight",
+	"mouthLowerDownLeft", "mouthLowerDownRight", "mouthUpperUpLeft", "mouthUpperUpRight",
+	"browDownLeft", "browDownRight", "browInnerUp", "browOuterUpLeft", "browOuterUpRight",
+	"cheekPuff", "cheekSquintLeft", "cheekSquintRight", "noseSneerLeft", "noseSneerRight", "tongueOut",
+	"headRoll", "leftEyeRoll", "rightEyeRoll",
+}
+
+// TimelineBoundary is a word, punctuation or sentence boundary of the synthesized text.
+type TimelineBoundary struct {
+	// Offset is the audio offset at which the boundary starts.
+	Offset time.Duration
+
+	// Duration is the audio duration of the boundary.
+	Duration time.Duration
+
+	// BoundaryType is the type of the boundary.
+	BoundaryType common.SpeechSynthesisBoundaryType
+
+	// Text is the text of the boundary.
+	Text string
+
+	// TextOffset is the offset of the boundary in the input text or SSML.
+	TextOffset uint
+
+	// TextLength is the length of the boundary text.
+	TextLength uint
+}
+
+// TimelineViseme is a viseme reached during synthesis.
+type TimelineViseme struct {
+	// Offset is the audio offset of the viseme.
+	Offset time.Duration
+
+	// VisemeID is the viseme ID.
+	VisemeID uint
+
+	// Animation is the raw animation payload of the viseme event, if any.
+	Animation string `json:",omitempty"`
+}
+
+// TimelineBookmark is a bookmark reached during synthesis.
+type TimelineBookmark struct {
+	// Offset is the audio offset of the bookmark.
+	Offset time.Duration
+
+	// Text is the mark name of the bookmark.
+	Text string
+}
+
+// BlendShapeFrame is one frame of facial positions, parsed from viseme animations.
+type BlendShapeFrame struct {
+	// Index is the frame index since the start of the audio.
+	Index int
+
+	// Offset is the audio offset of the frame.
+	Offset time.Duration
+
+	// Weights are the facial positions of the frame, in the order of BlendShapeNames.
+	Weights []float64
+}
+
+// Timeline is a sorted snapshot of the events collected by a SynthesisTimeline.
+type Timeline struct {
+	// Boundaries holds the word, punctuation and sentence boundaries.
+	Boundaries []TimelineBoundary
+
+	// Visemes holds the visemes.
+	Visemes []TimelineViseme
+
+	// Bookmarks holds the bookmarks.
+	Bookmarks []TimelineBookmark
+
+	// BlendShapes holds the blend shape frames.
+	BlendShapes []BlendShapeFrame
+}
+
+// Words returns the word boundaries of the timeline.
+func (timeline Timeline) Words() []TimelineBoundary {
+	return timeline.boundariesOfType(common.WordBoundary)
+}
+
+// Punctuations returns the punctuation boundaries of the timeline.
+func (timeline Timeline) Punctuations() []TimelineBoundary {
+	return timeline.boundariesOfType(common.PunctuationBoundary)
+}
+
+// Sentences returns the sentence boundaries of the timeline.
+func (timeline Timeline) Sentences() []TimelineBoundary {
+	return timeline.boundariesOfType(common.SentenceBoundary)
+}
+
+func (timeline Timeline) boundariesOfType(boundaryType common.SpeechSynthesisBoundaryType) []TimelineBoundary {
+	var boundaries []TimelineBoundary
+	for _, boundary := range timeline.Boundaries {
+		if boundary.BoundaryType == boundaryType {
+			boundaries = append(boundaries, boundary)
+		}
+	}
+	return boundaries
+}
+
+// WriteJSON writes the timeline as JSON. Offsets and durations are written in milliseconds.
+func (timeline Timeline) WriteJSON(w io.Writer) error {
+	type boundary struct {
+		Type       string  `json:"type"`
+		OffsetMs   float64 `json:"offsetMs"`
+		DurationMs float64 `json:"durationMs"`
+		Text       string  `json:"text"`
+		TextOffset uint    `json:"textOffset"`
+		TextLength uint    `json:"textLength"`
+	}
+	type viseme struct {
+		OffsetMs float64 `json:"offsetMs"`
+		VisemeID uint    `json:"visemeId"`
+	}
+	type bookmark struct {
+		OffsetMs float64 `json:"offsetMs"`
+		Text     string  `json:"text"`
+	}
+	type frame struct {
+		Index    int       `json:"index"`
+		OffsetMs float64   `json:"offsetMs"`
+		Weights  []float64 `json:"weights"`
+	}
+	doc := struct {
+		Boundaries  []boundary `json:"boundaries"`
+		Visemes     []viseme   `json:"visemes"`
+		Bookmarks   []bookmark `json:"bookmarks"`
+		BlendShapes []frame    `json:"blendShapes"`
+	}{
+		Boundaries:  make([]boundary, 0, len(timeline.Boundaries)),
+		Visemes:     make([]viseme, 0, len(timeline.Visemes)),
+		Bookmarks:   make([]bookmark, 0, len(timeline.Bookmarks)),
+		BlendShapes: make([]frame, 0, len(timeline.BlendShapes)),
+	}
+	for _, b := range timeline.Boundaries {
+		doc.Boundaries = append(doc.Boundaries, boundary{
+			Type:       boundaryTypeName(b.BoundaryType),
+			OffsetMs:   durationToMs(b.Offset),
+			DurationMs: durationToMs(b.Duration),
+			Text:       b.Text,
+			TextOffset: b.TextOffset,
+			TextLength: b.TextLength,
+		})
+	}
+	for _, v := range timeline.Visemes {
+		doc.Visemes = append(doc.Visemes, viseme{OffsetMs: durationToMs(v.Offset), VisemeID: v.VisemeID})
+	}
+	for _, b := range timeline.Bookmarks {
+		doc.Bookmarks = append(doc.Bookmarks, bookmark{OffsetMs: durationToMs(b.Offset), Text: b.Text})
+	}
+	for _, f := range timeline.BlendShapes {
+		doc.BlendShapes = append(doc.BlendShapes, frame{Index: f.Index, OffsetMs: durationToMs(f.Offset), Weights: f.Weights})
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// WriteBlendShapeCSV writes one CSV row per blend shape frame: the frame index, its offset in milliseconds and one
+// column per facial position.
+func (timeline Timeline) WriteBlendShapeCSV(w io.Writer) error {
+	width := 0
+	for _, frame := range timeline.BlendShapes {
+		if len(frame.Weights) > width {
+			width = len(frame.Weights)
+		}
+	}
+	writer := csv.NewWriter(w)
+	header := []string{"frame", "offset_ms"}
+	for i := 0; i < width; i++ {
+		if width == len(BlendShapeNames) {
+			header = append(header, BlendShapeNames[i])
+		} else {
+			header = append(header, "shape"+strconv.Itoa(i))
+		}
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	row := make([]string, 2+width)
+	for _, frame := range timeline.BlendShapes {
+		row[0] = strconv.Itoa(frame.Index)
+		row[1] = strconv.FormatFloat(durationToMs(frame.Offset), 'f', 3, 64)
+		for i := 0; i < width; i++ {
+			if i < len(frame.Weights) {
+				row[2+i] = strconv.FormatFloat(frame.Weights[i], 'f', -1, 64)
+			} else {
+				row[2+i] = ""
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// SynthesisTimeline collects the word boundary, viseme and bookmark events of a speech synthesizer into a Timeline,
+// e.g. for lip-sync or karaoke-style highlighting.
+type SynthesisTimeline struct {
+	mu          sync.Mutex
+	synthesizer *SpeechSynthesizer
+	boundaries  []TimelineBoundary
+	visemes     []TimelineViseme
+	bookmarks   []TimelineBookmark
+	blendShapes map[int][]float64
+	err         error
+}
+
+// NewSynthesisTimeline creates a timeline collector attached to the WordBoundary, VisemeReceived and BookmarkReached
+// events of the synthesizer. Handlers previously registered for these events are replaced; to keep your own handlers,
+// create the collector with a nil synthesizer and forward events to OnWordBoundary, OnViseme and OnBookmark instead.
+// To receive blend shape frames, request them in the SSML with <mstts:viseme type="FacialExpression"/>.
+func NewSynthesisTimeline(synthesizer *SpeechSynthesizer) *SynthesisTimeline {
+	timeline := &SynthesisTimeline{synthesizer: synthesizer, blendShapes: make(map[int][]float64)}
+	if synthesizer != nil {
+		synthesizer.WordBoundary(func(event SpeechSynthesisWordBoundaryEventArgs) {
+			defer event.Close()
+			timeline.OnWordBoundary(event)
+		})
+		synthesizer.VisemeReceived(func(event SpeechSynthesisVisemeEventArgs) {
+			defer event.Close()
+			timeline.OnViseme(event)
+		})
+		synthesizer.BookmarkReached(func(event SpeechSynthesisBookmarkEventArgs) {
+			defer event.Close()
+			timeline.OnBookmark(event)
+		})
+	}
+	return timeline
+}
+
+// OnWordBoundary adds a word boundary event to the timeline. It does not close the event.
+func (timeline *SynthesisTimeline) OnWordBoundary(event SpeechSynthesisWordBoundaryEventArgs) {
+	timeline.mu.Lock()
+	defer timeline.mu.Unlock()
+	timeline.boundaries = append(timeline.boundaries, TimelineBoundary{
+		Offset:       ticksToDuration(event.AudioOffset),
+		Duration:     event.Duration,
+		BoundaryType: event.BoundaryType,
+		Text:         event.Text,
+		TextOffset:   event.TextOffset,
+		TextLength:   event.WordLength,
+	})
+}
+
+// OnViseme adds a viseme event to the timeline, parsing the blend shape frames of its animation. It does not close
+// the event.
+func (timeline *SynthesisTimeline) OnViseme(event SpeechSynthesisVisemeEventArgs) {
+	timeline.mu.Lock()
+	defer timeline.mu.Unlock()
+	timeline.visemes = append(timeline.visemes, TimelineViseme{
+		Offset:    ticksToDuration(event.AudioOffset),
+		VisemeID:  event.VisemeID,
+		Animation: event.Animation,
+	})
+	if !strings.Contains(event.Animation, "BlendShapes") {
+		return
+	}
+	var animation struct {
+		FrameIndex  int
+		BlendShapes [][]float64
+	}
+	if err := json.Unmarshal([]byte(event.Animation), &animation); err != nil {
+		if timeline.err == nil {
+			timeline.err = fmt.Errorf("failed to parse viseme animation at offset %d: %v", event.AudioOffset, err)
+		}
+		return
+	}
+	for i, weights := range animation.BlendShapes {
+		timeline.blendShapes[animation.FrameIndex+i] = weights
+	}
+}
+
+// OnBookmark adds a bookmark event to the timeline. It does not close the event.
+func (timeline *SynthesisTimeline) OnBookmark(event SpeechSynthesisBookmarkEventArgs) {
+	timeline.mu.Lock()
+	defer timeline.mu.Unlock()
+	timeline.bookmarks = append(timeline.bookmarks, TimelineBookmark{
+		Offset: ticksToDuration(event.AudioOffset),
+		Text:   event.Text,
+	})
+}
+
+// Timeline returns a snapshot of the collected events, each list sorted by audio offset.
+func (timeline *SynthesisTimeline) Timeline() Timeline {
+	timeline.mu.Lock()
+	defer timeline.mu.Unlock()
+	result := Timeline{
+		Boundaries: append([]TimelineBoundary(nil), timeline.boundaries...),
+		Visemes:    append([]TimelineViseme(nil), timeline.visemes...),
+		Bookmarks:  append([]TimelineBookmark(nil), timeline.bookmarks...),
+	}
+	sort.SliceStable(result.Boundaries, func(i, j int) bool {
+		return result.Boundaries[i].Offset < result.Boundaries[j].Offset
+	})
+	sort.SliceStable(result.Visemes, func(i, j int) bool {
+		return result.Visemes[i].Offset < result.Visemes[j].Offset
+	})
+	sort.SliceStable(result.Bookmarks, func(i, j int) bool {
+		return result.Bookmarks[i].Offset < result.Bookmarks[j].Offset
+	})
+	for index, weights := range timeline.blendShapes {
+		result.BlendShapes = append(result.BlendShapes, BlendShapeFrame{
+			Index:   index,
+			Offset:  time.Duration(index) * time.Second / BlendShapeFrameRate,
+			Weights: weights,
+		})
+	}
+	sort.Slice(result.BlendShapes, func(i, j int) bool {
+		return result.BlendShapes[i].Index < result.BlendShapes[j].Index
+	})
+	return result
+}
+
+// Err returns the first error met while parsing viseme animations, if any.
+func (timeline *SynthesisTimeline) Err() error {
+	timeline.mu.Lock()
+	defer timeline.mu.Unlock()
+	return timeline.err
+}
+
+// Reset discards the collected events, e.g. before the next speak request.
+func (timeline *SynthesisTimeline) Reset() {
+	timeline.mu.Lock()
+	defer timeline.mu.Unlock()
+	timeline.boundaries = nil
+	timeline.visemes = nil
+	timeline.bookmarks = nil
+	timeline.blendShapes = make(map[int][]float64)
+	timeline.err = nil
+}
+
+// Close detaches the collector from the synthesizer events.
+func (timeline *SynthesisTimeline) Close() {
+	if timeline.synthesizer != nil {
+		timeline.synthesizer.WordBoundary(nil)
+		timeline.synthesizer.VisemeReceived(nil)
+		timeline.synthesizer.BookmarkReached(nil)
+		timeline.synthesizer = nil
+	}
+}
+
+func ticksToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks*100) * time.Nanosecond
+}
+
+func durationToMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func boundaryTypeName(boundaryType common.SpeechSynthesisBoundaryType) string {
+	switch boundaryType {
+	case common.WordBoundary:
+		return "word"
+	case common.PunctuationBoundary:
+		return "punctuation"
+	case common.SentenceBoundary:
+		return "sentence"
+	}
+	return "unknown"
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+func TestSynthesisTimelineOrdering(t *testing.T) {
+	timeline := NewSynthesisTimeline(nil)
+	defer timeline.Close()
+	timeline.OnWordBoundary(SpeechSynthesisWordBoundaryEventArgs{AudioOffset: 5000000, Duration: 200 * time.Millisecond, Text: "world", TextOffset: 6, WordLength: 5, BoundaryType: common.WordBoundary})
+	timeline.OnWordBoundary(SpeechSynthesisWordBoundaryEventArgs{AudioOffset: 500000, Duration: 300 * time.Millisecond, Text: "Hello", TextOffset: 0, WordLength: 5, BoundaryType: common.WordBoundary})
+	timeline.OnWordBoundary(SpeechSynthesisWordBoundaryEventArgs{AudioOffset: 7000000, Text: ".", TextOffset: 11, WordLength: 1, BoundaryType: common.PunctuationBoundary})
+	timeline.OnWordBoundary(SpeechSynthesisWordBoundaryEventArgs{AudioOffset: 500000, Text: "Hello world.", WordLength: 12, BoundaryType: common.SentenceBoundary})
+	timeline.OnViseme(SpeechSynthesisVisemeEventArgs{AudioOffset: 1000000, VisemeID: 4})
+	timeline.OnViseme(SpeechSynthesisVisemeEventArgs{AudioOffset: 0, VisemeID: 0})
+	timeline.OnBookmark(SpeechSynthesisBookmarkEventArgs{AudioOffset: 6000000, Text: "mark"})
+
+	result := timeline.Timeline()
+	words := result.Words()
+	if len(words) != 2 || words[0].Text != "Hello" || words[1].Text != "world" {
+		t.Error("Unexpected words: ", words)
+	}
+	if words[0].Offset != 50*time.Millisecond || words[1].TextOffset != 6 || words[1].TextLength != 5 {
+		t.Error("Unexpected word timing: ", words)
+	}
+	if len(result.Sentences()) != 1 || len(result.Punctuations()) != 1 {
+		t.Error("Unexpected boundaries: ", result.Boundaries)
+	}
+	if len(result.Visemes) != 2 || result.Visemes[0].VisemeID != 0 || result.Visemes[1].Offset != 100*time.Millisecond {
+		t.Error("Unexpected visemes: ", result.Visemes)
+	}
+	if len(result.Bookmarks) != 1 || result.Bookmarks[0].Offset != 600*time.Millisecond {
+		t.Error("Unexpected bookmarks: ", result.Bookmarks)
+	}
+
+	timeline.Reset()
+	if len(timeline.Timeline().Boundaries) != 0 {
+		t.Error("Timeline not reset")
+	}
+}
+
+func TestSynthesisTimelineBlendShapes(t *testing.T) {
+	if len(BlendShapeNames) != 55 {
+		t.Error("Unexpected blend shape count: ", len(BlendShapeNames))
+	}
+	weights := make([]string, len(BlendShapeNames))
+	for i := range weights {
+		weights[i] = "0.5"
+	}
+	frame := "[" + strings.Join(weights, ",") + "]"
+	timeline := NewSynthesisTimeline(nil)
+	timeline.OnViseme(SpeechSynthesisVisemeEventArgs{Animation: `{"FrameIndex":60,"BlendShapes":[` + frame + `,` + frame + `]}`})
+	timeline.OnViseme(SpeechSynthesisVisemeEventArgs{Animation: `{"FrameIndex":0,"BlendShapes":[` + frame + `]}`})
+	if err := timeline.Err(); err != nil {
+		t.Error("Got an error: ", err)
+	}
+	result := timeline.Timeline()
+	if len(result.BlendShapes) != 3 {
+		t.Fatal("Unexpected frame count: ", len(result.BlendShapes))
+	}
+	if result.BlendShapes[0].Index != 0 || result.BlendShapes[1].Offset != time.Second || result.BlendShapes[2].Index != 61 {
+		t.Error("Unexpected frames: ", result.BlendShapes)
+	}
+
+	var csvOutput bytes.Buffer
+	if err := result.WriteBlendShapeCSV(&csvOutput); err != nil {
+		t.Error("Got an error: ", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOutput.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "frame,offset_ms,eyeBlinkLeft,") || !strings.HasPrefix(lines[2], "60,1000.000,0.5,") {
+		t.Error("Unexpected CSV: ", csvOutput.String())
+	}
+
+	var jsonOutput bytes.Buffer
+	if err := result.WriteJSON(&jsonOutput); err != nil {
+		t.Error("Got an error: ", err)
+	}
+	var decoded map[string][]interface{}
+	if err := json.Unmarshal(jsonOutput.Bytes(), &decoded); err != nil {
+		t.Error("Got an error: ", err)
+	}
+	if len(decoded["blendShapes"]) != 3 || len(decoded["visemes"]) != 2 {
+		t.Error("Unexpected JSON: ", jsonOutput.String())
+	}
+
+	timeline.OnViseme(SpeechSynthesisVisemeEventArgs{Animation: `{"BlendShapes":[`})
+	if timeline.Err() == nil {
+		t.Error("Expected an animation parsing error")
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+// VoiceFilter specifies the criteria used to select voices from a SynthesisVoicesResult.
+// Fields left at their zero value match any voice.
+type VoiceFilter struct {
+	// Locale matches voices whose locale or one of whose secondary locales equals the BCP-47 tag, ignoring case.
+	Locale string
+
+	// Gender matches voices of the given gender. common.GenderUnknown matches any gender.
+	Gender common.SynthesisVoiceGender
+
+	// VoiceTypes matches voices of any of the given types. An empty list matches any type.
+	VoiceTypes []common.SynthesisVoiceType
+
+	// Style matches voices that list the given style in their StyleList, ignoring case.
+	Style string
+}
+
+// OnlineVoiceTypes lists the voice types served by the online service.
+var OnlineVoiceTypes = []common.SynthesisVoiceType{common.OnlineNeural, common.OnlineNeuralHD, common.OnlineStandard}
+
+// OfflineVoiceTypes lists the voice types served by embedded (offline) synthesis.
+var OfflineVoiceTypes = []common.SynthesisVoiceType{common.OfflineNeural, common.OfflineStandard}
+
+// Matches checks whether the voice satisfies all criteria of the filter.
+func (filter VoiceFilter) Matches(voice *VoiceInfo) bool {
+	if voice == nil {
+		return false
+	}
+	if filter.Locale != "" && !voice.SupportsLocale(filter.Locale) {
+		return false
+	}
+	if filter.Gender != common.GenderUnknown && voice.Gender != filter.Gender {
+		return false
+	}
+	if len(filter.VoiceTypes) > 0 {
+		found := false
+		for _, voiceType := range filter.VoiceTypes {
+			if voice.VoiceType == voiceType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.Style != "" && !voice.SupportsStyle(filter.Style) {
+		return false
+	}
+	return true
+}
+
+// SupportsLocale checks whether the voice speaks the given BCP-47 locale, either as its primary or a secondary locale.
+func (voice VoiceInfo) SupportsLocale(locale string) bool {
+	if strings.EqualFold(voice.Locale, locale) {
+		return true
+	}
+	for _, secondary := range voice.SecondaryLocales {
+		if strings.EqualFold(secondary, locale) {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsStyle checks whether the voice lists the given speaking style.
+func (voice VoiceInfo) SupportsStyle(style string) bool {
+	for _, s := range voice.StyleList {
+		if strings.EqualFold(s, style) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON serializes the voice information, without its native handle and property collection.
+func (voice VoiceInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name             string
+		Locale           string
+		ShortName        string
+		LocalName        string
+		Gender           string
+		VoiceType        string
+		StyleList        []string `json:",omitempty"`
+		SecondaryLocales []string `json:",omitempty"`
+		RolePlayList     []string `json:",omitempty"`
+		SampleRateHertz  int      `json:",omitempty"`
+		WordsPerMinute   int      `json:",omitempty"`
+		VoicePath        string   `json:",omitempty"`
+	}{
+		Name:             voice.Name,
+		Locale:           voice.Locale,
+		ShortName:        voice.ShortName,
+		LocalName:        voice.LocalName,
+		Gender:           voice.Gender.String(),
+		VoiceType:        voice.VoiceType.String(),
+		StyleList:        nonEmptyStrings(voice.StyleList),
+		SecondaryLocales: voice.SecondaryLocales,
+		RolePlayList:     voice.RolePlayList,
+		SampleRateHertz:  voice.SampleRateHertz,
+		WordsPerMinute:   voice.WordsPerMinute,
+		VoicePath:        voice.VoicePath,
+	})
+}
+
+// MarshalJSON serializes the voices list result, including all retrieved voices.
+func (result SynthesisVoicesResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ResultID     string
+		Reason       string
+		ErrorDetails string `json:",omitempty"`
+		Voices       []*VoiceInfo
+	}{
+		ResultID:     result.ResultID,
+		Reason:       result.Reason.String(),
+		ErrorDetails: result.ErrorDetails,
+		Voices:       result.Voices,
+	})
+}
+
+// FilterVoices returns the voices that match the filter, in the order they were retrieved.
+func (result SynthesisVoicesResult) FilterVoices(filter VoiceFilter) []*VoiceInfo {
+	var voices []*VoiceInfo
+	for _, voice := range result.Voices {
+		if filter.Matches(voice) {
+			voices = append(voices, voice)
+		}
+	}
+	return voices
+}
+
+// VoicesForLocale returns the voices that speak the given locale, as primary or secondary locale.
+func (result SynthesisVoicesResult) VoicesForLocale(locale string) []*VoiceInfo {
+	return result.FilterVoices(VoiceFilter{Locale: locale})
+}
+
+// VoicesForGender returns the voices of the given gender.
+func (result SynthesisVoicesResult) VoicesForGender(gender common.SynthesisVoiceGender) []*VoiceInfo {
+	return result.FilterVoices(VoiceFilter{Gender: gender})
+}
+
+// VoicesForType returns the voices of any of the given types.
+func (result SynthesisVoicesResult) VoicesForType(voiceTypes ...common.SynthesisVoiceType) []*VoiceInfo {
+	return result.FilterVoices(VoiceFilter{VoiceTypes: voiceTypes})
+}
+
+// VoicesWithStyle returns the voices that support the given speaking style.
+func (result SynthesisVoicesResult) VoicesWithStyle(style string) []*VoiceInfo {
+	return result.FilterVoices(VoiceFilter{Style: style})
+}
+
+// SelectVoice picks the best voice for a BCP-47 language tag that also matches the filter (whose Locale is ignored).
+// When no voice speaks the requested locale, related locales are tried in turn, e.g. en-AU falls back to en-GB and
+// then en-US, and finally any voice of the same language. Within a locale, voices with a matching primary locale are
+// preferred over secondary locale matches, and neural voices over standard ones.
+// It returns nil if no voice qualifies.
+func (result SynthesisVoicesResult) SelectVoice(locale string, filter VoiceFilter) *VoiceInfo {
+	filter.Locale = ""
+	candidates := result.FilterVoices(filter)
+	for _, fallback := range voiceLocaleFallbackChain(locale) {
+		var matches []*VoiceInfo
+		for _, voice := range candidates {
+			if voice.SupportsLocale(fallback) {
+				matches = append(matches, voice)
+			}
+		}
+		if len(matches) > 0 {
+			sortVoicesByPreference(matches, fallback)
+			return matches[0]
+		}
+	}
+	language := voiceLanguage(locale)
+	var matches []*VoiceInfo
+	for _, voice := range candidates {
+		if strings.EqualFold(voiceLanguage(voice.Locale), language) {
+			matches = append(matches, voice)
+		}
+	}
+	if len(matches) > 0 {
+		sortVoicesByPreference(matches, "")
+		return matches[0]
+	}
+	return nil
+}
+
+// voiceLocaleFallbacks lists, per lower-cased locale, the related locales to try before the language default.
+var voiceLocaleFallbacks = map[string][]string{
+	"en-au": {"en-GB"},
+	"en-nz": {"en-AU", "en-GB"},
+	"en-ie": {"en-GB"},
+	"en-in": {"en-GB"},
+	"en-za": {"en-GB"},
+	"en-sg": {"en-GB"},
+	"en-hk": {"en-GB"},
+	"en-ca": {"en-US"},
+	"fr-be": {"fr-FR"},
+	"fr-ch": {"fr-FR"},
+	"fr-ca": {"fr-FR"},
+	"de-at": {"de-DE"},
+	"de-ch": {"de-DE"},
+	"es-us": {"es-MX"},
+	"zh-hk": {"zh-TW"},
+	"nl-be": {"nl-NL"},
+}
+
+// voiceLanguageDefaults lists, per lower-cased language, the locale to fall back to when no related locale matches.
+var voiceLanguageDefaults = map[string]string{
+	"ar": "ar-SA",
+	"de": "de-DE",
+	"en": "en-US",
+	"es": "es-ES",
+	"fr": "fr-FR",
+	"it": "it-IT",
+	"ja": "ja-JP",
+	"ko": "ko-KR",
+	"nl": "nl-NL",
+	"pt": "pt-BR",
+	"ru": "ru-RU",
+	"zh": "zh-CN",
+}
+
+// voiceLocaleFallbackChain returns the ordered list of locales to try for a tag, starting with the tag itself.
+func voiceLocaleFallbackChain(locale string) []string {
+	chain := []string{locale}
+	chain = append(chain, voiceLocaleFallbacks[strings.ToLower(locale)]...)
+	if def, ok := voiceLanguageDefaults[strings.ToLower(voiceLanguage(locale))]; ok {
+		chain = append(chain, def)
+	}
+	return chain
+}
+
+// voiceLanguage returns the primary language subtag of a BCP-47 tag.
+func voiceLanguage(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// voiceTypePreference ranks voice types, lower is better.
+func voiceTypePreference(voiceType common.SynthesisVoiceType) int {
+	switch voiceType {
+	case common.OnlineNeural:
+		return 0
+	case common.OnlineNeuralHD:
+		return 1
+	case common.OfflineNeural:
+		return 2
+	case common.OnlineStandard:
+		return 3
+	case common.OfflineStandard:
+		return 4
+	}
+	return 5
+}
+
+func sortVoicesByPreference(voices []*VoiceInfo, locale string) {
+	sort.SliceStable(voices, func(i, j int) bool {
+		if locale != "" {
+			iPrimary := strings.EqualFold(voices[i].Locale, locale)
+			jPrimary := strings.EqualFold(voices[j].Locale, locale)
+			if iPrimary != jPrimary {
+				return iPrimary
+			}
+		}
+		return voiceTypePreference(voices[i].VoiceType) < voiceTypePreference(voices[j].VoiceType)
+	})
+}
+
+// nonEmptyStrings drops empty entries, which appear when splitting an empty list property.
+func nonEmptyStrings(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
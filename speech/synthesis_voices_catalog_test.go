@@ -0,0 +1,117 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+func createVoiceCatalog() SynthesisVoicesResult {
+	return SynthesisVoicesResult{
+		ResultID: "result",
+		Reason:   common.VoicesListRetrieved,
+		Voices: []*VoiceInfo{
+			{ShortName: "en-US-GuyStandard", Locale: "en-US", Gender: common.Male, VoiceType: common.OnlineStandard},
+			{ShortName: "en-US-JennyNeural", Locale: "en-US", Gender: common.Female, VoiceType: common.OnlineNeural, StyleList: []string{"cheerful", "sad"}},
+			{ShortName: "en-GB-RyanNeural", Locale: "en-GB", Gender: common.Male, VoiceType: common.OnlineNeural},
+			{ShortName: "de-DE-KatjaNeural", Locale: "de-DE", Gender: common.Female, VoiceType: common.OfflineNeural},
+			{ShortName: "fr-FR-MultilingualNeural", Locale: "fr-FR", Gender: common.Female, VoiceType: common.OnlineNeural, SecondaryLocales: []string{"en-AU"}},
+			{ShortName: "es-MX-DaliaNeural", Locale: "es-MX", Gender: common.Female, VoiceType: common.OnlineNeural},
+			{ShortName: "it-IT-IsabellaDragonHDLatestNeural", Locale: "it-IT", Gender: common.Female, VoiceType: common.OnlineNeuralHD},
+		},
+	}
+}
+
+func voiceNames(voices []*VoiceInfo) string {
+	names := make([]string, len(voices))
+	for i, voice := range voices {
+		names[i] = voice.ShortName
+	}
+	return strings.Join(names, ",")
+}
+
+func TestVoiceFilter(t *testing.T) {
+	catalog := createVoiceCatalog()
+	if names := voiceNames(catalog.VoicesForLocale("EN-us")); names != "en-US-GuyStandard,en-US-JennyNeural" {
+		t.Error("Unexpected voices for locale: ", names)
+	}
+	if names := voiceNames(catalog.VoicesForLocale("en-AU")); names != "fr-FR-MultilingualNeural" {
+		t.Error("Unexpected voices for secondary locale: ", names)
+	}
+	if names := voiceNames(catalog.VoicesForGender(common.Male)); names != "en-US-GuyStandard,en-GB-RyanNeural" {
+		t.Error("Unexpected voices for gender: ", names)
+	}
+	if names := voiceNames(catalog.VoicesForType(OfflineVoiceTypes...)); names != "de-DE-KatjaNeural" {
+		t.Error("Unexpected voices for type: ", names)
+	}
+	if names := voiceNames(catalog.VoicesForType(common.OnlineNeuralHD)); names != "it-IT-IsabellaDragonHDLatestNeural" {
+		t.Error("Unexpected voices for neural HD type: ", names)
+	}
+	if names := voiceNames(catalog.FilterVoices(VoiceFilter{Locale: "it-IT", VoiceTypes: OnlineVoiceTypes})); names != "it-IT-IsabellaDragonHDLatestNeural" {
+		t.Error("Unexpected voices for online type: ", names)
+	}
+	if names := voiceNames(catalog.VoicesWithStyle("Cheerful")); names != "en-US-JennyNeural" {
+		t.Error("Unexpected voices for style: ", names)
+	}
+	filter := VoiceFilter{Locale: "en-US", Gender: common.Female, VoiceTypes: OnlineVoiceTypes}
+	if names := voiceNames(catalog.FilterVoices(filter)); names != "en-US-JennyNeural" {
+		t.Error("Unexpected voices for combined filter: ", names)
+	}
+}
+
+func TestSelectVoice(t *testing.T) {
+	catalog := createVoiceCatalog()
+	cases := []struct {
+		locale   string
+		filter   VoiceFilter
+		expected string
+	}{
+		{"en-US", VoiceFilter{}, "en-US-JennyNeural"},
+		{"en-AU", VoiceFilter{}, "fr-FR-MultilingualNeural"},
+		{"en-AU", VoiceFilter{Gender: common.Male}, "en-GB-RyanNeural"},
+		{"en-CA", VoiceFilter{Gender: common.Male}, "en-US-GuyStandard"},
+		{"en", VoiceFilter{}, "en-US-JennyNeural"},
+		{"es-AR", VoiceFilter{}, "es-MX-DaliaNeural"},
+		{"de-CH", VoiceFilter{}, "de-DE-KatjaNeural"},
+	}
+	for _, c := range cases {
+		voice := catalog.SelectVoice(c.locale, c.filter)
+		if voice == nil || voice.ShortName != c.expected {
+			t.Errorf("SelectVoice(%s): expected %s, got %v", c.locale, c.expected, voice)
+		}
+	}
+	if voice := catalog.SelectVoice("ja-JP", VoiceFilter{}); voice != nil {
+		t.Error("Expected no voice, got ", voice.ShortName)
+	}
+}
+
+func TestSynthesisVoicesResultJSON(t *testing.T) {
+	catalog := createVoiceCatalog()
+	data, err := json.Marshal(catalog)
+	if err != nil {
+		t.Error("Got an error: ", err)
+	}
+	var decoded struct {
+		Reason string
+		Voices []struct {
+			ShortName string
+			Gender    string
+			VoiceType string
+			StyleList []string
+		}
+	}
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Error("Got an error: ", err)
+	}
+	if decoded.Reason != "VoicesListRetrieved" || len(decoded.Voices) != len(catalog.Voices) {
+		t.Error("Unexpected JSON: ", string(data))
+	}
+	if decoded.Voices[1].Gender != "Female" || decoded.Voices[1].VoiceType != "OnlineNeural" || len(decoded.Voices[1].StyleList) != 2 {
+		t.Error("Unexpected voice JSON: ", string(data))
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/internal/spans"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/tracing"
+)
+
+// #include <speechapi_c_common.h>
+import "C"
+
+// The helpers below report to the tracing package. Tracing relies on the session, speech start, recognized and
+// canceled events, so the recognizer start methods connect the native callbacks of those events while a tracer is
+// set, without registering a handler. Events without a handler are traced and released. Final and canceled results
+// are traced from the events in continuous recognition, and from the returned result in single-shot recognition.
+
+// resultEventTraces holds the recognizers whose results are traced from their recognized and canceled events.
+var resultEventTraces = make(map[C.SPXHANDLE]bool)
+
+func handleKey(handle C.SPXHANDLE) uintptr {
+	return uintptr(handle2uintptr(handle))
+}
+
+func setResultEventTrace(handle C.SPXHANDLE, traced bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if traced {
+		resultEventTraces[handle] = true
+	} else {
+		delete(resultEventTraces, handle)
+	}
+}
+
+func traceResultEvents(handle C.SPXHANDLE) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return resultEventTraces[handle]
+}
+
+func (recognizer SpeechRecognizer) beginTrace(ctx context.Context, continuous bool) {
+	if tracing.CurrentTracer() == nil {
+		return
+	}
+	recognizer.connectTracedEvents(continuous)
+	setResultEventTrace(recognizer.handle, continuous)
+	spans.Begin(ctx, handleKey(recognizer.handle),
+		tracing.String(tracing.AttrRecognizer, metrics.SpeechRecognizer),
+		tracing.String(tracing.AttrLanguage, recognizer.Properties.GetProperty(common.SpeechServiceConnectionRecoLanguage, "")))
+}
+
+func (recognizer TranslationRecognizer) beginTrace(ctx context.Context, continuous bool) {
+	if tracing.CurrentTracer() == nil {
+		return
+	}
+	recognizer.connectTracedEvents(continuous)
+	setResultEventTrace(recognizer.handle, continuous)
+	spans.Begin(ctx, handleKey(recognizer.handle),
+		tracing.String(tracing.AttrRecognizer, metrics.TranslationRecognizer),
+		tracing.String(tracing.AttrLanguage, recognizer.Properties.GetProperty(common.SpeechServiceConnectionRecoLanguage, "")),
+		tracing.String(tracing.AttrTargetLanguages, strings.Join(recognizer.GetTargetLanguages(), ",")))
+}
+
+func (transcriber ConversationTranscriber) beginTrace(ctx context.Context) {
+	if tracing.CurrentTracer() == nil {
+		return
+	}
+	transcriber.connectTracedEvents()
+	setResultEventTrace(transcriber.handle, true)
+	spans.Begin(ctx, handleKey(transcriber.handle),
+		tracing.String(tracing.AttrRecognizer, metrics.ConversationTranscriber),
+		tracing.String(tracing.AttrLanguage, transcriber.Properties.GetProperty(common.SpeechServiceConnectionRecoLanguage, "")))
+}
+
+func traceSessionStarted(handle C.SPXHANDLE, sessionID string) {
+	spans.SessionStarted(handleKey(handle), sessionID)
+}
+
+func traceSessionStopped(handle C.SPXHANDLE) {
+	spans.SessionStopped(handleKey(handle))
+}
+
+func traceUtteranceStarted(handle C.SPXHANDLE) {
+	spans.UtteranceStarted(handleKey(handle))
+}
+
+func traceEnd(handle C.SPXHANDLE) {
+	setResultEventTrace(handle, false)
+	spans.End(handleKey(handle))
+}
+
+// traceRecognitionResult ends the utterance span with a final or canceled recognition result.
+func traceRecognitionResult(handle C.SPXHANDLE, result *SpeechRecognitionResult) {
+	if result == nil || !spans.Active(handleKey(handle)) {
+		return
+	}
+	if result.Reason != common.Canceled {
+		spans.UtteranceEnded(handleKey(handle), recognitionResultAttributes(result)...)
+		return
+	}
+	if details, err := NewCancellationDetailsFromSpeechRecognitionResult(result); err == nil {
+		traceRecognitionCanceled(handle, result, details.Err())
+	}
+}
+
+// traceRecognitionCanceled marks the spans of the recognizer as canceled, and failed if the cancellation is caused
+// by an error.
+func traceRecognitionCanceled(handle C.SPXHANDLE, result *SpeechRecognitionResult, canceled *common.CancellationError) {
+	key := handleKey(handle)
+	if !spans.Active(key) {
+		return
+	}
+	attributes := append(recognitionResultAttributes(result), cancellationAttributes(canceled)...)
+	spans.Canceled(key, spanError(canceled), attributes...)
+}
+
+func recognitionResultAttributes(result *SpeechRecognitionResult) []tracing.Attribute {
+	attributes := []tracing.Attribute{
+		tracing.String(tracing.AttrResultID, result.ResultID),
+		tracing.String(tracing.AttrReason, result.Reason.String()),
+		tracing.Int64(tracing.AttrOffsetMs, int64(result.Offset/time.Millisecond)),
+		tracing.Int64(tracing.AttrDurationMs, int64(result.Duration/time.Millisecond)),
+	}
+	if result.Properties == nil {
+		return attributes
+	}
+	if latency, err := strconv.ParseInt(result.Properties.GetProperty(common.SpeechServiceResponseRecognitionLatencyMs, ""), 10, 64); err == nil {
+		attributes = append(attributes, tracing.Int64(tracing.AttrRecognitionLatencyMs, latency))
+	}
+	if language := result.Properties.GetProperty(common.SpeechServiceConnectionAutoDetectSourceLanguageResult, ""); language != "" {
+		attributes = append(attributes, tracing.String(tracing.AttrLanguage, language))
+	}
+	return attributes
+}
+
+func cancellationAttributes(canceled *common.CancellationError) []tracing.Attribute {
+	return []tracing.Attribute{
+		tracing.String(tracing.AttrCancellationReason, canceled.Reason.String()),
+		tracing.String(tracing.AttrCancellationErrorCode, canceled.ErrorCode.String()),
+		tracing.String(tracing.AttrCancellationDetails, canceled.ErrorDetails),
+	}
+}
+
+// spanError is the error marking a span as failed, which excludes cancellations without an error, e.g. at the end
+// of the audio stream.
+func spanError(err error) error {
+	var canceled *common.CancellationError
+	if errors.As(err, &canceled) && canceled.Reason != common.Error {
+		return nil
+	}
+	return err
+}
+
+// startSynthesisTrace starts the span of a synthesis request.
+func (synthesizer SpeechSynthesizer) startSynthesisTrace(ctx context.Context) tracing.Span {
+	if tracing.CurrentTracer() == nil {
+		_, span := spans.StartSpan(ctx, tracing.SynthesisSpan)
+		return span
+	}
+	_, span := spans.StartSpan(ctx, tracing.SynthesisSpan,
+		tracing.String(tracing.AttrRecognizer, metrics.SpeechSynthesizer),
+		tracing.String(tracing.AttrLanguage, synthesizer.Properties.GetProperty(common.SpeechServiceConnectionSynthLanguage, "")),
+		tracing.String(tracing.AttrVoice, synthesizer.Properties.GetProperty(common.SpeechServiceConnectionSynthVoice, "")))
+	return span
+}
+
+// endSynthesisTrace ends the span of a synthesis request with its result and error.
+func endSynthesisTrace(span tracing.Span, result *SpeechSynthesisResult, err error) {
+	defer span.End()
+	if err = spanError(err); err != nil {
+		span.SetError(err)
+	}
+	if result == nil {
+		return
+	}
+	span.SetAttributes(
+		tracing.String(tracing.AttrResultID, result.ResultID),
+		tracing.String(tracing.AttrReason, result.Reason.String()))
+	for _, latency := range []struct {
+		key string
+		id  common.PropertyID
+	}{
+		{tracing.AttrFirstByteLatencyMs, common.SpeechServiceResponseSynthesisFirstByteLatencyMs},
+		{tracing.AttrFinishLatencyMs, common.SpeechServiceResponseSynthesisFinishLatencyMs},
+		{tracing.AttrNetworkLatencyMs, common.SpeechServiceResponseSynthesisNetworkLatencyMs},
+		{tracing.AttrServiceLatencyMs, common.SpeechServiceResponseSynthesisServiceLatencyMs},
+	} {
+		if value, err := strconv.ParseInt(result.Properties.GetProperty(latency.id, ""), 10, 64); err == nil {
+			span.SetAttributes(tracing.Int64(latency.key, value))
+		}
+	}
+	if result.Reason == common.Canceled {
+		if details, err := NewCancellationDetailsFromSpeechSynthesisResult(result); err == nil {
+			span.SetAttributes(cancellationAttributes(details.Err())...)
+		}
+	}
+}
@@ -5,6 +5,8 @@ package speech
 
 import (
 	"sync"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
 )
 
 // #include <stdlib.h>
@@ -33,12 +35,24 @@ func registerTranslationRecognizedCallback(callback TranslationRecognitionEventH
 	translationRecognizedCallbacks[handle] = callback
 }
 
+func getTranslationRecognizedCallback(handle C.SPXHANDLE) TranslationRecognitionEventHandler {
+	translationCallbacksLock.Lock()
+	defer translationCallbacksLock.Unlock()
+	return translationRecognizedCallbacks[handle]
+}
+
 func registerTranslationCanceledCallback(callback TranslationRecognitionCanceledEventHandler, handle C.SPXHANDLE) {
 	translationCallbacksLock.Lock()
 	defer translationCallbacksLock.Unlock()
 	translationCanceledCallbacks[handle] = callback
 }
 
+func getTranslationCanceledCallback(handle C.SPXHANDLE) TranslationRecognitionCanceledEventHandler {
+	translationCallbacksLock.Lock()
+	defer translationCallbacksLock.Unlock()
+	return translationCanceledCallbacks[handle]
+}
+
 func registerTranslationSynthesisCallback(callback TranslationSynthesisEventHandler, handle C.SPXHANDLE) {
 	translationCallbacksLock.Lock()
 	defer translationCallbacksLock.Unlock()
@@ -52,6 +66,9 @@ func cgoTranslationRecognizing(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHAN
 	translationCallbacksLock.Unlock()
 	if callback != nil {
 		eventArgs, _ := NewTranslationRecognitionEventArgsFromHandle(handle2uintptr(eventHandle))
+		if eventArgs != nil {
+			recordPartial(metrics.TranslationRecognizer, handle)
+		}
 		callback(*eventArgs)
 	}
 }
@@ -61,10 +78,20 @@ func cgoTranslationRecognized(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHAND
 	translationCallbacksLock.Lock()
 	callback := translationRecognizedCallbacks[handle]
 	translationCallbacksLock.Unlock()
-	if callback != nil {
-		eventArgs, _ := NewTranslationRecognitionEventArgsFromHandle(handle2uintptr(eventHandle))
-		callback(*eventArgs)
+	eventArgs, _ := NewTranslationRecognitionEventArgsFromHandle(handle2uintptr(eventHandle))
+	if eventArgs == nil {
+		return
+	}
+	if traceResultEvents(handle) {
+		traceRecognitionResult(handle, &eventArgs.Result.SpeechRecognitionResult)
+	}
+	recordRecognitionResult(metrics.TranslationRecognizer, &eventArgs.Result.SpeechRecognitionResult)
+	if callback == nil {
+		eventArgs.Close()
+		eventArgs.Result.Close()
+		return
 	}
+	callback(*eventArgs)
 }
 
 //export cgoTranslationCanceled
@@ -72,10 +99,22 @@ func cgoTranslationCanceled(handle C.SPXRECOHANDLE, eventHandle C.SPXEVENTHANDLE
 	translationCallbacksLock.Lock()
 	callback := translationCanceledCallbacks[handle]
 	translationCallbacksLock.Unlock()
-	if callback != nil {
-		eventArgs, _ := NewTranslationRecognitionCanceledEventArgsFromHandle(handle2uintptr(eventHandle))
-		callback(*eventArgs)
+	eventArgs, _ := NewTranslationRecognitionCanceledEventArgsFromHandle(handle2uintptr(eventHandle))
+	if eventArgs == nil {
+		return
+	}
+	if traceResultEvents(handle) {
+		traceRecognitionCanceled(handle, &eventArgs.Result.SpeechRecognitionResult, eventArgs.Err())
+	}
+	cancelLimitedSession(handle, eventArgs.Err())
+	recordRecognitionStopped(handle)
+	recordRecognitionCanceled(metrics.TranslationRecognizer, eventArgs.Result.ResultID, eventArgs.Err())
+	if callback == nil {
+		eventArgs.Close()
+		eventArgs.Result.Close()
+		return
 	}
+	callback(*eventArgs)
 }
 
 //export cgoTranslationSynthesis
@@ -7,6 +7,7 @@ import (
 	"unsafe"
 
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/diagnostics/logging"
 )
 
 // #include <stdlib.h>
@@ -148,6 +149,10 @@ type TranslationRecognitionCanceledEventArgs struct {
 	ErrorDetails string
 	Reason       common.CancellationReason
 	ErrorCode    common.CancellationErrorCode
+
+	// SessionLog is the log captured for the session when it was canceled with an error and a session log capture
+	// is enabled (see logging.EnableSessionLogCapture).
+	SessionLog *logging.SessionLog
 }
 
 // NewTranslationRecognitionCanceledEventArgsFromHandle creates a TranslationRecognitionCanceledEventArgs from a handle.
@@ -178,10 +183,18 @@ func NewTranslationRecognitionCanceledEventArgsFromHandle(handle common.SPXHandl
 	event.ErrorDetails = event.Result.Properties.GetProperty(common.SpeechServiceResponseJSONErrorDetails, "")
 	event.ErrorCode = (common.CancellationErrorCode)(errorCode)
 	event.Reason = (common.CancellationReason)(reason)
+	if event.Reason == common.Error {
+		event.SessionLog = logging.CapturedSessionLog(event.SessionID)
+	}
 
 	return event, nil
 }
 
+// Err returns the cancellation as an error.
+func (event TranslationRecognitionCanceledEventArgs) Err() *common.CancellationError {
+	return &common.CancellationError{Reason: event.Reason, ErrorCode: event.ErrorCode, ErrorDetails: event.ErrorDetails, SessionID: event.SessionID}
+}
+
 // TranslationSynthesisEventArgs represents the event arguments for a translation synthesis event.
 type TranslationSynthesisEventArgs struct {
 	SessionEventArgs
@@ -4,11 +4,14 @@
 package speech
 
 import (
+	"context"
 	"math"
+	"strings"
 	"unsafe"
 
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/metrics"
 )
 
 // #include <stdlib.h>
@@ -30,13 +33,15 @@ import "C"
 
 // TranslationRecognizer is the class for translation recognizers.
 type TranslationRecognizer struct {
+	common.RecognitionProperties
 	Properties                 *common.PropertyCollection
 	handle                     C.SPXHANDLE
 	handleAsyncStartContinuous C.SPXASYNCHANDLE
 	handleAsyncStopContinuous  C.SPXASYNCHANDLE
+	limiter                    SessionLimiter
 }
 
-func newTranslationRecognizerFromHandle(handle C.SPXHANDLE) (*TranslationRecognizer, error) {
+func newTranslationRecognizerFromHandle(handle C.SPXHANDLE, limiter SessionLimiter) (*TranslationRecognizer, error) {
 	var propBagHandle C.SPXHANDLE
 	ret := uintptr(C.recognizer_get_property_bag(handle, &propBagHandle))
 	if ret != C.SPX_NOERROR {
@@ -44,9 +49,11 @@ func newTranslationRecognizerFromHandle(handle C.SPXHANDLE) (*TranslationRecogni
 	}
 	recognizer := new(TranslationRecognizer)
 	recognizer.handle = handle
+	recognizer.limiter = limiter
 	recognizer.handleAsyncStartContinuous = C.SPXHANDLE_INVALID
 	recognizer.handleAsyncStopContinuous = C.SPXHANDLE_INVALID
 	recognizer.Properties = common.NewPropertyCollectionFromHandle(handle2uintptr(propBagHandle))
+	recognizer.RecognitionProperties = common.NewRecognitionPropertiesFromCollection(recognizer.Properties)
 	return recognizer, nil
 }
 
@@ -67,7 +74,7 @@ func NewTranslationRecognizerFromConfig(config *SpeechTranslationConfig, audioCo
 	if ret != C.SPX_NOERROR {
 		return nil, common.NewCarbonError(ret)
 	}
-	return newTranslationRecognizerFromHandle(handle)
+	return newTranslationRecognizerFromHandle(handle, config.limiter)
 }
 
 // NewTranslationRecognizerFromEmbeddedConfig creates a translation recognizer from an embedded (offline)
@@ -89,7 +96,7 @@ func NewTranslationRecognizerFromEmbeddedConfig(config *EmbeddedSpeechConfig, au
 	if ret != C.SPX_NOERROR {
 		return nil, common.NewCarbonError(ret)
 	}
-	return newTranslationRecognizerFromHandle(handle)
+	return newTranslationRecognizerFromHandle(handle, config.limiter)
 }
 
 // NewTranslationRecognizerFromAutoDetectSourceLangConfig creates a translation recognizer from a speech translation config, auto detection source language config and audio config.
@@ -113,7 +120,7 @@ func NewTranslationRecognizerFromAutoDetectSourceLangConfig(config *SpeechTransl
 	if ret != C.SPX_NOERROR {
 		return nil, common.NewCarbonError(ret)
 	}
-	return newTranslationRecognizerFromHandle(handle)
+	return newTranslationRecognizerFromHandle(handle, config.limiter)
 }
 
 // RecognizeOnceAsync starts translation recognition, and returns after a single utterance is recognized.
@@ -122,15 +129,42 @@ func NewTranslationRecognizerFromAutoDetectSourceLangConfig(config *SpeechTransl
 // Note: Since RecognizeOnceAsync() returns only a single utterance, it is suitable only for single
 // shot recognition like command or query.
 // For long-running multi-utterance recognition, use StartContinuousRecognitionAsync() instead.
+// When the result is canceled, the outcome holds both the result and a *common.CancellationError.
 func (recognizer TranslationRecognizer) RecognizeOnceAsync() chan TranslationRecognitionOutcome {
+	return recognizer.RecognizeOnceWithContextAsync(context.Background())
+}
+
+// RecognizeOnceWithContextAsync is like RecognizeOnceAsync, with ctx as the parent of the trace spans of the
+// recognition (see the tracing package). The recognition is not canceled with ctx.
+func (recognizer TranslationRecognizer) RecognizeOnceWithContextAsync(ctx context.Context) chan TranslationRecognitionOutcome {
 	outcome := make(chan TranslationRecognitionOutcome)
+	recognizer.beginTrace(ctx, false)
 	go func() {
+		release, err := acquireSession(ctx, recognizer.limiter, "", false)
+		if err != nil {
+			traceEnd(recognizer.handle)
+			outcome <- TranslationRecognitionOutcome{Result: nil, OperationOutcome: common.OperationOutcome{err}}
+			return
+		}
 		var handle C.SPXRESULTHANDLE
+		recordRecognitionStarted(metrics.TranslationRecognizer, recognizer.handle)
 		ret := uintptr(C.recognizer_recognize_once(recognizer.handle, &handle))
+		recordRecognitionStopped(recognizer.handle)
 		if ret != C.SPX_NOERROR {
-			outcome <- TranslationRecognitionOutcome{Result: nil, OperationOutcome: common.OperationOutcome{common.NewCarbonError(ret)}}
+			err := common.NewCarbonError(ret)
+			release(err)
+			outcome <- TranslationRecognitionOutcome{Result: nil, OperationOutcome: common.OperationOutcome{err}}
 		} else {
 			result, err := NewTranslationRecognitionResultFromHandle(handle2uintptr(handle))
+			if result != nil {
+				if err == nil {
+					err = canceledRecognitionError(&result.SpeechRecognitionResult, recognizer.Properties)
+				}
+				recordRecognitionResult(metrics.TranslationRecognizer, &result.SpeechRecognitionResult)
+				traceRecognitionResult(recognizer.handle, &result.SpeechRecognitionResult)
+			}
+			release(err)
+			traceEnd(recognizer.handle)
 			outcome <- TranslationRecognitionOutcome{Result: result, OperationOutcome: common.OperationOutcome{err}}
 		}
 	}()
@@ -139,11 +173,25 @@ func (recognizer TranslationRecognizer) RecognizeOnceAsync() chan TranslationRec
 
 // StartContinuousRecognitionAsync asynchronously initiates continuous translation recognition operation.
 func (recognizer TranslationRecognizer) StartContinuousRecognitionAsync() chan error {
+	return recognizer.StartContinuousRecognitionWithContextAsync(context.Background())
+}
+
+// StartContinuousRecognitionWithContextAsync is like StartContinuousRecognitionAsync, with ctx as the parent of the
+// trace spans of the sessions and utterances (see the tracing package). The recognition is not stopped with ctx.
+func (recognizer TranslationRecognizer) StartContinuousRecognitionWithContextAsync(ctx context.Context) chan error {
 	outcome := make(chan error)
+	recognizer.beginTrace(ctx, true)
 	go func() {
+		if err := startLimitedSession(ctx, recognizer.limiter, recognizer.handle, recognizer.connectLimitedEvents); err != nil {
+			traceEnd(recognizer.handle)
+			outcome <- err
+			return
+		}
 		// Close any unfinished previous attempt
 		ret := releaseAsyncHandleIfValid(&recognizer.handleAsyncStartContinuous)
 		if ret == C.SPX_NOERROR {
+			recognizer.connectRecordedEvents()
+			recordRecognitionStarted(metrics.TranslationRecognizer, recognizer.handle)
 			ret = uintptr(C.recognizer_start_continuous_recognition_async(recognizer.handle, &recognizer.handleAsyncStartContinuous))
 		}
 		if ret == C.SPX_NOERROR {
@@ -151,6 +199,7 @@ func (recognizer TranslationRecognizer) StartContinuousRecognitionAsync() chan e
 		}
 		releaseAsyncHandleIfValid(&recognizer.handleAsyncStartContinuous)
 		if ret != C.SPX_NOERROR {
+			endLimitedSession(recognizer.handle)
 			outcome <- common.NewCarbonError(ret)
 			return
 		}
@@ -171,6 +220,8 @@ func (recognizer TranslationRecognizer) StopContinuousRecognitionAsync() chan er
 			ret = uintptr(C.recognizer_stop_continuous_recognition_async_wait_for(recognizer.handleAsyncStopContinuous, math.MaxUint32))
 		}
 		releaseAsyncHandleIfValid(&recognizer.handleAsyncStopContinuous)
+		recordRecognitionStopped(recognizer.handle)
+		endLimitedSession(recognizer.handle)
 		if ret != C.SPX_NOERROR {
 			outcome <- common.NewCarbonError(ret)
 			return
@@ -198,6 +249,39 @@ func (recognizer TranslationRecognizer) AuthorizationToken() string {
 	return recognizer.Properties.GetProperty(common.SpeechServiceAuthorizationToken, "")
 }
 
+// AddTargetLanguage adds a target language for translation. Unlike SpeechTranslationConfig.AddTargetLanguage,
+// it applies to this recognizer and can be called while recognition is running.
+func (recognizer TranslationRecognizer) AddTargetLanguage(language string) error {
+	languageCStr := C.CString(language)
+	defer C.free(unsafe.Pointer(languageCStr))
+	ret := uintptr(C.translator_add_target_language(recognizer.handle, languageCStr))
+	if ret != C.SPX_NOERROR {
+		return common.NewCarbonError(ret)
+	}
+	return nil
+}
+
+// RemoveTargetLanguage removes a target language for translation. Unlike SpeechTranslationConfig.RemoveTargetLanguage,
+// it applies to this recognizer and can be called while recognition is running.
+func (recognizer TranslationRecognizer) RemoveTargetLanguage(language string) error {
+	languageCStr := C.CString(language)
+	defer C.free(unsafe.Pointer(languageCStr))
+	ret := uintptr(C.translator_remove_target_language(recognizer.handle, languageCStr))
+	if ret != C.SPX_NOERROR {
+		return common.NewCarbonError(ret)
+	}
+	return nil
+}
+
+// GetTargetLanguages gets the current target languages of the recognizer.
+func (recognizer TranslationRecognizer) GetTargetLanguages() []string {
+	languages := recognizer.Properties.GetProperty(common.SpeechServiceConnectionTranslationToLanguages, "")
+	if languages == "" {
+		return []string{}
+	}
+	return strings.Split(languages, ",")
+}
+
 // SessionStarted signals events indicating the start of a recognition session (operation).
 func (recognizer TranslationRecognizer) SessionStarted(handler SessionEventHandler) {
 	registerSessionStartedCallback(handler, recognizer.handle)
@@ -305,8 +389,62 @@ func (recognizer TranslationRecognizer) Synthesizing(handler TranslationSynthesi
 	}
 }
 
+// connectTracedEvents connects the native callbacks of the events traced during a recognition for which no handler
+// is set. The recognized and canceled events are only traced in continuous recognition.
+func (recognizer TranslationRecognizer) connectTracedEvents(continuous bool) {
+	handle := recognizer.handle
+	if getSessionStartedCallback(handle) == nil {
+		C.recognizer_session_started_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_session_started)), nil)
+	}
+	if getSessionStoppedCallback(handle) == nil {
+		C.recognizer_session_stopped_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_session_stopped)), nil)
+	}
+	if getSpeechStartDetectedCallback(handle) == nil {
+		C.recognizer_speech_start_detected_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_speech_start_detected)), nil)
+	}
+	if !continuous {
+		return
+	}
+	if getTranslationRecognizedCallback(handle) == nil {
+		C.recognizer_recognized_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_translation_recognizer_recognized)), nil)
+	}
+	if getTranslationCanceledCallback(handle) == nil {
+		C.recognizer_canceled_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_translation_recognizer_canceled)), nil)
+	}
+}
+
+// connectRecordedEvents connects the native callbacks of the recognized and canceled events for which no handler is
+// set when a metrics hook is set, so that the results of a continuous recognition are recorded.
+func (recognizer TranslationRecognizer) connectRecordedEvents() {
+	if metrics.CurrentHook() == nil {
+		return
+	}
+	handle := recognizer.handle
+	if getTranslationRecognizedCallback(handle) == nil {
+		C.recognizer_recognized_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_translation_recognizer_recognized)), nil)
+	}
+	if getTranslationCanceledCallback(handle) == nil {
+		C.recognizer_canceled_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_translation_recognizer_canceled)), nil)
+	}
+}
+
+// connectLimitedEvents connects the native callbacks of the canceled and session stopped events for which no handler
+// is set, so that the session of a continuous recognition is released when it stops, with the error of its
+// cancellation.
+func (recognizer TranslationRecognizer) connectLimitedEvents() {
+	handle := recognizer.handle
+	if getTranslationCanceledCallback(handle) == nil {
+		C.recognizer_canceled_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_translation_recognizer_canceled)), nil)
+	}
+	if getSessionStoppedCallback(handle) == nil {
+		C.recognizer_session_stopped_set_callback(handle, (C.PSESSION_CALLBACK_FUNC)(unsafe.Pointer(C.cgo_recognizer_session_stopped)), nil)
+	}
+}
+
 // Close disposes the associated resources.
 func (recognizer TranslationRecognizer) Close() {
+	traceEnd(recognizer.handle)
+	endLimitedSession(recognizer.handle)
 	recognizer.SessionStarted(nil)
 	recognizer.SessionStopped(nil)
 	recognizer.SpeechStartDetected(nil)
@@ -329,3 +467,7 @@ func (recognizer TranslationRecognizer) Close() {
 		recognizer.handle = C.SPXHANDLE_INVALID
 	}
 }
+
+func (recognizer *TranslationRecognizer) recognizerHandle() common.SPXHandle {
+	return handle2uintptr(recognizer.handle)
+}
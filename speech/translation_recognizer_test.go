@@ -382,3 +382,51 @@ func TestTranslationSynthesis(t *testing.T) {
 		t.Error("Didn't receive synthesis event.")
 	}
 }
+
+func TestTranslationRecognizerTargetLanguages(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+
+	recognizer := createTranslationRecognizerFromFileInput(t, "../test_files/turn_on_the_lamp.wav")
+	if recognizer == nil {
+		return
+	}
+	defer recognizer.Close()
+
+	if err := recognizer.AddTargetLanguage("de"); err != nil {
+		t.Error("Got an error adding target language: ", err)
+	}
+	if err := recognizer.RemoveTargetLanguage("fr"); err != nil {
+		t.Error("Got an error removing target language: ", err)
+	}
+	languages := strings.Join(recognizer.GetTargetLanguages(), ",")
+	if !strings.Contains(languages, "de") || strings.Contains(languages, "fr") {
+		t.Error("Unexpected target languages: ", languages)
+	}
+
+	session, err := NewTranslationSession(recognizer)
+	if err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	defer session.Close()
+	stream := session.Stream("de")
+	if stream == nil {
+		t.Fatal("Missing stream for target language de")
+	}
+	if err = session.Start(); err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	final := ""
+	for update := range stream.Updates {
+		if update.Final {
+			final = update.Text
+			break
+		}
+	}
+	if final == "" {
+		t.Error("No final translation received, session error: ", session.Err())
+	}
+	if err = session.Stop(); err != nil {
+		t.Error("Got an error: ", err)
+	}
+}
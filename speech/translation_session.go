@@ -0,0 +1,523 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/diagnostics/logging"
+)
+
+// TranslationUpdate is a partial or final translation of an utterance into one target language.
+type TranslationUpdate struct {
+	// Language is the target language of the translation.
+	Language string
+
+	// ResultID is the identifier of the recognition result the translation belongs to.
+	ResultID string
+
+	// Text is the translated text.
+	Text string
+
+	// SourceText is the recognized text in the source language.
+	SourceText string
+
+	// Offset is the offset of the utterance in the audio stream.
+	Offset time.Duration
+
+	// Duration is the duration of the utterance.
+	Duration time.Duration
+
+	// Final is true for the final translation of the utterance, and false for intermediate translations.
+	Final bool
+}
+
+// TranslationAudio is the synthesized audio of one translated utterance. Reads block until more audio is received,
+// and return io.EOF once synthesis of the utterance is completed.
+type TranslationAudio struct {
+	// Language is the target language of the synthesized audio.
+	Language string
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	data   bytes.Buffer
+	closed bool
+}
+
+func newTranslationAudio(language string) *TranslationAudio {
+	audio := &TranslationAudio{Language: language}
+	audio.cond = sync.NewCond(&audio.mu)
+	return audio
+}
+
+// Read reads synthesized audio data, implementing io.Reader.
+func (audio *TranslationAudio) Read(p []byte) (int, error) {
+	audio.mu.Lock()
+	defer audio.mu.Unlock()
+	for audio.data.Len() == 0 && !audio.closed {
+		audio.cond.Wait()
+	}
+	if audio.data.Len() == 0 {
+		return 0, io.EOF
+	}
+	return audio.data.Read(p)
+}
+
+func (audio *TranslationAudio) write(p []byte) {
+	audio.mu.Lock()
+	defer audio.mu.Unlock()
+	audio.data.Write(p)
+	audio.cond.Broadcast()
+}
+
+func (audio *TranslationAudio) close() {
+	audio.mu.Lock()
+	defer audio.mu.Unlock()
+	audio.closed = true
+	audio.cond.Broadcast()
+}
+
+// TranslationStream carries the translations and synthesized audio of one target language of a TranslationSession.
+// Both channels are closed when the session ends, the language is removed or the session is closed.
+type TranslationStream struct {
+	// Language is the target language of the stream.
+	Language string
+
+	// Updates receives the partial and final translations, in the order they are recognized.
+	Updates <-chan TranslationUpdate
+
+	// Audio receives one reader per synthesized utterance, if synthesis is enabled for this language.
+	Audio <-chan *TranslationAudio
+
+	updates *eventQueue
+	audio   *eventQueue
+}
+
+// eventQueue is an unbounded queue feeding a channel, so that native callbacks never block on slow consumers.
+type eventQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	items  []interface{}
+	closed bool
+}
+
+func newEventQueue() *eventQueue {
+	queue := new(eventQueue)
+	queue.cond = sync.NewCond(&queue.mu)
+	return queue
+}
+
+func (queue *eventQueue) push(item interface{}) bool {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	if queue.closed {
+		return false
+	}
+	queue.items = append(queue.items, item)
+	queue.cond.Signal()
+	return true
+}
+
+func (queue *eventQueue) pop() (interface{}, bool) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	for len(queue.items) == 0 && !queue.closed {
+		queue.cond.Wait()
+	}
+	if len(queue.items) == 0 {
+		return nil, false
+	}
+	item := queue.items[0]
+	queue.items[0] = nil
+	queue.items = queue.items[1:]
+	return item, true
+}
+
+func (queue *eventQueue) close() {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.closed = true
+	queue.cond.Broadcast()
+}
+
+func newTranslationStream(language string, abort <-chan struct{}) *TranslationStream {
+	updates := make(chan TranslationUpdate)
+	audio := make(chan *TranslationAudio)
+	stream := &TranslationStream{
+		Language: language,
+		Updates:  updates,
+		Audio:    audio,
+		updates:  newEventQueue(),
+		audio:    newEventQueue(),
+	}
+	go func() {
+		defer close(updates)
+		for {
+			item, ok := stream.updates.pop()
+			if !ok {
+				return
+			}
+			select {
+			case updates <- item.(TranslationUpdate):
+			case <-abort:
+				return
+			}
+		}
+	}()
+	go func() {
+		defer close(audio)
+		for {
+			item, ok := stream.audio.pop()
+			if !ok {
+				return
+			}
+			select {
+			case audio <- item.(*TranslationAudio):
+			case <-abort:
+				return
+			}
+		}
+	}()
+	return stream
+}
+
+func (stream *TranslationStream) close() {
+	stream.updates.close()
+	stream.audio.close()
+}
+
+// TranslationSession fans the events of a translation recognizer out into one TranslationStream per target language.
+type TranslationSession struct {
+	recognizer        *TranslationRecognizer
+	start             func() error
+	stop              func() error
+	mu                sync.Mutex
+	streams           map[string]*TranslationStream
+	languages         []string
+	synthesisLanguage string
+	currentAudio      *TranslationAudio
+	running           bool
+	finished          bool
+	err               error
+	done              chan struct{}
+	abort             chan struct{}
+	closeOnce         sync.Once
+}
+
+// NewTranslationSession creates a session with one stream per target language of the recognizer, as configured by
+// SpeechTranslationConfig.AddTargetLanguage. The session registers the Recognizing, Recognized, Synthesizing, Canceled
+// and SessionStopped handlers of the recognizer, replacing any handlers previously registered for these events.
+// Synthesized audio is routed to the target language matching the voice set with SpeechTranslationConfig.SetVoiceName,
+// or to the first target language when no language matches; use SetSynthesisLanguage to choose another one.
+func NewTranslationSession(recognizer *TranslationRecognizer) (*TranslationSession, error) {
+	if recognizer == nil {
+		return nil, fmt.Errorf("translation recognizer is nil")
+	}
+	voice := recognizer.Properties.GetProperty(common.SpeechServiceConnectionTranslationVoice, "")
+	session := newTranslationSession(recognizer.GetTargetLanguages(), voice)
+	session.recognizer = recognizer
+	session.start = func() error { return <-recognizer.StartContinuousRecognitionAsync() }
+	session.stop = func() error { return <-recognizer.StopContinuousRecognitionAsync() }
+	recognizer.Recognizing(func(event TranslationRecognitionEventArgs) {
+		defer event.Close()
+		session.onRecognition(event, false)
+	})
+	recognizer.Recognized(func(event TranslationRecognitionEventArgs) {
+		defer event.Close()
+		session.onRecognition(event, true)
+	})
+	recognizer.Synthesizing(func(event TranslationSynthesisEventArgs) {
+		defer event.Close()
+		session.onSynthesis(event)
+	})
+	recognizer.Canceled(func(event TranslationRecognitionCanceledEventArgs) {
+		defer event.Close()
+		session.onCanceled(event)
+	})
+	recognizer.SessionStopped(func(event SessionEventArgs) {
+		defer event.Close()
+		session.finish()
+	})
+	return session, nil
+}
+
+func newTranslationSession(languages []string, voice string) *TranslationSession {
+	session := &TranslationSession{
+		streams: make(map[string]*TranslationStream),
+		done:    make(chan struct{}),
+		abort:   make(chan struct{}),
+	}
+	for _, language := range languages {
+		session.addStream(language)
+	}
+	session.synthesisLanguage = matchSynthesisLanguage(session.languages, voice)
+	return session
+}
+
+// matchSynthesisLanguage picks the target language whose tag prefixes the voice name (e.g. de-DE-KatjaNeural matches
+// de-DE or de), falling back to the first language.
+func matchSynthesisLanguage(languages []string, voice string) string {
+	voice = strings.ToLower(voice)
+	best := ""
+	for _, language := range languages {
+		if strings.HasPrefix(voice, strings.ToLower(language)+"-") && len(language) > len(best) {
+			best = language
+		}
+	}
+	if best == "" && len(languages) > 0 {
+		best = languages[0]
+	}
+	return best
+}
+
+func (session *TranslationSession) addStream(language string) *TranslationStream {
+	key := strings.ToLower(language)
+	if stream, ok := session.streams[key]; ok {
+		return stream
+	}
+	stream := newTranslationStream(language, session.abort)
+	if session.finished {
+		stream.close()
+	}
+	session.streams[key] = stream
+	session.languages = append(session.languages, language)
+	return stream
+}
+
+// Stream returns the stream of a target language, or nil if the language is not a target language of the session.
+func (session *TranslationSession) Stream(language string) *TranslationStream {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.streams[strings.ToLower(language)]
+}
+
+// Languages returns the current target languages of the session.
+func (session *TranslationSession) Languages() []string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return append([]string(nil), session.languages...)
+}
+
+// SetSynthesisLanguage sets the target language that receives the synthesized audio.
+func (session *TranslationSession) SetSynthesisLanguage(language string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.synthesisLanguage = language
+}
+
+// AddTargetLanguage adds a target language to the running recognizer and returns its new stream.
+func (session *TranslationSession) AddTargetLanguage(language string) (*TranslationStream, error) {
+	if session.recognizer != nil {
+		if err := session.recognizer.AddTargetLanguage(language); err != nil {
+			return nil, err
+		}
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.addStream(language), nil
+}
+
+// RemoveTargetLanguage removes a target language from the running recognizer and closes its stream.
+func (session *TranslationSession) RemoveTargetLanguage(language string) error {
+	if session.recognizer != nil {
+		if err := session.recognizer.RemoveTargetLanguage(language); err != nil {
+			return err
+		}
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	key := strings.ToLower(language)
+	stream, ok := session.streams[key]
+	if !ok {
+		return nil
+	}
+	delete(session.streams, key)
+	for i, l := range session.languages {
+		if strings.ToLower(l) == key {
+			session.languages = append(session.languages[:i], session.languages[i+1:]...)
+			break
+		}
+	}
+	stream.close()
+	return nil
+}
+
+// Start starts continuous recognition and returns once it is started. When the session is started again after it
+// stopped, each target language gets a new stream, so callers must fetch the streams and Done channel again.
+// Start fails once the session is closed.
+func (session *TranslationSession) Start() error {
+	if err := session.reset(); err != nil {
+		return err
+	}
+	if err := session.start(); err != nil {
+		session.mu.Lock()
+		session.running = false
+		session.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// reset prepares a stopped session for another run, with a new stream per target language.
+func (session *TranslationSession) reset() error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	select {
+	case <-session.abort:
+		return fmt.Errorf("translation session is closed")
+	default:
+	}
+	session.running = true
+	if !session.finished {
+		return nil
+	}
+	session.finished = false
+	session.err = nil
+	session.done = make(chan struct{})
+	for key, stream := range session.streams {
+		session.streams[key] = newTranslationStream(stream.Language, session.abort)
+	}
+	return nil
+}
+
+// Stop stops continuous recognition and returns once the session is stopped and its streams are closed.
+func (session *TranslationSession) Stop() error {
+	session.mu.Lock()
+	running, done := session.running, session.done
+	session.mu.Unlock()
+	if err := session.stop(); err != nil {
+		return err
+	}
+	if running {
+		// The run ends with its SessionStopped event, which follows the stop. Waiting for it keeps the event from
+		// ending the next run when Start follows.
+		select {
+		case <-done:
+		case <-session.abort:
+		}
+	}
+	return nil
+}
+
+// Done returns a channel that is closed when the session stops or is canceled.
+func (session *TranslationSession) Done() <-chan struct{} {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.done
+}
+
+// Err returns the cancellation error of the session, if it was canceled because of an error.
+func (session *TranslationSession) Err() error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.err
+}
+
+// Close detaches the session from the recognizer and closes all streams, discarding undelivered updates.
+// It does not close the recognizer.
+func (session *TranslationSession) Close() {
+	session.closeOnce.Do(func() {
+		if session.recognizer != nil {
+			session.recognizer.Recognizing(nil)
+			session.recognizer.Recognized(nil)
+			session.recognizer.Synthesizing(nil)
+			session.recognizer.Canceled(nil)
+			session.recognizer.SessionStopped(nil)
+		}
+		close(session.abort)
+		session.finish()
+	})
+}
+
+func (session *TranslationSession) onRecognition(event TranslationRecognitionEventArgs, final bool) {
+	result := event.Result
+	if result == nil {
+		return
+	}
+	if final && result.Reason != common.TranslatedSpeech {
+		return
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	for language, text := range result.GetTranslations() {
+		stream, ok := session.streams[strings.ToLower(language)]
+		if !ok {
+			continue
+		}
+		stream.updates.push(TranslationUpdate{
+			Language:   stream.Language,
+			ResultID:   result.ResultID,
+			Text:       text,
+			SourceText: result.Text,
+			Offset:     result.Offset,
+			Duration:   result.Duration,
+			Final:      final,
+		})
+	}
+}
+
+func (session *TranslationSession) onSynthesis(event TranslationSynthesisEventArgs) {
+	if event.Result == nil {
+		return
+	}
+	data := event.Result.GetAudioData()
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if len(data) == 0 || event.Result.Reason == common.SynthesizingAudioCompleted {
+		// An empty chunk marks the end of the synthesized audio of an utterance.
+		if session.currentAudio != nil {
+			session.currentAudio.close()
+			session.currentAudio = nil
+		}
+		return
+	}
+	if session.currentAudio == nil {
+		stream, ok := session.streams[strings.ToLower(session.synthesisLanguage)]
+		if !ok {
+			return
+		}
+		session.currentAudio = newTranslationAudio(stream.Language)
+		if !stream.audio.push(session.currentAudio) {
+			session.currentAudio = nil
+			return
+		}
+	}
+	session.currentAudio.write(data)
+}
+
+func (session *TranslationSession) onCanceled(event TranslationRecognitionCanceledEventArgs) {
+	if event.Reason == common.Error {
+		session.mu.Lock()
+		session.err = event.Err()
+		if event.SessionLog != nil {
+			session.err = &logging.SessionLogError{Err: session.err, Log: *event.SessionLog}
+		}
+		session.mu.Unlock()
+	}
+	session.finish()
+}
+
+func (session *TranslationSession) finish() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.finished {
+		return
+	}
+	session.running = false
+	session.finished = true
+	if session.currentAudio != nil {
+		session.currentAudio.close()
+		session.currentAudio = nil
+	}
+	for _, stream := range session.streams {
+		stream.close()
+	}
+	close(session.done)
+}
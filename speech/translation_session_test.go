@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package speech
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
+)
+
+func createTranslationEvent(reason common.ResultReason, text string, translations map[string]string) TranslationRecognitionEventArgs {
+	result := new(TranslationRecognitionResult)
+	result.ResultID = "result"
+	result.Reason = reason
+	result.Text = text
+	result.Offset = time.Second
+	result.translations = translations
+	return TranslationRecognitionEventArgs{Result: result}
+}
+
+func receiveUpdate(t *testing.T, stream *TranslationStream) TranslationUpdate {
+	select {
+	case update := <-stream.Updates:
+		return update
+	case <-time.After(timeout):
+		t.Fatal("Timeout waiting for translation update.")
+	}
+	return TranslationUpdate{}
+}
+
+func TestTranslationSessionFanOut(t *testing.T) {
+	session := newTranslationSession([]string{"de", "fr"}, "fr-FR-DeniseNeural")
+	defer session.Close()
+	de := session.Stream("DE")
+	fr := session.Stream("fr")
+	if de == nil || fr == nil || session.Stream("it") != nil {
+		t.Fatal("Unexpected streams for languages ", session.Languages())
+	}
+
+	session.onRecognition(createTranslationEvent(common.TranslatingSpeech, "hello", map[string]string{"de": "hallo", "fr": "bonjour"}), false)
+	session.onRecognition(createTranslationEvent(common.TranslatedSpeech, "hello world", map[string]string{"de": "hallo Welt", "fr": "bonjour le monde"}), true)
+	session.onRecognition(createTranslationEvent(common.NoMatch, "", nil), true)
+
+	update := receiveUpdate(t, de)
+	if update.Final || update.Text != "hallo" || update.SourceText != "hello" || update.Offset != time.Second {
+		t.Error("Unexpected partial update: ", update)
+	}
+	update = receiveUpdate(t, de)
+	if !update.Final || update.Text != "hallo Welt" || update.Language != "de" {
+		t.Error("Unexpected final update: ", update)
+	}
+	if update = receiveUpdate(t, fr); update.Text != "bonjour" {
+		t.Error("Unexpected partial update: ", update)
+	}
+
+	session.onSynthesis(TranslationSynthesisEventArgs{Result: &TranslationSynthesisResult{Reason: common.SynthesizingAudio, audioData: []byte{1, 2}}})
+	session.onSynthesis(TranslationSynthesisEventArgs{Result: &TranslationSynthesisResult{Reason: common.SynthesizingAudio, audioData: []byte{3}}})
+	session.onSynthesis(TranslationSynthesisEventArgs{Result: &TranslationSynthesisResult{Reason: common.SynthesizingAudioCompleted}})
+	select {
+	case audio := <-fr.Audio:
+		data, err := ioutil.ReadAll(audio)
+		if err != nil || len(data) != 3 || audio.Language != "fr" {
+			t.Error("Unexpected synthesized audio: ", data, err)
+		}
+	case <-time.After(timeout):
+		t.Error("Timeout waiting for synthesized audio.")
+	}
+}
+
+func TestTranslationSessionAddRemoveLanguage(t *testing.T) {
+	session := newTranslationSession([]string{"de"}, "")
+	defer session.Close()
+	it, err := session.AddTargetLanguage("it")
+	if err != nil || it == nil {
+		t.Fatal("Got an error: ", err)
+	}
+	if err = session.RemoveTargetLanguage("de"); err != nil {
+		t.Error("Got an error: ", err)
+	}
+	if len(session.Languages()) != 1 || session.Languages()[0] != "it" {
+		t.Error("Unexpected languages: ", session.Languages())
+	}
+	session.onRecognition(createTranslationEvent(common.TranslatedSpeech, "hello", map[string]string{"de": "hallo", "it": "ciao"}), true)
+	if update := receiveUpdate(t, it); update.Text != "ciao" {
+		t.Error("Unexpected update: ", update)
+	}
+
+	session.onCanceled(TranslationRecognitionCanceledEventArgs{Reason: common.Error, ErrorCode: common.ConnectionFailure})
+	select {
+	case <-session.Done():
+	case <-time.After(timeout):
+		t.Error("Timeout waiting for session end.")
+	}
+	if session.Err() == nil {
+		t.Error("Expected a cancellation error")
+	}
+	if _, ok := <-it.Updates; ok {
+		t.Error("Expected the stream to be closed")
+	}
+}
+
+func TestTranslationSessionRestart(t *testing.T) {
+	session := newTranslationSession([]string{"de"}, "")
+	session.finish()
+	<-session.Done()
+	if err := session.reset(); err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	select {
+	case <-session.Done():
+		t.Error("Expected the restarted session to be running")
+	default:
+	}
+	de := session.Stream("de")
+	session.onRecognition(createTranslationEvent(common.TranslatedSpeech, "hello", map[string]string{"de": "hallo"}), true)
+	if update := receiveUpdate(t, de); update.Text != "hallo" {
+		t.Error("Unexpected update: ", update)
+	}
+	session.Close()
+	if err := session.reset(); err == nil {
+		t.Error("Expected an error restarting a closed session")
+	}
+}
+
+func TestTranslationSessionStopWaitsForSessionStopped(t *testing.T) {
+	session := newTranslationSession([]string{"de"}, "")
+	session.start = func() error { return nil }
+	session.stop = func() error {
+		// The SessionStopped event arrives after the recognition is stopped.
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			session.finish()
+		}()
+		return nil
+	}
+	if err := session.Start(); err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	if err := session.Stop(); err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	select {
+	case <-session.Done():
+	default:
+		t.Error("Expected the session to be stopped when Stop returns")
+	}
+	if err := session.Start(); err != nil {
+		t.Fatal("Got an error: ", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	select {
+	case <-session.Done():
+		t.Error("Expected the restarted session to be running")
+	default:
+	}
+	de := session.Stream("de")
+	session.onRecognition(createTranslationEvent(common.TranslatedSpeech, "hello", map[string]string{"de": "hallo"}), true)
+	if update := receiveUpdate(t, de); update.Text != "hallo" {
+		t.Error("Unexpected update: ", update)
+	}
+	session.Close()
+}
@@ -4,6 +4,7 @@
 package speech
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
@@ -45,6 +46,18 @@ type VoiceInfo struct {
 	// VoicePath specifies the voice path
 	VoicePath string
 
+	// SecondaryLocales specifies the additional locales the voice can speak, if reported by the service.
+	SecondaryLocales []string
+
+	// RolePlayList specifies the roles the voice can play, if reported by the service.
+	RolePlayList []string
+
+	// SampleRateHertz specifies the native sample rate of the voice, or 0 if not reported.
+	SampleRateHertz int
+
+	// WordsPerMinute specifies the average speaking rate of the voice, or 0 if not reported.
+	WordsPerMinute int
+
 	// Collection of additional properties.
 	Properties *common.PropertyCollection
 }
@@ -105,5 +118,17 @@ func NewVoiceInfoFromHandle(handle common.SPXHandle) (*VoiceInfo, error) {
 	} else {
 		voiceInfo.Gender = common.GenderUnknown
 	}
+	voiceInfo.SecondaryLocales = splitVoiceList(voiceInfo.Properties.GetPropertyByString("SecondaryLocaleList", ""))
+	voiceInfo.RolePlayList = splitVoiceList(voiceInfo.Properties.GetPropertyByString("RolePlayList", ""))
+	voiceInfo.SampleRateHertz, _ = strconv.Atoi(voiceInfo.Properties.GetPropertyByString("SampleRateHertz", "0"))
+	voiceInfo.WordsPerMinute, _ = strconv.Atoi(voiceInfo.Properties.GetPropertyByString("WordsPerMinute", "0"))
 	return voiceInfo, nil
 }
+
+// splitVoiceList splits a "|" separated list property, returning nil for an empty value.
+func splitVoiceList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, "|")
+}
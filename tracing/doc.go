@@ -0,0 +1,12 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+// Package tracing creates trace spans for recognition sessions, utterances and synthesis requests.
+// Tracing is disabled until a Tracer is registered with SetTracer. The SDK does not depend on OpenTelemetry;
+// applications bridge their tracer with a small adapter implementing Tracer and Span.
+//
+// The speech package then creates a speech.session span from SessionStarted to SessionStopped, a speech.utterance
+// span from SpeechStartDetected to the final result or cancellation, and a speech.synthesis span per synthesis
+// request. The spans are children of the context passed to the WithContext variants of the start methods, e.g.
+// SpeechRecognizer.StartContinuousRecognitionWithContextAsync.
+package tracing
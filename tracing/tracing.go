@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft. All rights reserved.
+// Licensed under the MIT license. See LICENSE.md file in the project root for full license information.
+
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+// Span names.
+const (
+	// SessionSpan covers a recognition session, from SessionStarted to SessionStopped.
+	SessionSpan = "speech.session"
+
+	// UtteranceSpan covers an utterance, from SpeechStartDetected to its final result or cancellation.
+	UtteranceSpan = "speech.utterance"
+
+	// SynthesisSpan covers a synthesis request.
+	SynthesisSpan = "speech.synthesis"
+)
+
+// Attribute keys.
+const (
+	AttrRecognizer            = "speech.recognizer"
+	AttrSessionID             = "speech.session.id"
+	AttrResultID              = "speech.result.id"
+	AttrReason                = "speech.result.reason"
+	AttrOffsetMs              = "speech.result.offset_ms"
+	AttrDurationMs            = "speech.result.duration_ms"
+	AttrLanguage              = "speech.language"
+	AttrTargetLanguages       = "speech.translation.target_languages"
+	AttrRecognitionLatencyMs  = "speech.recognition.latency_ms"
+	AttrCancellationReason    = "speech.cancellation.reason"
+	AttrCancellationErrorCode = "speech.cancellation.error_code"
+	AttrCancellationDetails   = "speech.cancellation.details"
+	AttrVoice                 = "speech.synthesis.voice"
+	AttrFirstByteLatencyMs    = "speech.synthesis.first_byte_latency_ms"
+	AttrFinishLatencyMs       = "speech.synthesis.finish_latency_ms"
+	AttrNetworkLatencyMs      = "speech.synthesis.network_latency_ms"
+	AttrServiceLatencyMs      = "speech.synthesis.service_latency_ms"
+)
+
+// Attribute is a key-value pair attached to a span. Value is a string, an int64 or a float64.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String creates a string attribute.
+func String(key string, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int64 creates an integer attribute.
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Float64 creates a floating point attribute.
+func Float64(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttributes adds attributes to the span.
+	SetAttributes(attributes ...Attribute)
+
+	// SetError marks the span as failed with the given error.
+	SetError(err error)
+
+	// End ends the span.
+	End()
+}
+
+// Tracer starts spans. Implementations must be safe for concurrent use, as they are called from the SDK callback
+// threads. An adapter for go.opentelemetry.io/otel/trace looks like:
+//
+//	type tracer struct{ trace.Tracer }
+//
+//	func (t tracer) Start(ctx context.Context, name string, attributes ...tracing.Attribute) (context.Context, tracing.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name, trace.WithAttributes(toKeyValues(attributes)...))
+//		return ctx, otelSpan{span}
+//	}
+//
+// where otelSpan forwards SetAttributes to span.SetAttributes, SetError to span.RecordError and span.SetStatus,
+// and End to span.End.
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, if any, and returns a context holding the new span.
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+var (
+	tracerMu sync.RWMutex
+	tracer   Tracer
+)
+
+// SetTracer sets the tracer creating the spans of the SDK. Pass nil to disable tracing.
+func SetTracer(t Tracer) {
+	tracerMu.Lock()
+	defer tracerMu.Unlock()
+	tracer = t
+}
+
+// CurrentTracer returns the tracer set with SetTracer, or nil.
+func CurrentTracer() Tracer {
+	tracerMu.RLock()
+	defer tracerMu.RUnlock()
+	return tracer
+}